@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// Authenticator provides the bearer token of the API requests. It is called for every request,
+// so implementations can hand out short-lived tokens and refresh them, e.g. with OAuth.
+type Authenticator interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticKeyAuthenticator authenticates every request with the same API key, the default
+type StaticKeyAuthenticator string
+
+// Token returns the API key
+func (k StaticKeyAuthenticator) Token(context.Context) (string, error) {
+	return string(k), nil
+}
+
+// authorization returns the Authorization header of a request
+func (c *Client) authorization(ctx context.Context) (string, error) {
+	token, err := c.authenticator.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not get an API token: %w", err)
+	}
+	return fmt.Sprintf("bearer %v", token), nil
+}
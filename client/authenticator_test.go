@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshingAuthenticator hands out a new token for every request, like a short-lived OAuth
+// token that is refreshed each time
+type refreshingAuthenticator struct {
+	mu      sync.Mutex
+	refresh int
+	err     error
+}
+
+func (a *refreshingAuthenticator) Token(context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return "", a.err
+	}
+	a.refresh++
+	return fmt.Sprintf("token-%d", a.refresh), nil
+}
+
+func TestAuthenticator(t *testing.T) {
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(`{"data": []}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+
+	c := NewClient("api-key", "org-name", "public")
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	assert.Equal(t, []string{"bearer api-key"}, authorizations, "the API key is used by default")
+
+	authorizations = nil
+	auth := &refreshingAuthenticator{}
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{Authenticator: auth})
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	_, err := c.ListStreams(context.Background(), "tacoman")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bearer token-1", "bearer token-2"}, authorizations, "tokens rotate across requests")
+
+	authorizations = nil
+	auth.err = errors.New("refresh token expired")
+	err = c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	assert.EqualError(t, err, "could not get an API token: refresh token expired")
+	assert.Empty(t, authorizations, "no request is sent without a token")
+}
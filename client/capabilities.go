@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Capabilities lists the chart types, alert operators and display units supported by the API
+type Capabilities struct {
+	ChartTypes []string `json:"chart-types"`
+	Operators  []string `json:"operators"`
+	Units      []string `json:"units"`
+}
+
+// ErrCapabilitiesUnavailable is returned by GetCapabilities when the API doesn't report its
+// capabilities
+var ErrCapabilitiesUnavailable = errors.New("the API doesn't report its capabilities")
+
+// capabilitiesCache holds the capabilities of the API once a GetCapabilities call succeeded,
+// or whether the API doesn't have them
+type capabilitiesCache struct {
+	mu           sync.Mutex
+	capabilities *Capabilities
+	unavailable  bool
+}
+
+// GetCapabilities returns the capabilities of the API. They don't change while the provider
+// runs, so they are cached for the lifetime of the client once fetched. So is a 404, after which
+// ErrCapabilitiesUnavailable is returned without calling the API. Other errors, e.g. a cancelled
+// context, aren't cached and the next call tries again.
+func (c *Client) GetCapabilities(ctx context.Context) (*Capabilities, error) {
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	if c.capabilities.capabilities != nil {
+		return c.capabilities.capabilities, nil
+	}
+	if c.capabilities.unavailable {
+		return nil, ErrCapabilitiesUnavailable
+	}
+
+	var resp genericAPIResponse[struct {
+		Attributes Capabilities `json:"attributes"`
+	}]
+	if err := c.CallAPI(ctx, "GET", "capabilities", nil, &resp); err != nil {
+		if apiErr, ok := err.(APIResponseCarrier); ok && apiErr.GetStatusCode() == http.StatusNotFound {
+			c.capabilities.unavailable = true
+			return nil, ErrCapabilitiesUnavailable
+		}
+		return nil, err
+	}
+	c.capabilities.capabilities = &resp.Data.Attributes
+	return c.capabilities.capabilities, nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetCapabilities(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/public/v0.2/blars/capabilities", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": {"attributes": {
+			"chart-types": ["timeseries", "heatmap"],
+			"operators": ["above", "below", "outside"],
+			"units": ["B", "KB", "MB"]
+		}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	for i := 0; i < 2; i++ {
+		capabilities, err := c.GetCapabilities(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &Capabilities{
+			ChartTypes: []string{"timeseries", "heatmap"},
+			Operators:  []string{"above", "below", "outside"},
+			Units:      []string{"B", "KB", "MB"},
+		}, capabilities)
+	}
+	assert.Equal(t, 1, calls, "capabilities are cached")
+}
+
+func Test_GetCapabilities_unavailable(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	for i := 0; i < 2; i++ {
+		capabilities, err := c.GetCapabilities(context.Background())
+		assert.ErrorIs(t, err, ErrCapabilitiesUnavailable)
+		assert.Nil(t, capabilities)
+	}
+	assert.Equal(t, 1, calls, "a missing endpoint is cached")
+}
+
+func Test_GetCapabilities_transientError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, err := w.Write([]byte(`{"data": {"attributes": {"chart-types": ["timeseries"]}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.GetCapabilities(ctx)
+	require.Error(t, err)
+
+	capabilities, err := c.GetCapabilities(context.Background())
+	require.NoError(t, err, "the error of a cancelled context isn't cached")
+	assert.Equal(t, []string{"timeseries"}, capabilities.ChartTypes)
+	assert.Equal(t, 1, calls)
+}
@@ -1,8 +1,18 @@
 package client
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 func TestNew_public(t *testing.T) {
@@ -24,3 +34,614 @@ func TestNew_env_var_provided_baseURL(t *testing.T) {
 	c := NewClient("api-key", "org-name", "public")
 	assert.Equal(t, "http://localhost:8080/public/v0.2/org-name", c.baseURL)
 }
+
+func TestNew_env_var_provided_public_envs(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_PUBLIC_ENVS", "sandbox, public-test,")
+
+	c := NewClient("api-key", "org-name", "public-test")
+	assert.Equal(t, "https://api.lightstep.com/public/v0.2/org-name", c.baseURL)
+
+	c = NewClient("api-key", "org-name", "sandbox")
+	assert.Equal(t, "https://api.lightstep.com/public/v0.2/org-name", c.baseURL)
+
+	c = NewClient("api-key", "org-name", "staging")
+	assert.Equal(t, "https://api-staging.lightstep.com/public/v0.2/org-name", c.baseURL)
+}
+
+func TestEnvFromEnvironment(t *testing.T) {
+	t.Setenv("LIGHTSTEP_ENV", "")
+	assert.Equal(t, "public", EnvFromEnvironment())
+
+	t.Setenv("LIGHTSTEP_ENV", " staging ")
+	assert.Equal(t, "staging", EnvFromEnvironment())
+}
+
+func TestCheckEnv(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_BASE_URL", "")
+	t.Setenv("LIGHTSTEP_API_PUBLIC_ENVS", "sandbox")
+
+	for _, env := range []string{"public", "meta", "staging", "sandbox"} {
+		assert.NoError(t, CheckEnv(env), env)
+	}
+	assert.EqualError(t, CheckEnv("prod"),
+		`environment "prod" is not one of public, meta, staging, so the API is called at https://api-prod.lightstep.com`)
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", "http://localhost:8080")
+	assert.NoError(t, CheckEnv("prod"), "the environment doesn't matter with a base URL")
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_READ_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_WRITE_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "")
+	t.Setenv("LIGHTSTEP_API_TIMEOUT_SECONDS", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_WAIT_MIN_SECONDS", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_WAIT_MAX_SECONDS", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_TIMEOUT_SECONDS", "")
+
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Equal(t, rate.Limit(DefaultRateLimitPerSecond), c.readRateLimiter.Limit())
+	assert.Equal(t, rate.Limit(DefaultRateLimitPerSecond), c.writeRateLimiter.Limit())
+	assert.Equal(t, 4, c.client.RetryMax)
+	assert.Equal(t, DefaultTimeoutSeconds*time.Second, c.client.HTTPClient.Timeout)
+	assert.Equal(t, DefaultRetryWaitMinSeconds*time.Second, c.client.RetryWaitMin)
+	assert.Equal(t, DefaultRetryWaitMaxSeconds*time.Second, c.client.RetryWaitMax)
+
+	t.Setenv("LIGHTSTEP_API_RATE_LIMIT", "5")
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "0")
+	t.Setenv("LIGHTSTEP_API_TIMEOUT_SECONDS", "30")
+	t.Setenv("LIGHTSTEP_API_RETRY_WAIT_MIN_SECONDS", "2")
+
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Equal(t, rate.Limit(5), c.readRateLimiter.Limit())
+	assert.Equal(t, rate.Limit(5), c.writeRateLimiter.Limit())
+	assert.Equal(t, 0, c.client.RetryMax)
+	assert.Equal(t, NoRetries, c.Options().RetryMax)
+	assert.Equal(t, 30*time.Second, c.client.HTTPClient.Timeout)
+	assert.Equal(t, 2*time.Second, c.client.RetryWaitMin)
+
+	// explicit options take precedence over the env vars
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{
+		RateLimitPerSecond:  10,
+		RetryMax:            2,
+		TimeoutSeconds:      15,
+		RetryWaitMinSeconds: 3,
+	})
+	assert.Equal(t, rate.Limit(10), c.readRateLimiter.Limit())
+	assert.Equal(t, rate.Limit(10), c.writeRateLimiter.Limit())
+	assert.Equal(t, 2, c.client.RetryMax)
+	assert.Equal(t, 15*time.Second, c.client.HTTPClient.Timeout)
+	assert.Equal(t, ClientOptions{
+		UserAgent:               c.userAgent,
+		RateLimitPerSecond:      10,
+		ReadRateLimitPerSecond:  10,
+		WriteRateLimitPerSecond: 10,
+		RetryMax:                2,
+		TimeoutSeconds:          15,
+		RetryWaitMinSeconds:     3,
+		RetryWaitMaxSeconds:     DefaultRetryWaitMaxSeconds,
+		RetryTimeoutSeconds:     DefaultRetryTimeoutSeconds,
+		KeepAliveSeconds:        DefaultKeepAliveSeconds,
+		RedirectPolicy:          RedirectSameHost,
+		MaxResponseBytes:        DefaultMaxResponseBytes,
+	}, c.Options())
+
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "3")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{RetryMax: NoRetries})
+	assert.Equal(t, 0, c.client.RetryMax, "takes precedence over LIGHTSTEP_API_RETRY_MAX")
+}
+
+func TestRateLimitJitter(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_RATE_LIMIT_JITTER_MS", "")
+
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Equal(t, 0, c.Options().RateLimitJitterMs, "no jitter by default")
+
+	t.Setenv("LIGHTSTEP_API_RATE_LIMIT_JITTER_MS", "250")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Equal(t, 250, c.Options().RateLimitJitterMs)
+
+	// the jitter is cut short when the context is done
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{RateLimitJitterMs: int(time.Hour / time.Millisecond)})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.waitForRateLimit(ctx, http.MethodGet)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"data": "` + strings.Repeat("x", 1024) + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LIGHTSTEP_API_MAX_RESPONSE_BYTES", "")
+
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Equal(t, int64(DefaultMaxResponseBytes), c.Options().MaxResponseBytes)
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+
+	t.Setenv("LIGHTSTEP_API_MAX_RESPONSE_BYTES", "512")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+
+	var tooLarge *ResponseTooLargeError
+	err := c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, int64(512), tooLarge.Limit)
+	assert.EqualError(t, err, "response body exceeds the maximum size of 512 bytes")
+
+	// streamed responses are decoded as they are read, the limit still applies
+	var resp Envelope
+	err = c.callAPIStreaming(context.Background(), "projects", &resp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "response body exceeds the maximum size of 512 bytes")
+}
+
+func TestUserAgentFromEnv(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		_, err := w.Write([]byte(`{"data": {}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LIGHTSTEP_API_USER_AGENT", "")
+
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{UserAgent: "provider/1.0"})
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	assert.Equal(t, "provider/1.0", userAgent)
+
+	t.Setenv("LIGHTSTEP_API_USER_AGENT", " ci-pipeline/42 ")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{UserAgent: "provider/1.0"})
+	assert.Equal(t, "provider/1.0 ci-pipeline/42", c.Options().UserAgent)
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	assert.Equal(t, "provider/1.0 ci-pipeline/42", userAgent)
+}
+
+func TestCallAPIWithResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Location", "/public/v0.2/org-name/projects/p/saved_views/v1")
+		w.Header().Set("X-Unrelated", "ignored")
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte(`{"data": {"id": "v1", "type": "saved_view"}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api-key", "org-name", "public")
+
+	resp, err := CallAPIWithResult[genericAPIResponse[SavedView]](context.Background(), c, "POST", "projects/p/saved_views", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, `"v2"`, resp.Headers.Get("ETag"))
+	assert.Equal(t, "/public/v0.2/org-name/projects/p/saved_views/v1", resp.Headers.Get("Location"))
+	assert.Empty(t, resp.Headers.Get("X-Unrelated"), "only the selected headers are kept")
+	assert.Equal(t, "v1", resp.Result.Data.ID)
+}
+
+func TestSeparateRateLimiters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"data": []}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_READ_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_WRITE_RATE_LIMIT", "")
+
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{
+		RateLimitPerSecond:      5,
+		WriteRateLimitPerSecond: 1,
+	})
+	assert.Equal(t, rate.Limit(5), c.readRateLimiter.Limit(), "defaults to the configured rate")
+	assert.Equal(t, rate.Limit(1), c.writeRateLimiter.Limit())
+
+	// a budget of one call per hour for each class, so a second call of the same class can't
+	// be made before the deadline
+	c.readRateLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	c.writeRateLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	call := func(method string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return c.CallAPI(ctx, method, "projects", nil, nil)
+	}
+
+	require.NoError(t, call("GET"))
+	require.NoError(t, call("POST"), "writes don't draw from the budget used up by reads")
+	require.Error(t, call("GET"), "the read budget is used up")
+	require.Error(t, call("DELETE"), "the write budget is used up")
+}
+
+func TestCallAPIContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// much slower than the deadline of the call, but within the client timeout
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{TimeoutSeconds: 60})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := c.CallAPI(ctx, "GET", "projects", nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "the deadline of the context wins over the client timeout")
+
+	// the rate limiter fails right away if the deadline would pass while waiting
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "")
+	c.readRateLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	require.True(t, c.readRateLimiter.Allow(), "the budget is used up")
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, c.CallAPI(ctx, "GET", "projects", nil, nil), context.DeadlineExceeded)
+}
+
+func TestRetryTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{
+		RetryMax:            1000,
+		RetryWaitMaxSeconds: 1,
+		RetryTimeoutSeconds: 2,
+	})
+	c.client.RetryWaitMin = 100 * time.Millisecond
+
+	start := time.Now()
+	err := c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after retrying for 2s")
+	assert.GreaterOrEqual(t, elapsed, 2*time.Second)
+	assert.Less(t, elapsed, 3*time.Second, "the request gives up at the cap")
+	assert.Greater(t, atomic.LoadInt32(&calls), int32(1), "the request is retried until the cap")
+}
+
+func TestRetryWaitMax(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// much longer than the wait cap
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, err := w.Write([]byte(`{"data": []}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{RetryWaitMaxSeconds: 1})
+
+	start := time.Now()
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	assert.Less(t, time.Since(start), 2*time.Second, "Retry-After is capped by the max wait")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCappedBackoffRetryAfter(t *testing.T) {
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+	min, max := time.Second, time.Minute
+
+	assert.Equal(t, 7*time.Second, cappedBackoff(min, max, 0, response(http.StatusTooManyRequests, "7")))
+	assert.Equal(t, 7*time.Second, cappedBackoff(min, max, 0, response(http.StatusServiceUnavailable, "7")))
+	assert.Equal(t, max, cappedBackoff(min, max, 0, response(http.StatusTooManyRequests, "3600")), "capped by the max wait")
+
+	date := time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)
+	wait := cappedBackoff(min, max, 0, response(http.StatusTooManyRequests, date))
+	assert.Greater(t, wait, 18*time.Second)
+	assert.LessOrEqual(t, wait, 20*time.Second)
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	assert.Zero(t, cappedBackoff(min, max, 0, response(http.StatusTooManyRequests, past)))
+
+	// the exponential backoff is used without a usable header
+	assert.Equal(t, 4*time.Second, cappedBackoff(min, max, 2, response(http.StatusTooManyRequests, "")))
+	assert.Equal(t, 4*time.Second, cappedBackoff(min, max, 2, response(http.StatusTooManyRequests, "soon")))
+	assert.Equal(t, 4*time.Second, cappedBackoff(min, max, 2, response(http.StatusInternalServerError, "7")))
+	assert.Equal(t, 4*time.Second, cappedBackoff(min, max, 2, nil))
+}
+
+func TestNewClientWithOptionsTransport(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_DISABLE_HTTP2", "")
+	t.Setenv("LIGHTSTEP_API_DISABLE_KEEPALIVES", "")
+	t.Setenv("LIGHTSTEP_API_KEEPALIVE_SECONDS", "")
+
+	transport := func(c *Client) *http.Transport {
+		return c.client.HTTPClient.Transport.(*http.Transport)
+	}
+
+	// HTTP/2 and keep-alives are enabled by default
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.True(t, transport(c).ForceAttemptHTTP2)
+	assert.Nil(t, transport(c).TLSNextProto)
+	assert.False(t, transport(c).DisableKeepAlives)
+	assert.Equal(t, DefaultKeepAliveSeconds, c.Options().KeepAliveSeconds)
+
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{
+		DisableHTTP2:      true,
+		DisableKeepAlives: true,
+		KeepAliveSeconds:  5,
+	})
+	assert.False(t, transport(c).ForceAttemptHTTP2)
+	assert.NotNil(t, transport(c).TLSNextProto, "an empty TLSNextProto disables HTTP/2")
+	assert.Empty(t, transport(c).TLSNextProto)
+	assert.True(t, transport(c).DisableKeepAlives)
+	assert.Equal(t, 5, c.Options().KeepAliveSeconds)
+
+	t.Setenv("LIGHTSTEP_API_DISABLE_HTTP2", "true")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.False(t, transport(c).ForceAttemptHTTP2, "falls back to the env var")
+}
+
+func TestProxyURL(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL of the API
+		proxied = append(proxied, r.URL.String())
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", r.Header.Get("Proxy-Authorization"))
+		_, err := w.Write([]byte(`{"data": {}}`))
+		assert.NoError(t, err)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", "http://api.lightstep.test")
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:password@", 1)
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{ProxyURL: proxyURL})
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	assert.Equal(t, []string{"http://api.lightstep.test/public/v0.2/org-name/projects"}, proxied)
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"data": {}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	t.Setenv("LIGHTSTEP_API_INSECURE", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "0")
+
+	// the self-signed certificate of the server is rejected by default
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.False(t, c.Options().InsecureSkipVerify)
+	err := c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate")
+
+	// it takes both the option and the env var to skip the verification
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{InsecureSkipVerify: true})
+	assert.False(t, c.Options().InsecureSkipVerify, "the env var isn't set")
+	require.Error(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+
+	t.Setenv("LIGHTSTEP_API_INSECURE", "true")
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.False(t, c.Options().InsecureSkipVerify, "the option isn't set")
+	require.Error(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+
+	c = NewClientWithOptions("api-key", "org-name", "public", ClientOptions{InsecureSkipVerify: true})
+	assert.True(t, c.Options().InsecureSkipVerify)
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+}
+
+func TestExecuteAPIRequestSuccessCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			_, err := w.Write([]byte(`{"data": {"id": "d1"}}`))
+			assert.NoError(t, err)
+		case "/created-empty":
+			w.WriteHeader(http.StatusCreated)
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api-key", "org-name", "public")
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		data   string
+	}{
+		{path: "/created", status: http.StatusCreated, data: `{"id": "d1"}`},
+		{path: "/created-empty", status: http.StatusCreated},
+		{path: "/no-content", status: http.StatusNoContent},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			headers, err := c.requestHeaders(context.Background())
+			require.NoError(t, err)
+			req, err := createJSONRequest(context.Background(), "POST", server.URL+tc.path, nil, headers)
+			require.NoError(t, err)
+
+			var result Envelope
+			resp, err := executeAPIRequest(context.Background(), c, req, &result)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.Equal(t, tc.data, string(result.Data))
+
+			req, err = createJSONRequest(context.Background(), "GET", server.URL+tc.path, nil, headers)
+			require.NoError(t, err)
+
+			result = Envelope{}
+			_, err = executeStreamingAPIRequest(context.Background(), c, req, &result)
+			require.NoError(t, err, "streamed responses are handled the same way")
+			assert.Equal(t, tc.data, string(result.Data))
+		})
+	}
+}
+
+func TestAPIClientErrorDetails(t *testing.T) {
+	bodies := map[string]string{
+		"/objects": `{"errors": [{"status": "422", "title": "Invalid Attribute", "detail": "query is invalid"}, {"status": "429", "title": "Quota Exceeded"}]}`,
+		"/strings": `{"errors": ["not found"]}`,
+		"/text":    `bad gateway`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(bodies[r.URL.Path]))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api-key", "org-name", "public")
+	headers, err := c.requestHeaders(context.Background())
+	require.NoError(t, err)
+
+	call := func(path string) APIClientError {
+		err := callAPI(context.Background(), c, server.URL+path, "GET", headers, nil, nil)
+		require.Error(t, err)
+		apiErr, ok := err.(APIClientError)
+		require.True(t, ok)
+		assert.Contains(t, apiErr.Message, "status 422", "the message is kept")
+		return apiErr
+	}
+
+	assert.Equal(t, []APIError{
+		{Status: "422", Title: "Invalid Attribute", Detail: "query is invalid"},
+		{Status: "429", Title: "Quota Exceeded"},
+	}, call("/objects").Errors)
+	assert.Equal(t, []APIError{{Detail: "not found"}}, call("/strings").Errors)
+	assert.Nil(t, call("/text").Errors)
+}
+
+func TestRetryAttemptsInError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, err := w.Write([]byte("try again later"))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{RetryMax: 2})
+	c.client.RetryWaitMin = time.Millisecond
+	c.client.RetryWaitMax = time.Millisecond
+
+	err := c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	require.Error(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	assert.Contains(t, err.Error(), "status 503")
+	assert.Contains(t, err.Error(), "after 3 attempt(s)")
+	assert.Contains(t, err.Error(), "try again later")
+
+	apiErr, ok := err.(APIResponseCarrier)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.GetStatusCode(), "the last response is kept")
+
+	// requests failing without a response mention the attempts too
+	server.Close()
+	err = c.CallAPI(context.Background(), "GET", "projects", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after 3 attempt(s)")
+	assert.Equal(t, -1, err.(APIResponseCarrier).GetStatusCode())
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	const streamBody = `{"data": {"id": "s1", "type": "stream", "attributes": {"name": "Checkout"}}}`
+
+	// the moved stream is served by target, which records the Authorization header it got
+	var gotAuth []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		assert.Equal(t, "/moved/s1", r.URL.Path)
+		_, err := w.Write([]byte(streamBody))
+		assert.NoError(t, err)
+	}))
+	defer target.Close()
+
+	for _, tc := range []struct {
+		policy       string
+		crossHost    bool
+		expectedAuth string
+	}{
+		{policy: RedirectSameHost, expectedAuth: "bearer api-key"},
+		{policy: RedirectSameHost, crossHost: true, expectedAuth: ""},
+		{policy: RedirectAll, crossHost: true, expectedAuth: "bearer api-key"},
+	} {
+		t.Run(fmt.Sprintf("%v cross-host=%v", tc.policy, tc.crossHost), func(t *testing.T) {
+			gotAuth = nil
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/moved/s1" {
+					target.Config.Handler.ServeHTTP(w, r)
+					return
+				}
+				location := server.URL + "/moved/s1"
+				if tc.crossHost {
+					location = target.URL + "/moved/s1"
+				}
+				http.Redirect(w, r, location, http.StatusFound)
+			}))
+			defer server.Close()
+
+			t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+			t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+			c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{RedirectPolicy: tc.policy})
+
+			s, err := c.GetStream(context.Background(), "p", "s1")
+			require.NoError(t, err)
+			assert.Equal(t, "Checkout", s.Attributes.Name, "the moved stream is fetched")
+			assert.Equal(t, []string{tc.expectedAuth}, gotAuth)
+		})
+	}
+
+	t.Run(RedirectNone, func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target.URL+"/moved/s1", http.StatusFound)
+		}))
+		defer server.Close()
+
+		t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+		t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+		t.Setenv("LIGHTSTEP_API_REDIRECT_POLICY", RedirectNone)
+		c := NewClient("api-key", "org-name", "public")
+
+		_, err := c.GetStream(context.Background(), "p", "s1")
+		require.Error(t, err)
+		assert.Equal(t, http.StatusFound, err.(APIResponseCarrier).GetStatusCode(), "the redirect isn't followed")
+	})
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// dashboardCache holds the dashboard IDs of each project, so references to many dashboards
+// are checked with one list call per project instead of one read per dashboard
+type dashboardCache struct {
+	mu  sync.Mutex
+	ids map[string]map[string]bool
+}
+
+// UnifiedDashboardsExist reports which of the dashboard IDs still exist in the project, using a
+// single list call
+func (c *Client) UnifiedDashboardsExist(ctx context.Context, projectName string, ids []string) (map[string]bool, error) {
+	listed, err := c.listUnifiedDashboardIDs(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	exist := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		exist[id] = listed[id]
+	}
+	return exist, nil
+}
+
+// UnifiedDashboardExists reports whether the project has a dashboard with the given ID. The
+// dashboards of a project are listed once and cached. Dashboards created and deleted with this
+// client afterwards keep the cache current. An ID that isn't in the cache lists them again, in
+// case the dashboard was created outside of Terraform since, so missing dashboards aren't cached.
+func (c *Client) UnifiedDashboardExists(ctx context.Context, projectName string, id string) (bool, error) {
+	r := &c.dashboards
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ids[projectName][id] {
+		return true, nil
+	}
+
+	ids, err := c.listUnifiedDashboardIDs(ctx, projectName)
+	if err != nil {
+		return false, err
+	}
+	if r.ids == nil {
+		r.ids = map[string]map[string]bool{}
+	}
+	r.ids[projectName] = ids
+	return ids[id], nil
+}
+
+// setDashboardExists records the creation or deletion of a dashboard in the projects that are
+// already cached
+func (c *Client) setDashboardExists(projectName string, id string, exists bool) {
+	r := &c.dashboards
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ids, ok := r.ids[projectName]; ok {
+		ids[id] = exists
+	}
+}
+
+// listUnifiedDashboardIDs returns the set of dashboard IDs of the project
+func (c *Client) listUnifiedDashboardIDs(ctx context.Context, projectName string) (map[string]bool, error) {
+	dashboards, err := c.ListUnifiedDashboards(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(dashboards))
+	for _, d := range dashboards {
+		ids[d.ID] = true
+	}
+	return ids, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 )
 
 type Destination struct {
@@ -87,12 +86,5 @@ func (c *Client) GetDestination(ctx context.Context, projectName string, destina
 }
 
 func (c *Client) DeleteDestination(ctx context.Context, project string, destinationID string) error {
-	err := c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/destinations/%v", project, destinationID), nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
-		}
-	}
-	return nil
+	return c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/destinations/%v", project, destinationID), nil, nil)
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 )
 
@@ -125,15 +124,7 @@ func (c *Client) DeleteInferredServiceRule(
 	inferredServiceRuleID string,
 ) error {
 	apiPath := getInferredServiceRuleUrlWithId(projectName, inferredServiceRuleID)
-	err := c.CallAPI(ctx, "DELETE", apiPath, nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
-		}
-	}
-
-	return nil
+	return c.CallAPI(ctx, "DELETE", apiPath, nil, nil)
 }
 
 func getInferredServiceRuleUrl(project string) string {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 )
 
 type UnifiedCondition struct {
@@ -23,6 +22,16 @@ type UnifiedConditionAttributes struct {
 	Queries        []MetricQueryWithAttributes `json:"metric-queries"`
 	AlertingRules  []AlertingRule              `json:"alerting-rules,omitempty"`
 	CompositeAlert *CompositeAlert             `json:"composite-alert,omitempty"`
+	// EvaluationWindow is the window the queries are evaluated over as a duration, e.g. "5m"
+	EvaluationWindow string `json:"evaluation-window,omitempty"`
+	// Severity is the priority of the alert: critical, warning or info
+	Severity string `json:"severity,omitempty"`
+	// RenotifyIntervalMS is how often notifications are re-sent while the alert stays
+	// triggered, 0 to never re-send them
+	RenotifyIntervalMS *int `json:"renotify-interval-ms,omitempty"`
+	// NoDataBehavior is what the alert does when its queries return no data: alert,
+	// no_alert or keep_previous to stay in the state it was in
+	NoDataBehavior string `json:"no-data-behavior,omitempty"`
 }
 
 type CompositeAlert struct {
@@ -63,8 +72,14 @@ type DependencyMapOptions struct {
 	MapType string `json:"map-type,omitempty"`
 }
 
+// Baseline overlays a query with its expected range based on its own history
+type Baseline struct {
+	Lookback    string `json:"lookback"`
+	Sensitivity string `json:"sensitivity,omitempty"`
+}
+
 type MetricQueryWithAttributes struct {
-	Name                 string                 `json:"query-name"`
+	Name                 string                 `json:"query-name" validate:"required"`
 	Type                 string                 `json:"query-type"`
 	Hidden               bool                   `json:"hidden"`
 	Display              string                 `json:"display-type"`
@@ -75,6 +90,9 @@ type MetricQueryWithAttributes struct {
 	TQLQuery             string                 `json:"tql-query"`
 	DependencyMapOptions *DependencyMapOptions  `json:"dependency-map-options,omitempty"`
 	HiddenQueries        map[string]bool        `json:"hidden-queries,omitempty"`
+	TimeShift            string                 `json:"time-shift,omitempty"`
+	DisplayLabel         string                 `json:"display-label,omitempty"`
+	Baseline             *Baseline              `json:"baseline,omitempty"`
 }
 
 type MetricQuery struct {
@@ -104,12 +122,31 @@ type CompositeQuery struct {
 	FinalWindowOperation *FinalWindowOperation `json:"final-window-operation"`
 }
 
+const (
+	// LabelFilterExists and LabelFilterNotExists are the operands of the filters that only
+	// check whether the label is set, they have no value
+	LabelFilterExists    = "exists"
+	LabelFilterNotExists = "not_exists"
+)
+
 type LabelFilter struct {
 	Key     string `json:"key"`
 	Value   string `json:"value"`
 	Operand string `json:"operand"`
 }
 
+// MarshalJSON leaves the value out of the filters that check whether the label is set
+func (f LabelFilter) MarshalJSON() ([]byte, error) {
+	type labelFilter LabelFilter
+	if f.Operand == LabelFilterExists || f.Operand == LabelFilterNotExists {
+		return json.Marshal(struct {
+			Key     string `json:"key"`
+			Operand string `json:"operand"`
+		}{Key: f.Key, Operand: f.Operand})
+	}
+	return json.Marshal(labelFilter(f))
+}
+
 type GroupBy struct {
 	LabelKeys   []string `json:"label-keys"`
 	Aggregation string   `json:"aggregation-method"`
@@ -214,12 +251,5 @@ func (c *Client) GetUnifiedCondition(ctx context.Context, projectName string, co
 func (c *Client) DeleteUnifiedCondition(ctx context.Context, projectName string, conditionID string) error {
 	url := getURL(projectName, conditionID)
 
-	err := c.CallAPI(ctx, "DELETE", url, nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
-		}
-	}
-	return nil
+	return c.CallAPI(ctx, "DELETE", url, nil, nil)
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -43,3 +44,17 @@ func Test_DeleteUnifiedCondition_when_connection_has_wrong_content_length(t *tes
 	assert.NotNil(t, err)
 	assert.Equal(t, "unexpected EOF", err.Error())
 }
+
+func TestLabelFilterMarshalJSON(t *testing.T) {
+	b, err := json.Marshal([]LabelFilter{
+		{Key: "service", Value: "checkout", Operand: "eq"},
+		{Key: "canary", Operand: LabelFilterExists},
+		{Key: "canary", Value: "ignored", Operand: LabelFilterNotExists},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"key": "service", "value": "checkout", "operand": "eq"},
+		{"key": "canary", "operand": "exists"},
+		{"key": "canary", "operand": "not_exists"}
+	]`, string(b))
+}
@@ -3,31 +3,62 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 type UnifiedDashboard struct {
 	Type       string                     `json:"type"`
 	ID         string                     `json:"id"`
 	Attributes UnifiedDashboardAttributes `json:"attributes,omitempty"`
+
+	// Version is the ETag returned when the dashboard was read. It is sent back as an
+	// If-Match precondition on update so that concurrent edits aren't clobbered.
+	Version string `json:"-"`
 }
 
 type UnifiedDashboardAttributes struct {
-	Name              string             `json:"name"`
+	Name              string             `json:"name" validate:"required"`
 	Description       string             `json:"description"`
 	Charts            []UnifiedChart     `json:"charts"`
 	Groups            []UnifiedGroup     `json:"groups"`
 	Labels            []Label            `json:"labels"`
 	TemplateVariables []TemplateVariable `json:"template_variables"`
+	// TemplateVariablePresets are named combinations of template variable values users can
+	// switch between
+	TemplateVariablePresets []TemplateVariablePreset `json:"template_variable_presets,omitempty"`
+	// EventOverlays mark events, e.g. deploys, on the timelines of the dashboard's charts
+	EventOverlays []EventOverlay `json:"event-overlays,omitempty"`
+	// LinkedDashboards are related dashboards linked to for navigation
+	LinkedDashboards []DashboardLink `json:"linked-dashboards,omitempty"`
+	// DefaultGroupBy is applied to every chart that doesn't group its queries itself
+	DefaultGroupBy *GroupBy `json:"default-group-by,omitempty"`
+	// Locked dashboards are protected from deletion by the API until they are unlocked
+	Locked bool `json:"locked,omitempty"`
+	// TimeRange is the default time range of the dashboard as a duration, e.g. "1h"
+	TimeRange string `json:"time-range,omitempty"`
+	// AbsoluteTimeRange pins the dashboard to a fixed window, it's exclusive with TimeRange
+	AbsoluteTimeRange *AbsoluteTimeRange `json:"absolute-time-range,omitempty"`
+	// IsDefault is set on the project's default (home) dashboard. It's read-only, use
+	// SetDefaultDashboard and UnsetDefaultDashboard to change it.
+	IsDefault bool `json:"is-default,omitempty"`
+}
+
+type AbsoluteTimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
 type UnifiedGroup struct {
 	ID             string         `json:"id"`
 	Rank           int            `json:"rank"`
 	Title          string         `json:"title"`
-	VisibilityType string         `json:"visibility_type"`
+	VisibilityType string         `json:"visibility_type" validate:"required,oneof=implicit explicit"`
 	Charts         []UnifiedChart `json:"charts"`
 	Labels         []Label        `json:"labels"`
 }
@@ -45,16 +76,28 @@ type UnifiedChart struct {
 	ID            string                      `json:"id"`
 	Title         string                      `json:"title"`
 	Description   string                      `json:"description"`
-	ChartType     string                      `json:"chart-type"`
+	ChartType     string                      `json:"chart-type" validate:"required"`
 	YAxis         *YAxis                      `json:"y-axis"`
 	MetricQueries []MetricQueryWithAttributes `json:"metric-queries"`
 	Text          string                      `json:"text"`
 	Subtitle      *string                     `json:"subtitle,omitempty"`
+	// Precision is the number of decimal places of big number charts
+	Precision *int `json:"precision,omitempty"`
+	// ShowSparkLine shows the trend of the value under the big number of big number charts
+	ShowSparkLine bool `json:"show-spark-line,omitempty"`
+	// DisplaySettings converts the chart's values for display, e.g. from bytes to MB
+	DisplaySettings *ChartDisplaySettings `json:"display-settings,omitempty"`
+}
+
+type ChartDisplaySettings struct {
+	Unit string `json:"unit,omitempty"`
+	// Scale multiplies the values of the chart before they are displayed
+	Scale float64 `json:"scale,omitempty"`
 }
 
 type Label struct {
 	Key   string `json:"label_key"`
-	Value string `json:"label_value"`
+	Value string `json:"label_value" validate:"required"`
 }
 
 type YAxis struct {
@@ -68,11 +111,21 @@ type MetricGroupBy struct {
 }
 
 type TemplateVariable struct {
-	Name                   string   `json:"name"`
+	Name                   string   `json:"name" validate:"required"`
 	DefaultValues          []string `json:"default_values"`
 	SuggestionAttributeKey string   `json:"suggestion_attribute_key"`
 }
 
+type TemplateVariablePreset struct {
+	Name      string                        `json:"name" validate:"required"`
+	Variables []TemplateVariablePresetValue `json:"variables"`
+}
+
+type TemplateVariablePresetValue struct {
+	Name   string   `json:"name" validate:"required"`
+	Values []string `json:"values"`
+}
+
 func getUnifiedDashboardURL(project, id string) string {
 	path := fmt.Sprintf(
 		"projects/%s/metric_dashboards",
@@ -95,14 +148,27 @@ func (c *Client) CreateUnifiedDashboard(
 		resp Envelope
 	)
 
+	if c.options.ValidateRequests {
+		if err := validateRequest("attributes", dashboard.Attributes); err != nil {
+			return cond, err
+		}
+	}
+
 	bytes, err := json.Marshal(UnifiedDashboard{
 		Type: dashboard.Type,
 		Attributes: UnifiedDashboardAttributes{
-			Name:              dashboard.Attributes.Name,
-			Description:       dashboard.Attributes.Description,
-			Groups:            dashboard.Attributes.Groups,
-			Labels:            dashboard.Attributes.Labels,
-			TemplateVariables: dashboard.Attributes.TemplateVariables,
+			Name:                    dashboard.Attributes.Name,
+			Description:             dashboard.Attributes.Description,
+			Groups:                  dashboard.Attributes.Groups,
+			Labels:                  dashboard.Attributes.Labels,
+			TemplateVariables:       dashboard.Attributes.TemplateVariables,
+			TemplateVariablePresets: dashboard.Attributes.TemplateVariablePresets,
+			EventOverlays:           dashboard.Attributes.EventOverlays,
+			LinkedDashboards:        dashboard.Attributes.LinkedDashboards,
+			DefaultGroupBy:          dashboard.Attributes.DefaultGroupBy,
+			Locked:                  dashboard.Attributes.Locked,
+			TimeRange:               dashboard.Attributes.TimeRange,
+			AbsoluteTimeRange:       dashboard.Attributes.AbsoluteTimeRange,
 		},
 	})
 
@@ -121,36 +187,148 @@ func (c *Client) CreateUnifiedDashboard(
 	if err != nil {
 		return cond, err
 	}
+	c.setDashboardExists(projectName, cond.ID, true)
 	return cond, err
 }
 
+// ListUnifiedDashboards lists the dashboards in the project from every page of the list. If a
+// page fails, the dashboards of the pages before it are returned along with the error. Use
+// GetUnifiedDashboard to get the full definition of a dashboard.
+func (c *Client) ListUnifiedDashboards(ctx context.Context, projectName string) ([]UnifiedDashboard, error) {
+	// dashboard lists can be large, so the pages are decoded without buffering the responses
+	return listAllPages[UnifiedDashboard](ctx, c, getUnifiedDashboardURL(projectName, ""))
+}
+
+// GetUnifiedDashboardIDBySlug returns the ID of the dashboard of the project that the segments
+// of a dashboard URL refer to, the segments after "dashboard" in the path. A segment is either
+// the ID of the dashboard or a slug of its name like "checkout-overview". IDs take precedence.
+func (c *Client) GetUnifiedDashboardIDBySlug(ctx context.Context, projectName string, segments []string) (string, error) {
+	dashboards, err := c.ListUnifiedDashboards(ctx, projectName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, s := range segments {
+		for _, d := range dashboards {
+			if d.ID == s {
+				return d.ID, nil
+			}
+		}
+	}
+	for _, s := range segments {
+		var matching []string
+		for _, d := range dashboards {
+			if dashboardSlug(d.Attributes.Name) == strings.ToLower(s) {
+				matching = append(matching, d.ID)
+			}
+		}
+		switch len(matching) {
+		case 0:
+		case 1:
+			return matching[0], nil
+		default:
+			return "", fmt.Errorf("%v dashboards of project %v match %q: %v, import one of them by ID", len(matching), projectName, s, matching)
+		}
+	}
+	return "", fmt.Errorf("no dashboard of project %v matches %q", projectName, strings.Join(segments, "/"))
+}
+
+// dashboardSlug returns the slug of a dashboard name, its lowercase letters and digits with
+// the runs of other characters between them replaced by a dash
+func dashboardSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteRune('-')
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func (c *Client) GetUnifiedDashboard(ctx context.Context, projectName string, id string) (*UnifiedDashboard, error) {
-	var (
-		d    *UnifiedDashboard
-		resp Envelope
-	)
+	var d *UnifiedDashboard
 
-	url := getUnifiedDashboardURL(projectName, id)
-	err := c.CallAPI(ctx, "GET", url, nil, &resp)
+	resp, err := CallAPIWithResult[Envelope](ctx, c, "GET", getUnifiedDashboardURL(projectName, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(resp.Data, &d)
+	err = json.Unmarshal(resp.Result.Data, &d)
+	if err == nil && d != nil {
+		d.Version = resp.Headers.Get("ETag")
+	}
 	return d, err
 }
 
+// GetUnifiedDashboards gets the dashboards of the project concurrently and returns them in
+// the order of ids. The requests still wait for the read rate limiter, so no more workers are
+// started than requests it lets through per second. Dashboards that can't be fetched are nil
+// and their errors are joined.
+func (c *Client) GetUnifiedDashboards(ctx context.Context, projectName string, ids []string) ([]*UnifiedDashboard, error) {
+	dashboards := make([]*UnifiedDashboard, len(ids))
+	errs := make([]error, len(ids))
+
+	workers := c.options.ReadRateLimitPerSecond
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				d, err := c.GetUnifiedDashboard(ctx, projectName, ids[i])
+				if err != nil {
+					errs[i] = fmt.Errorf("dashboard %v: %w", ids[i], err)
+					continue
+				}
+				dashboards[i] = d
+			}
+		}()
+	}
+	for i := range ids {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return dashboards, errors.Join(errs...)
+}
+
+// UpdateUnifiedDashboard updates the dashboard. If version is non-empty it is sent as an
+// If-Match precondition and the API responds with 412 Precondition Failed if the dashboard
+// has been modified since that version was read.
 func (c *Client) UpdateUnifiedDashboard(
 	ctx context.Context,
 	projectName string,
 	dashboardID string,
 	attributes UnifiedDashboardAttributes,
+	version string,
 ) (*UnifiedDashboard, error) {
 	var (
 		d    *UnifiedDashboard
 		resp Envelope
 	)
 
+	if c.options.ValidateRequests {
+		if err := validateRequest("attributes", attributes); err != nil {
+			return nil, err
+		}
+	}
+
 	bytes, err := json.Marshal(&UnifiedDashboard{
 		Type:       "dashboard",
 		ID:         dashboardID,
@@ -160,8 +338,13 @@ func (c *Client) UpdateUnifiedDashboard(
 		return nil, err
 	}
 
+	var headers Headers
+	if version != "" {
+		headers = Headers{"If-Match": version}
+	}
+
 	url := getUnifiedDashboardURL(projectName, dashboardID)
-	err = c.CallAPI(ctx, "PUT", url, Envelope{Data: bytes}, &resp)
+	_, err = c.callAPIWithHeaders(ctx, "PUT", url, headers, Envelope{Data: bytes}, &resp)
 	if err != nil {
 		return d, err
 	}
@@ -173,12 +356,32 @@ func (c *Client) UpdateUnifiedDashboard(
 func (c *Client) DeleteUnifiedDashboard(ctx context.Context, projectName string, dashboardID string) error {
 	url := getUnifiedDashboardURL(projectName, dashboardID)
 
-	err := c.CallAPI(ctx, "DELETE", url, nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
-		}
+	if err := c.CallAPI(ctx, "DELETE", url, nil, nil); err != nil {
+		return err
 	}
+	c.setDashboardExists(projectName, dashboardID, false)
 	return nil
 }
+
+// SetDefaultDashboard makes the dashboard the default (home) dashboard of the project, replacing
+// the current default dashboard if there is one
+func (c *Client) SetDefaultDashboard(ctx context.Context, projectName string, dashboardID string) error {
+	return c.CallAPI(ctx, "PUT", getUnifiedDashboardURL(projectName, dashboardID)+"/default", nil, nil)
+}
+
+// UnsetDefaultDashboard removes the dashboard as the default (home) dashboard of the project
+func (c *Client) UnsetDefaultDashboard(ctx context.Context, projectName string, dashboardID string) error {
+	return c.CallAPI(ctx, "DELETE", getUnifiedDashboardURL(projectName, dashboardID)+"/default", nil, nil)
+}
+
+type EventOverlay struct {
+	Name string `json:"name" validate:"required"`
+	// Source is where the events come from, "deploys" or "events" for custom events
+	Source string `json:"source" validate:"required,oneof=deploys events"`
+	// Query narrows down the events of the source, e.g. service = "checkout"
+	Query string `json:"query,omitempty"`
+}
+
+type DashboardLink struct {
+	ID string `json:"id" validate:"required"`
+}
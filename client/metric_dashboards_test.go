@@ -2,9 +2,14 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,3 +68,337 @@ func Test_DeleteMetricDashboard_when_connection_has_wrong_content_length(t *test
 	assert.NotNil(t, err)
 	assert.Equal(t, "unexpected EOF", err.Error())
 }
+
+func Test_UpdateUnifiedDashboard_sends_version_as_precondition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			_, err := w.Write([]byte(`{"data":{"type":"dashboard","id":"hi","attributes":{"name":"dash"}}}`))
+			require.NoError(t, err)
+		case http.MethodPut:
+			// the dashboard has been modified since v1 was read
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+			w.WriteHeader(http.StatusPreconditionFailed)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	d, err := c.GetUnifiedDashboard(context.Background(), "tacoman", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, `"v1"`, d.Version)
+
+	_, err = c.UpdateUnifiedDashboard(context.Background(), "tacoman", "hi", d.Attributes, d.Version)
+	require.Error(t, err)
+
+	apiErr, ok := err.(APIResponseCarrier)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusPreconditionFailed, apiErr.GetStatusCode())
+}
+
+func Test_ListUnifiedDashboards(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		body        string
+		expected    []string
+		expectError string
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			body:     `{"data": [{"id": "d1", "attributes": {"name": "one"}}, {"id": "d2", "attributes": {"name": "two"}}]}`,
+			expected: []string{"d1", "d2"},
+		},
+		{
+			name:        "error body is kept",
+			status:      http.StatusForbidden,
+			body:        `{"errors": ["forbidden"]}`,
+			expectError: `forbidden`,
+		},
+		{
+			name:        "malformed response",
+			status:      http.StatusOK,
+			body:        `{"data": [{"id": `,
+			expectError: `could not decode response`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+				w.WriteHeader(tc.status)
+				_, err := w.Write([]byte(tc.body))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+			c := NewClient("api", "blars", "staging")
+			dashboards, err := c.ListUnifiedDashboards(context.Background(), "tacoman")
+
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids []string
+			for _, d := range dashboards {
+				ids = append(ids, d.ID)
+			}
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func Test_GetUnifiedDashboardIDBySlug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [
+			{"id": "AbC123", "attributes": {"name": "Checkout: Overview (prod)"}},
+			{"id": "checkout", "attributes": {"name": "Payments"}},
+			{"id": "d1", "attributes": {"name": "Latency"}},
+			{"id": "d2", "attributes": {"name": "latency"}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api", "blars", "staging")
+
+	for _, tc := range []struct {
+		segments []string
+		expected string
+	}{
+		{segments: []string{"AbC123"}, expected: "AbC123"},
+		{segments: []string{"checkout-overview-prod"}, expected: "AbC123"},
+		{segments: []string{"Checkout-Overview-Prod"}, expected: "AbC123"},
+		// IDs take precedence over slugs
+		{segments: []string{"checkout-overview-prod", "checkout"}, expected: "checkout"},
+	} {
+		id, err := c.GetUnifiedDashboardIDBySlug(context.Background(), "tacoman", tc.segments)
+		require.NoError(t, err, tc.segments)
+		assert.Equal(t, tc.expected, id, tc.segments)
+	}
+
+	_, err := c.GetUnifiedDashboardIDBySlug(context.Background(), "tacoman", []string{"missing"})
+	assert.ErrorContains(t, err, `no dashboard of project tacoman matches "missing"`)
+	_, err = c.GetUnifiedDashboardIDBySlug(context.Background(), "tacoman", []string{"latency"})
+	assert.ErrorContains(t, err, `2 dashboards of project tacoman match "latency"`)
+}
+
+func Test_ListUnifiedDashboardsPages(t *testing.T) {
+	pages := map[string]string{
+		"":       `{"data": [{"id": "d1", "attributes": {"name": "one"}}], "links": {"next": "metric_dashboards?page=2"}}`,
+		"page=2": `{"data": [{"id": "d2", "attributes": {"name": "two"}}], "links": {}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+		_, err := w.Write([]byte(pages[r.URL.RawQuery]))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api", "blars", "staging")
+
+	dashboards, err := c.ListUnifiedDashboards(context.Background(), "tacoman")
+	require.NoError(t, err)
+	require.Len(t, dashboards, 2, "every page is listed")
+	assert.Equal(t, "d1", dashboards[0].ID)
+	assert.Equal(t, "one", dashboards[0].Attributes.Name)
+	assert.Equal(t, "d2", dashboards[1].ID)
+	assert.Equal(t, "two", dashboards[1].Attributes.Name)
+}
+
+func Test_GetUnifiedDashboards(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/public/v0.2/blars/projects/tacoman/metric_dashboards/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// the first dashboards take the longest, so they complete out of order
+		delay := map[string]time.Duration{"d1": 600, "d2": 400}[id] * time.Millisecond
+		time.Sleep(delay + 100*time.Millisecond)
+		_, err := fmt.Fprintf(w, `{"data": {"id": %q, "attributes": {"name": "dashboard %v"}}}`, id, id)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "")
+	c := NewClientWithOptions("api", "blars", "staging", ClientOptions{ReadRateLimitPerSecond: 3})
+
+	ids := []string{"d1", "d2", "missing", "d3", "d4", "d5"}
+	dashboards, err := c.GetUnifiedDashboards(context.Background(), "tacoman", ids)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dashboard missing: ")
+	var apiErr APIResponseCarrier
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.GetStatusCode())
+
+	require.Len(t, dashboards, len(ids))
+	for i, id := range ids {
+		if id == "missing" {
+			assert.Nil(t, dashboards[i])
+			continue
+		}
+		require.NotNil(t, dashboards[i], id)
+		assert.Equal(t, id, dashboards[i].ID, "dashboards are returned in the order of the ids")
+	}
+	assert.Greater(t, atomic.LoadInt32(&maxInFlight), int32(1), "dashboards are fetched concurrently")
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3), "no more requests than the rate limit are in flight")
+}
+
+// largeDashboardList returns a list response of n dashboards with a few charts each
+func largeDashboardList(n int) []byte {
+	dashboards := make([]UnifiedDashboard, n)
+	for i := range dashboards {
+		dashboards[i] = UnifiedDashboard{
+			ID:   fmt.Sprintf("dashboard-%d", i),
+			Type: "dashboard",
+			Attributes: UnifiedDashboardAttributes{
+				Name:        fmt.Sprintf("Dashboard %d", i),
+				Description: strings.Repeat("description ", 20),
+			},
+		}
+		for j := 0; j < 5; j++ {
+			dashboards[i].Attributes.Charts = append(dashboards[i].Attributes.Charts, UnifiedChart{
+				Title:     fmt.Sprintf("Chart %d", j),
+				ChartType: "timeseries",
+				MetricQueries: []MetricQueryWithAttributes{
+					{Name: "a", Type: "tql", Display: "line", TQLQuery: "metric requests | rate | group_by [service], sum"},
+				},
+			})
+		}
+	}
+
+	body, err := json.Marshal(genericAPIResponse[[]UnifiedDashboard]{Data: dashboards})
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// BenchmarkListUnifiedDashboards compares decoding a large list response after buffering it
+// (as CallAPI does) with decoding it straight from the response body.
+func BenchmarkListUnifiedDashboards(b *testing.B) {
+	body := largeDashboardList(2000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	b.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	b.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api", "blars", "staging")
+	ctx := context.Background()
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var resp Envelope
+			if err := c.CallAPI(ctx, "GET", getUnifiedDashboardURL("tacoman", ""), nil, &resp); err != nil {
+				b.Fatal(err)
+			}
+			var d []UnifiedDashboard
+			if err := json.Unmarshal(resp.Data, &d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.ListUnifiedDashboards(ctx, "tacoman"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func Test_UnifiedDashboardsExist(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [{"id": "d1"}, {"id": "d2"}, {"id": "d4"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	exist, err := c.UnifiedDashboardsExist(context.Background(), "tacoman", []string{"d1", "d2", "d3", "d4", "d5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"d1": true, "d2": true, "d3": false, "d4": true, "d5": false}, exist)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_UnifiedDashboardExists(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			calls++
+			_, err := w.Write([]byte(`{"data": [{"id": "d1"}, {"id": "d2"}]}`))
+			assert.NoError(t, err)
+		case http.MethodPost:
+			_, err := w.Write([]byte(`{"data": {"id": "d3"}}`))
+			assert.NoError(t, err)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+	ctx := context.Background()
+
+	for _, id := range []string{"d1", "d2"} {
+		exists, err := c.UnifiedDashboardExists(ctx, "tacoman", id)
+		require.NoError(t, err)
+		assert.True(t, exists, id)
+	}
+	assert.Equal(t, 1, calls, "dashboards are listed once")
+
+	// dashboards created with the client update the cache
+	_, err := c.CreateUnifiedDashboard(ctx, "tacoman", UnifiedDashboard{})
+	require.NoError(t, err)
+	exists, err := c.UnifiedDashboardExists(ctx, "tacoman", "d3")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 1, calls)
+
+	// missing dashboards aren't cached, they are listed again
+	require.NoError(t, c.DeleteUnifiedDashboard(ctx, "tacoman", "d1"))
+	for i := 0; i < 2; i++ {
+		exists, err = c.UnifiedDashboardExists(ctx, "tacoman", "d4")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const ProjectType = "project"
+
+// Project is a Lightstep project, its ID is the project name
+type Project struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Attributes ProjectAttributes `json:"attributes,omitempty"`
+}
+
+type ProjectAttributes struct {
+	// Description is always sent, so that removing it clears it
+	Description string `json:"description"`
+}
+
+// ListProjects returns the projects of the organization
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	var resp genericAPIResponse[[]Project]
+
+	err := c.callAPIStreaming(ctx, "projects", &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *Client) CreateProject(ctx context.Context, name string, attributes ProjectAttributes) (Project, error) {
+	var (
+		project Project
+		resp    Envelope
+	)
+
+	bytes, err := json.Marshal(Project{Type: ProjectType, ID: name, Attributes: attributes})
+	if err != nil {
+		return project, err
+	}
+
+	err = c.CallAPI(ctx, "POST", "projects", Envelope{Data: bytes}, &resp)
+	if err != nil {
+		return project, err
+	}
+
+	err = json.Unmarshal(resp.Data, &project)
+	return project, err
+}
+
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	var (
+		project *Project
+		resp    Envelope
+	)
+
+	err := c.CallAPI(ctx, "GET", getProjectURL(name), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(resp.Data, &project)
+	return project, err
+}
+
+func (c *Client) UpdateProject(ctx context.Context, name string, attributes ProjectAttributes) (Project, error) {
+	var (
+		project Project
+		resp    Envelope
+	)
+
+	bytes, err := json.Marshal(Project{Type: ProjectType, ID: name, Attributes: attributes})
+	if err != nil {
+		return project, err
+	}
+
+	err = c.CallAPI(ctx, "PUT", getProjectURL(name), Envelope{Data: bytes}, &resp)
+	if err != nil {
+		return project, err
+	}
+
+	err = json.Unmarshal(resp.Data, &project)
+	return project, err
+}
+
+func (c *Client) DeleteProject(ctx context.Context, name string) error {
+	return c.CallAPI(ctx, "DELETE", getProjectURL(name), nil, nil)
+}
+
+func getProjectURL(name string) string {
+	u := url.URL{Path: fmt.Sprintf("projects/%v", url.PathEscape(name))}
+	return u.String()
+}
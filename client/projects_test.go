@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getProjectURL(t *testing.T) {
+	assert.Equal(t, "projects/my_project", getProjectURL("my_project"))
+}
+
+func Test_CreateProject(t *testing.T) {
+	attributes := ProjectAttributes{Description: "Services of the checkout team"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/public/v0.2/blars/projects", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req struct {
+			Data Project `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, ProjectType, req.Data.Type)
+		assert.Equal(t, "checkout", req.Data.ID)
+		assert.Equal(t, attributes, req.Data.Attributes)
+
+		_, err = w.Write([]byte(`{"data": {"id": "checkout", "type": "project", "attributes": {"description": "Services of the checkout team"}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	project, err := c.CreateProject(context.Background(), "checkout", attributes)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", project.ID)
+	assert.Equal(t, attributes, project.Attributes)
+}
+
+func Test_UpdateProjectClearsDescription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/public/v0.2/blars/projects/checkout", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"data": {"id": "checkout", "type": "project", "attributes": {"description": ""}}}`, string(body))
+
+		_, err = w.Write([]byte(`{"data": {"id": "checkout", "type": "project", "attributes": {}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	project, err := c.UpdateProject(context.Background(), "checkout", ProjectAttributes{})
+	require.NoError(t, err)
+	assert.Equal(t, "", project.Attributes.Description)
+}
+
+func Test_DeleteProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/public/v0.2/blars/projects/checkout", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	require.NoError(t, c.DeleteProject(context.Background(), "checkout"))
+}
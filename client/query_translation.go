@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// TranslatedQuery is the TQL equivalent of a structured (legacy) query
+type TranslatedQuery struct {
+	Name string `json:"query-name"`
+	TQL  string `json:"tql-query"`
+}
+
+// TranslateQueries asks the API for the TQL equivalent of the queries, they are returned by
+// query name
+func (c *Client) TranslateQueries(ctx context.Context, projectName string, queries []MetricQueryWithAttributes) ([]TranslatedQuery, error) {
+	var resp struct {
+		Data struct {
+			Queries []TranslatedQuery `json:"queries"`
+		} `json:"data"`
+	}
+
+	req := map[string]interface{}{
+		"data": map[string]interface{}{
+			"queries": queries,
+		},
+	}
+	err := c.CallAPI(ctx, "POST", fmt.Sprintf("projects/%v/query_translation", url.PathEscape(projectName)), req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data.Queries, nil
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// referenceCache holds the stream IDs of each project, so a plan can check the references of
+// every resource with one list call per project instead of one read per resource
+type referenceCache struct {
+	mu      sync.Mutex
+	streams map[string]map[string]bool
+}
+
+// StreamIDExists reports whether the project has a stream with the given ID. The streams of a
+// project are listed once and cached. An ID that isn't in the cache lists them again, in case
+// the stream was created since (e.g. earlier in the same apply or outside of Terraform), so
+// missing streams aren't cached.
+func (c *Client) StreamIDExists(ctx context.Context, projectName string, id string) (bool, error) {
+	r := &c.references
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.streams == nil {
+		r.streams = map[string]map[string]bool{}
+	}
+	if r.streams[projectName][id] {
+		return true, nil
+	}
+
+	streams, err := c.ListStreams(ctx, projectName)
+	if err != nil {
+		return false, err
+	}
+	ids := make(map[string]bool, len(streams))
+	for _, s := range streams {
+		ids[s.ID] = true
+	}
+	r.streams[projectName] = ids
+	return ids[id], nil
+}
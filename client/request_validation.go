@@ -0,0 +1,113 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldViolation is a structural error in a request body, at the JSON path of the field,
+// e.g. "attributes.groups[0].charts[1].chart-type"
+type FieldViolation struct {
+	Path    string
+	Message string
+}
+
+// RequestValidationError is returned instead of sending a request whose body breaks the
+// `validate` tags of its structs
+type RequestValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *RequestValidationError) Error() string {
+	var violations []string
+	for _, v := range e.Violations {
+		violations = append(violations, fmt.Sprintf("%v: %v", v.Path, v.Message))
+	}
+	return fmt.Sprintf("invalid request: %v", strings.Join(violations, "; "))
+}
+
+// validateRequest checks the `validate` tags of v and of the structs it holds, under the JSON
+// path prefix. `validate:"required"` fields must not be empty and `validate:"oneof=a b"` fields
+// must be one of the listed values, or empty unless they are also required.
+func validateRequest(prefix string, v interface{}) error {
+	var violations []FieldViolation
+	validateValue(prefix, reflect.ValueOf(v), &violations)
+	if len(violations) > 0 {
+		return &RequestValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func validateValue(path string, v reflect.Value, violations *[]FieldViolation) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			validateValue(path, v.Elem(), violations)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(fmt.Sprintf("%v[%d]", path, i), v.Index(i), violations)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = joinPath(path, name)
+			}
+
+			value := v.Field(i)
+			if message := checkValidateTag(field.Tag.Get("validate"), value); message != "" {
+				*violations = append(*violations, FieldViolation{Path: fieldPath, Message: message})
+			}
+			validateValue(fieldPath, value, violations)
+		}
+	}
+}
+
+// checkValidateTag returns why the value breaks the rules of the tag, or "" if it doesn't
+func checkValidateTag(tag string, value reflect.Value) string {
+	if tag == "" {
+		return ""
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "required" {
+			required = true
+			if value.IsZero() {
+				return "is required"
+			}
+		}
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		allowed, ok := strings.CutPrefix(rule, "oneof=")
+		if !ok || (!required && value.IsZero()) {
+			continue
+		}
+		s := fmt.Sprint(value.Interface())
+		for _, a := range strings.Fields(allowed) {
+			if s == a {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %q, got %q", strings.Fields(allowed), s)
+	}
+	return ""
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
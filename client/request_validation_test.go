@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateRequest(t *testing.T) {
+	attributes := UnifiedDashboardAttributes{
+		Name: "Test dashboard",
+		Groups: []UnifiedGroup{
+			{
+				VisibilityType: "implicit",
+				Charts: []UnifiedChart{
+					{
+						Title:     "Requests",
+						ChartType: "timeseries",
+						MetricQueries: []MetricQueryWithAttributes{
+							{Name: "a", Display: "line", TQLQuery: "metric requests | rate"},
+						},
+					},
+				},
+			},
+		},
+		Labels: []Label{{Key: "team", Value: "payments"}},
+	}
+	require.NoError(t, validateRequest("attributes", attributes))
+
+	attributes.Groups[0].VisibilityType = "hidden"
+	attributes.Groups[0].Charts[0].ChartType = ""
+	attributes.Groups[0].Charts[0].MetricQueries[0].Name = ""
+	attributes.Labels[0].Value = ""
+
+	err := validateRequest("attributes", attributes)
+	var validationErr *RequestValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldViolation{
+		{Path: "attributes.groups[0].visibility_type", Message: `must be one of ["implicit" "explicit"], got "hidden"`},
+		{Path: "attributes.groups[0].charts[0].chart-type", Message: "is required"},
+		{Path: "attributes.groups[0].charts[0].metric-queries[0].query-name", Message: "is required"},
+		{Path: "attributes.labels[0].label_value", Message: "is required"},
+	}, validationErr.Violations)
+}
+
+func Test_CreateUnifiedDashboard_validates_request(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("an invalid dashboard must not be sent, got %v %v", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClientWithOptions("api", "blars", "staging", ClientOptions{ValidateRequests: true})
+
+	_, err := c.CreateUnifiedDashboard(context.Background(), "tacoman", UnifiedDashboard{
+		Type: "dashboard",
+		Attributes: UnifiedDashboardAttributes{
+			Groups: []UnifiedGroup{{VisibilityType: "implicit", Charts: []UnifiedChart{{ChartType: "timeseries"}}}},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "invalid request: attributes.name: is required", err.Error())
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const SavedViewType = "saved_view"
+
+// SavedView is a saved trace exploration: a span query narrowed by filters over a time window
+type SavedView struct {
+	Type       string              `json:"type,omitempty"`
+	ID         string              `json:"id,omitempty"`
+	Attributes SavedViewAttributes `json:"attributes"`
+}
+
+type SavedViewAttributes struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Query       string            `json:"query"`
+	Filters     []SavedViewFilter `json:"filters,omitempty"`
+	// TimeWindow is how far back the view looks as a duration, e.g. "1h"
+	TimeWindow string `json:"time-window,omitempty"`
+}
+
+type SavedViewFilter struct {
+	Key     string `json:"key"`
+	Operand string `json:"operand"`
+	Value   string `json:"value"`
+}
+
+func (c *Client) CreateSavedView(ctx context.Context, projectName string, attributes SavedViewAttributes) (SavedView, error) {
+	var (
+		view SavedView
+		resp Envelope
+	)
+
+	bytes, err := json.Marshal(SavedView{Type: SavedViewType, Attributes: attributes})
+	if err != nil {
+		return view, err
+	}
+
+	err = c.CallAPI(ctx, "POST", getSavedViewURL(projectName, ""), Envelope{Data: bytes}, &resp)
+	if err != nil {
+		return view, err
+	}
+
+	err = json.Unmarshal(resp.Data, &view)
+	return view, err
+}
+
+func (c *Client) GetSavedView(ctx context.Context, projectName string, id string) (*SavedView, error) {
+	var (
+		view *SavedView
+		resp Envelope
+	)
+
+	err := c.CallAPI(ctx, "GET", getSavedViewURL(projectName, id), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(resp.Data, &view)
+	return view, err
+}
+
+func (c *Client) UpdateSavedView(ctx context.Context, projectName string, id string, attributes SavedViewAttributes) (SavedView, error) {
+	var (
+		view SavedView
+		resp Envelope
+	)
+
+	bytes, err := json.Marshal(SavedView{Type: SavedViewType, ID: id, Attributes: attributes})
+	if err != nil {
+		return view, err
+	}
+
+	err = c.CallAPI(ctx, "PUT", getSavedViewURL(projectName, id), Envelope{Data: bytes}, &resp)
+	if err != nil {
+		return view, err
+	}
+
+	err = json.Unmarshal(resp.Data, &view)
+	return view, err
+}
+
+func (c *Client) DeleteSavedView(ctx context.Context, projectName string, id string) error {
+	return c.CallAPI(ctx, "DELETE", getSavedViewURL(projectName, id), nil, nil)
+}
+
+func getSavedViewURL(project string, id string) string {
+	path := fmt.Sprintf("projects/%v/saved_views", url.PathEscape(project))
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	u := url.URL{Path: path}
+	return u.String()
+}
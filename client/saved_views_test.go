@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getSavedViewURL(t *testing.T) {
+	assert.Equal(t, "projects/my_project/saved_views", getSavedViewURL("my_project", ""))
+	assert.Equal(t, "projects/my_project/saved_views/abc", getSavedViewURL("my_project", "abc"))
+}
+
+func Test_CreateSavedView(t *testing.T) {
+	attributes := SavedViewAttributes{
+		Name:       "Checkout errors",
+		Query:      `service IN ("checkout")`,
+		Filters:    []SavedViewFilter{{Key: "error", Operand: "eq", Value: "true"}},
+		TimeWindow: "1h",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/saved_views", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req struct {
+			Data SavedView `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, SavedViewType, req.Data.Type)
+		assert.Equal(t, attributes, req.Data.Attributes)
+
+		_, err = w.Write([]byte(`{"data": {"id": "v1", "type": "saved_view", "attributes": {"name": "Checkout errors", "time-window": "1h"}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	view, err := c.CreateSavedView(context.Background(), "tacoman", attributes)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", view.ID)
+	assert.Equal(t, "1h", view.Attributes.TimeWindow)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 )
 
 type CreateRequest struct {
@@ -119,12 +118,5 @@ func (c *Client) GetAlertingRule(ctx context.Context, projectName string, alerti
 }
 
 func (c *Client) DeleteAlertingRule(ctx context.Context, projectName string, alertingRuleID string) error {
-	err := c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/alerting_rules/%v", projectName, alertingRuleID), nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
-		}
-	}
-	return nil
+	return c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/alerting_rules/%v", projectName, alertingRuleID), nil, nil)
 }
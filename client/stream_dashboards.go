@@ -4,24 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 )
 
 type Dashboard struct {
 	Type       string              `json:"type,omitempty"`
 	ID         string              `json:"id,omitempty"`
 	Attributes DashboardAttributes `json:"attributes,omitempty"`
+
+	// IncludedStreams are the streams of the dashboard that GetDashboard received along with
+	// it, so their names and queries are available without reading each stream
+	IncludedStreams []Stream `json:"-"`
 }
 
 type DashboardAttributes struct {
-	Name    string   `json:"name"`
-	Streams []Stream `json:"streams"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Streams     []Stream `json:"streams"`
 }
 
 func (c *Client) CreateDashboard(
 	ctx context.Context,
 	projectName string,
 	dashboardName string,
+	dashboardDescription string,
 	streams []Stream,
 ) (Dashboard, error) {
 
@@ -34,8 +39,9 @@ func (c *Client) CreateDashboard(
 		Dashboard{
 			Type: "dashboard",
 			Attributes: DashboardAttributes{
-				Name:    dashboardName,
-				Streams: streams,
+				Name:        dashboardName,
+				Description: dashboardDescription,
+				Streams:     streams,
 			},
 		})
 
@@ -60,6 +66,7 @@ func (c *Client) UpdateDashboard(
 	ctx context.Context,
 	projectName string,
 	dashboardName string,
+	dashboardDescription string,
 	streams []Stream,
 	dashboardID string,
 ) (Dashboard, error) {
@@ -73,8 +80,9 @@ func (c *Client) UpdateDashboard(
 		Type: "dashboard",
 		ID:   dashboardID,
 		Attributes: DashboardAttributes{
-			Name:    dashboardName,
-			Streams: streams,
+			Name:        dashboardName,
+			Description: dashboardDescription,
+			Streams:     streams,
 		},
 	})
 	if err != nil {
@@ -108,16 +116,37 @@ func (c *Client) GetDashboard(ctx context.Context, projectName string, dashboard
 	if err != nil {
 		return d, err
 	}
+
+	if d != nil {
+		d.IncludedStreams, err = decodeIncluded[Stream](resp, "stream")
+	}
 	return d, err
 }
 
-func (c *Client) DeleteDashboard(ctx context.Context, projectName string, dashboardID string) error {
-	err := c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/dashboards/%v", projectName, dashboardID), nil, nil)
+// ListDashboards gets the stream dashboards of the project
+func (c *Client) ListDashboards(ctx context.Context, projectName string) ([]Dashboard, error) {
+	return listAllPages[Dashboard](ctx, c, fmt.Sprintf("projects/%v/dashboards", projectName))
+}
+
+// DashboardsReferencingStream returns the stream dashboards of the project that include the stream
+func (c *Client) DashboardsReferencingStream(ctx context.Context, projectName string, streamID string) ([]Dashboard, error) {
+	dashboards, err := c.ListDashboards(ctx, projectName)
 	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
+		return nil, err
+	}
+
+	var referencing []Dashboard
+	for _, d := range dashboards {
+		for _, s := range d.Attributes.Streams {
+			if s.ID == streamID {
+				referencing = append(referencing, d)
+				break
+			}
 		}
 	}
-	return nil
+	return referencing, nil
+}
+
+func (c *Client) DeleteDashboard(ctx context.Context, projectName string, dashboardID string) error {
+	return c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/dashboards/%v", projectName, dashboardID), nil, nil)
 }
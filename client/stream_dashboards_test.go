@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetDashboardIncluded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/dashboards/d1", r.URL.Path)
+		_, err := w.Write([]byte(`{
+			"data": {"id": "d1", "type": "dashboard", "attributes": {
+				"name": "Checkout",
+				"streams": [{"id": "s1", "type": "stream"}, {"id": "s2", "type": "stream"}]
+			}},
+			"included": [
+				{"id": "s1", "type": "stream", "attributes": {"name": "Checkout errors", "query": "service IN (\"checkout\")"}},
+				{"id": "p1", "type": "project", "attributes": {"name": "tacoman"}},
+				{"id": "s2", "type": "stream", "attributes": {"name": "Checkout latency", "query": "service IN (\"checkout\") AND operation IN (\"pay\")"}}
+			]
+		}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	d, err := c.GetDashboard(context.Background(), "tacoman", "d1")
+	require.NoError(t, err)
+	assert.Equal(t, "Checkout", d.Attributes.Name)
+	assert.Equal(t, []Stream{
+		{
+			ID:         "s1",
+			Type:       "stream",
+			Attributes: StreamAttributes{Name: "Checkout errors", Query: `service IN ("checkout")`},
+		},
+		{
+			ID:         "s2",
+			Type:       "stream",
+			Attributes: StreamAttributes{Name: "Checkout latency", Query: `service IN ("checkout") AND operation IN ("pay")`},
+		},
+	}, d.IncludedStreams, "only the included streams are returned")
+}
+
+func Test_DecodeIncluded(t *testing.T) {
+	streams, err := decodeIncluded[Stream](Envelope{}, "stream")
+	require.NoError(t, err)
+	assert.Empty(t, streams, "responses without included resources")
+
+	_, err = decodeIncluded[Stream](Envelope{Included: []json.RawMessage{json.RawMessage(`[]`)}}, "stream")
+	assert.Error(t, err)
+}
+
+func Test_DashboardCanceledContext(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.GetDashboard(ctx, "tacoman", "d1")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = c.CreateDashboard(ctx, "tacoman", "Checkout", "", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = c.UpdateDashboard(ctx, "tacoman", "Checkout", "", nil, "d1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, c.DeleteDashboard(ctx, "tacoman", "d1"), context.Canceled)
+	assert.Zero(t, requests, "canceled requests aren't sent")
+}
+
+func Test_DashboardsReferencingStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/dashboards", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [
+			{"id": "d1", "type": "dashboard", "attributes": {"name": "Checkout", "streams": [{"id": "s1"}, {"id": "s2"}]}},
+			{"id": "d2", "type": "dashboard", "attributes": {"name": "Payments", "streams": [{"id": "s3"}]}},
+			{"id": "d3", "type": "dashboard", "attributes": {"name": "Overview", "streams": [{"id": "s2"}]}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api", "blars", "staging")
+
+	dashboards, err := c.DashboardsReferencingStream(context.Background(), "tacoman", "s2")
+	require.NoError(t, err)
+	require.Len(t, dashboards, 2)
+	assert.Equal(t, "d1", dashboards[0].ID)
+	assert.Equal(t, "d3", dashboards[1].ID)
+
+	dashboards, err = c.DashboardsReferencingStream(context.Background(), "tacoman", "s4")
+	require.NoError(t, err)
+	assert.Empty(t, dashboards)
+}
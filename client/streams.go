@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 type Stream struct {
@@ -22,6 +24,13 @@ type StreamAttributes struct {
 
 	// Hack until https://lightstep.atlassian.net/browse/LS-26494 is fixed.
 	CustomDataGet map[string]map[string]string `json:"custom-data,omitempty"`
+
+	// Retention is how long the stream is kept before it expires, e.g. "30d", or "never"
+	Retention string `json:"retention,omitempty"`
+
+	// Color groups the stream with others in the UI, a hex color such as "#3c6fd8" or a named
+	// color such as "blue"
+	Color string `json:"color,omitempty"`
 }
 
 func CustomDataConvert(customData []interface{}) map[string]map[string]string {
@@ -71,6 +80,8 @@ func (c *Client) CreateStream(
 	name string,
 	query string,
 	customData []interface{},
+	retention string,
+	color string,
 ) (Stream, error) {
 
 	var (
@@ -87,6 +98,8 @@ func (c *Client) CreateStream(
 				Name:       name,
 				Query:      query,
 				CustomData: lsCustomData,
+				Retention:  retention,
+				Color:      color,
 			},
 		})
 	if err != nil {
@@ -111,21 +124,10 @@ func (c *Client) CreateStream(
 	return s, err
 }
 
+// ListStreams returns the streams of a project from every page of the list. If a page fails,
+// the streams of the pages before it are returned along with the error.
 func (c *Client) ListStreams(ctx context.Context, projectName string) ([]Stream, error) {
-	var (
-		s    []Stream
-		resp Envelope
-	)
-
-	err := c.CallAPI(ctx, "GET", fmt.Sprintf("projects/%v/streams", projectName), nil, &resp)
-	if err != nil {
-		return s, err
-	}
-	err = json.Unmarshal(resp.Data, &s)
-	if err != nil {
-		return s, err
-	}
-	return s, err
+	return listAllPages[Stream](ctx, c, fmt.Sprintf("projects/%v/streams", projectName))
 }
 
 func (c *Client) GetStream(ctx context.Context, projectName string, StreamID string) (*Stream, error) {
@@ -146,6 +148,20 @@ func (c *Client) GetStream(ctx context.Context, projectName string, StreamID str
 	return s, err
 }
 
+// StreamExists reports whether the stream exists. Only a 404 response means the stream doesn't
+// exist; any other failure (e.g. a 403) is returned as an error since the stream may still be there.
+func (c *Client) StreamExists(ctx context.Context, projectName string, StreamID string) (bool, error) {
+	_, err := c.GetStream(ctx, projectName, StreamID)
+	if err != nil {
+		apiErr, ok := err.(APIResponseCarrier)
+		if ok && apiErr.GetStatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (c *Client) UpdateStream(ctx context.Context, projectName string,
 	streamID string,
 	stream Stream,
@@ -170,13 +186,53 @@ func (c *Client) UpdateStream(ctx context.Context, projectName string,
 	return s, err
 }
 
-func (c *Client) DeleteStream(ctx context.Context, projectName string, StreamID string) error {
-	err := c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/streams/%v", projectName, StreamID), nil, nil)
-	if err != nil {
-		apiClientError, ok := err.(APIResponseCarrier)
-		if !ok || apiClientError.GetStatusCode() != http.StatusNoContent {
-			return err
+// StreamTimeseries holds the number of spans and errors of a stream in each time window of a
+// time range
+type StreamTimeseries struct {
+	TimeWindows []StreamTimeWindow `json:"time-windows"`
+	OpsCounts   []int64            `json:"ops-counts"`
+	ErrorCounts []int64            `json:"error-counts"`
+}
+
+type StreamTimeWindow struct {
+	OldestTime   time.Time `json:"oldest-time"`
+	YoungestTime time.Time `json:"youngest-time"`
+}
+
+// GetStreamTimeseries returns the timeseries of the stream between oldest and youngest, in
+// windows of resolution. The API runs the query of the stream over the range, so it fails for
+// queries that aren't valid over it.
+func (c *Client) GetStreamTimeseries(
+	ctx context.Context,
+	projectName string,
+	streamID string,
+	oldest time.Time,
+	youngest time.Time,
+	resolution time.Duration,
+) (*StreamTimeseries, error) {
+	var (
+		ts struct {
+			Attributes StreamTimeseries `json:"attributes"`
 		}
+		resp Envelope
+	)
+
+	params := url.Values{}
+	params.Set("oldest-time", oldest.UTC().Format(time.RFC3339))
+	params.Set("youngest-time", youngest.UTC().Format(time.RFC3339))
+	params.Set("resolution-ms", fmt.Sprint(resolution.Milliseconds()))
+
+	err := c.CallAPI(ctx, "GET", fmt.Sprintf("projects/%v/streams/%v/timeseries?%v", projectName, streamID, params.Encode()), nil, &resp)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	if err := json.Unmarshal(resp.Data, &ts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream timeseries: %v", err)
+	}
+	return &ts.Attributes, nil
+}
+
+func (c *Client) DeleteStream(ctx context.Context, projectName string, StreamID string) error {
+	return c.CallAPI(ctx, "DELETE", fmt.Sprintf("projects/%v/streams/%v", projectName, StreamID), nil, nil)
 }
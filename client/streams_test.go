@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func Test_StreamExists(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		body        string
+		exists      bool
+		expectError bool
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+			body:   `{"data": {"id": "s1", "type": "stream"}}`,
+			exists: true,
+		},
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"errors": ["not found"]}`,
+			exists: false,
+		},
+		{
+			name:        "forbidden",
+			status:      http.StatusForbidden,
+			body:        `{"errors": ["forbidden"]}`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams/s1", r.URL.Path)
+				w.WriteHeader(tc.status)
+				_, err := w.Write([]byte(tc.body))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+			c := NewClient("api", "blars", "staging")
+			exists, err := c.StreamExists(context.Background(), "tacoman", "s1")
+
+			if tc.expectError {
+				assert.Error(t, err)
+				apiErr, ok := err.(APIResponseCarrier)
+				assert.True(t, ok)
+				assert.Equal(t, tc.status, apiErr.GetStatusCode())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.exists, exists)
+		})
+	}
+}
+
+func Test_StreamIDExists(t *testing.T) {
+	var calls int
+	body := `{"data": [{"id": "s1", "type": "stream"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams", r.URL.Path)
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+	ctx := context.Background()
+
+	exists, err := c.StreamIDExists(ctx, "tacoman", "s1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = c.StreamIDExists(ctx, "tacoman", "s1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 1, calls, "streams are listed once")
+
+	// a stream created since the list is found by listing again
+	body = `{"data": [{"id": "s1", "type": "stream"}, {"id": "s2", "type": "stream"}]}`
+	exists, err = c.StreamIDExists(ctx, "tacoman", "s2")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, calls)
+
+	// a missing stream isn't remembered, it may be created outside of Terraform
+	exists, err = c.StreamIDExists(ctx, "tacoman", "s3")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	body = `{"data": [{"id": "s1", "type": "stream"}, {"id": "s2", "type": "stream"}, {"id": "s3", "type": "stream"}]}`
+	exists, err = c.StreamIDExists(ctx, "tacoman", "s3")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 4, calls)
+}
+
+func Test_ListStreams(t *testing.T) {
+	pages := map[string]string{
+		"":       `{"data": [{"id": "s1", "type": "stream"}], "links": {"next": "/public/v0.2/blars/projects/tacoman/streams?page=2"}}`,
+		"page=2": `{"data": [{"id": "s2", "type": "stream"}, {"id": "s3", "type": "stream"}], "links": {"next": "streams?page=3"}}`,
+		"page=3": `{"data": [{"id": "s4", "type": "stream"}], "links": {}}`,
+	}
+	failPage := "none"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams", r.URL.Path)
+		if r.URL.RawQuery == failPage {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, err := w.Write([]byte(pages[r.URL.RawQuery]))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := NewClient("api", "blars", "staging")
+
+	ids := func(streams []Stream) []string {
+		var ids []string
+		for _, s := range streams {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	streams, err := c.ListStreams(context.Background(), "tacoman")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2", "s3", "s4"}, ids(streams), "every page is listed")
+
+	// the streams of the pages before a failing one are returned with the error
+	failPage = "page=3"
+	streams, err = c.ListStreams(context.Background(), "tacoman")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"s1", "s2", "s3"}, ids(streams))
+
+	// every page is rate limited, with a budget of one request per hour the second page
+	// can't be fetched before the deadline
+	failPage = "none"
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "")
+	c.readRateLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	streams, err = c.ListStreams(ctx, "tacoman")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"s1"}, ids(streams))
+
+	// a next link to another host isn't followed, it would get the API key
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("the API key was sent to another host: %v", r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	pages["page=2"] = `{"data": [{"id": "s2", "type": "stream"}], "links": {"next": "` + other.URL + `/public/v0.2/blars/projects/tacoman/streams?page=3"}}`
+	streams, err = c.ListStreams(context.Background(), "tacoman")
+	assert.ErrorContains(t, err, "leaves "+server.URL)
+	assert.Equal(t, []string{"s1", "s2"}, ids(streams))
+}
+
+func Test_GetStreamTimeseries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams/s1/timeseries", r.URL.Path)
+		assert.Equal(t, "2023-06-01T00:00:00Z", r.URL.Query().Get("oldest-time"))
+		assert.Equal(t, "2023-06-02T00:00:00Z", r.URL.Query().Get("youngest-time"))
+		assert.Equal(t, "3600000", r.URL.Query().Get("resolution-ms"))
+		_, err := w.Write([]byte(`{"data": {"attributes": {
+			"time-windows": [{"oldest-time": "2023-06-01T00:00:00Z", "youngest-time": "2023-06-01T01:00:00Z"}],
+			"ops-counts": [42],
+			"error-counts": [1]
+		}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+	oldest := time.Date(2023, 6, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	ts, err := c.GetStreamTimeseries(context.Background(), "tacoman", "s1", oldest, oldest.Add(24*time.Hour), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{42}, ts.OpsCounts)
+	assert.Equal(t, []int64{1}, ts.ErrorCounts)
+	assert.Len(t, ts.TimeWindows, 1)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QueryTimeseriesRequest describes a query run against the telemetry API
+type QueryTimeseriesRequest struct {
+	Query         string    `json:"query"`
+	InputLanguage string    `json:"input-language"`
+	OldestTime    time.Time `json:"oldest-time"`
+	YoungestTime  time.Time `json:"youngest-time"`
+	// OutputPeriod is the spacing of the returned points in seconds
+	OutputPeriod int64 `json:"output-period"`
+}
+
+type QueryTimeseriesResult struct {
+	Series []TimeseriesSeries `json:"series"`
+}
+
+type TimeseriesSeries struct {
+	GroupLabels []Label `json:"group-labels"`
+	// Points are pairs of [timestamp in microseconds, value] in ascending time order
+	Points [][2]float64 `json:"points"`
+}
+
+type queryTimeseriesAttributes[T any] struct {
+	Attributes T `json:"attributes"`
+}
+
+// QueryMetric runs a TQL query and returns the resulting series
+func (c *Client) QueryMetric(ctx context.Context, projectName string, req QueryTimeseriesRequest) (*QueryTimeseriesResult, error) {
+	if req.InputLanguage == "" {
+		req.InputLanguage = "tql"
+	}
+
+	var resp genericAPIResponse[queryTimeseriesAttributes[QueryTimeseriesResult]]
+	err := c.CallAPI(
+		ctx,
+		"POST",
+		fmt.Sprintf("projects/%v/telemetry/query_timeseries", projectName),
+		genericAPIResponse[queryTimeseriesAttributes[QueryTimeseriesRequest]]{
+			Data: queryTimeseriesAttributes[QueryTimeseriesRequest]{Attributes: req},
+		},
+		&resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Data.Attributes, nil
+}
+
+// LastValue returns the most recent value of the single series returned by a query
+func (r *QueryTimeseriesResult) LastValue() (float64, error) {
+	if len(r.Series) != 1 {
+		return 0, fmt.Errorf("query must return exactly one series, got %d", len(r.Series))
+	}
+
+	points := r.Series[0].Points
+	if len(points) == 0 {
+		return 0, fmt.Errorf("query returned no points")
+	}
+	return points[len(points)-1][1], nil
+}
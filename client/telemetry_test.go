@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_QueryMetric(t *testing.T) {
+	youngest := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/telemetry/query_timeseries", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"data": {"attributes": {
+			"query": "constant 42",
+			"input-language": "tql",
+			"oldest-time": "2023-01-01T11:50:00Z",
+			"youngest-time": "2023-01-01T12:00:00Z",
+			"output-period": 60
+		}}}`, string(body))
+
+		_, err = w.Write([]byte(`{"data": {"attributes": {"series": [
+			{"group-labels": [], "points": [[1672574340000000, 41], [1672574400000000, 42]]}
+		]}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := NewClient("api", "blars", "staging")
+	result, err := c.QueryMetric(context.Background(), "tacoman", QueryTimeseriesRequest{
+		Query:        "constant 42",
+		OldestTime:   youngest.Add(-10 * time.Minute),
+		YoungestTime: youngest,
+		OutputPeriod: 60,
+	})
+	require.NoError(t, err)
+
+	value, err := result.LastValue()
+	require.NoError(t, err)
+	assert.Equal(t, float64(42), value)
+}
+
+func Test_QueryTimeseriesResult_LastValue(t *testing.T) {
+	testCases := []struct {
+		name        string
+		result      string
+		expected    float64
+		expectError string
+	}{
+		{
+			name:     "single series",
+			result:   `{"series": [{"points": [[1, 1.5], [2, 2.5]]}]}`,
+			expected: 2.5,
+		},
+		{
+			name:        "no series",
+			result:      `{"series": []}`,
+			expectError: "exactly one series, got 0",
+		},
+		{
+			name:        "several series",
+			result:      `{"series": [{"points": [[1, 1]]}, {"points": [[1, 2]]}]}`,
+			expectError: "exactly one series, got 2",
+		},
+		{
+			name:        "no points",
+			result:      `{"series": [{"points": []}]}`,
+			expectError: "no points",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var result QueryTimeseriesResult
+			require.NoError(t, json.Unmarshal([]byte(tc.result), &result))
+
+			value, err := result.LastValue()
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
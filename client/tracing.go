@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lightstep/terraform-provider-lightstep/version"
+)
+
+const tracerName = "github.com/lightstep/terraform-provider-lightstep/client"
+
+// newTracer returns the tracer of the client, or nil if no TracerProvider is configured so
+// requests aren't instrumented at all
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		return nil
+	}
+	return tp.Tracer(tracerName, trace.WithInstrumentationVersion(version.ProviderVersion))
+}
+
+// startRequestSpan starts the client span of an API call, retries included. The returned
+// function ends it with the status of the response or the error of the call.
+func (c *Client) startRequestSpan(ctx context.Context, req *retryablehttp.Request) (context.Context, func(*http.Response, error)) {
+	if c.tracer == nil {
+		return ctx, func(*http.Response, error) {}
+	}
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		fmt.Sprintf("HTTP %v", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(req.URL.String()),
+		),
+	)
+	return ctx, func(resp *http.Response, err error) {
+		if resp != nil {
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public/v0.2/org-name/projects/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(`{"data": []}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{TracerProvider: tp})
+
+	require.NoError(t, c.CallAPI(context.Background(), "GET", "projects", nil, nil))
+	require.Error(t, c.CallAPI(context.Background(), "GET", "projects/missing", nil, nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "HTTP GET", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.method", "GET"))
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.url", server.URL+"/public/v0.2/org-name/projects"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.status_code", http.StatusOK))
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	assert.Contains(t, spans[1].Attributes, attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
+
+func TestRequestSpansDisabled(t *testing.T) {
+	c := NewClientWithOptions("api-key", "org-name", "public", ClientOptions{})
+	assert.Nil(t, c.tracer, "requests aren't instrumented without a TracerProvider")
+}
@@ -0,0 +1,307 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+const streamTemplate = `
+resource "lightstep_stream" "{{.ResourceName}}" {
+  project_name = "{{escapeHCLString .Project}}"
+  stream_name  = "{{escapeHCLString .Stream.Attributes.Name}}"
+  query        = "{{escapeHCLString .Stream.Attributes.Query}}"
+{{- with .Stream.Attributes.Retention}}{{if ne . "never"}}
+  retention    = "{{escapeHCLString .}}"
+{{- end}}{{end}}
+{{- with .Stream.Attributes.Color}}
+  color        = "{{escapeHCLString .}}"
+{{- end}}
+{{- if .CustomData}}
+  custom_data = [
+{{- range .CustomData}}
+    {
+{{- range .}}
+{{- if .Variable}}
+      "{{escapeHCLString .Key}}" = var.{{.Variable}}
+{{- else}}
+      "{{escapeHCLString .Key}}" = "{{escapeHCLString .Value}}"
+{{- end}}
+{{- end}}
+    },
+{{- end}}
+  ]
+{{- end}}
+}
+`
+
+const secretVariableTemplate = `
+variable "{{.}}" {
+  type      = string
+  sensitive = true
+}
+`
+
+const importTemplate = `
+import {
+  to = {{.Address}}
+  id = "{{escapeHCLString .ID}}"
+}
+`
+
+var invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// secretKeyPattern matches the custom data keys whose values are likely to be credentials. The
+// words must be whole tokens of the key, separated by _, - or ., so "author" or "keyboard"
+// aren't redacted.
+var secretKeyPattern = regexp.MustCompile(`(?i)(^|[_.-])(secret|token|passw(or)?d|credentials?|auth|authorization|(api|access|private)[_-]?key)($|[_.-])`)
+
+// resourceNames hands out unique Terraform resource names derived from display names
+type resourceNames map[string]int
+
+// identifier turns s into a lowercase Terraform identifier, which may be empty
+func identifier(s string) string {
+	return strings.Trim(invalidResourceNameChars.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+func (n resourceNames) next(displayName string) string {
+	name := identifier(displayName)
+	if name == "" {
+		name = "unnamed"
+	} else if name[0] >= '0' && name[0] <= '9' {
+		// resource names must start with a letter or underscore
+		name = "_" + name
+	}
+
+	n[name]++
+	if n[name] > 1 {
+		return fmt.Sprintf("%s_%d", name, n[name])
+	}
+	return name
+}
+
+type keyValue struct {
+	Key   string
+	Value string
+	// Variable is the name of the input variable the value is replaced with, if it is redacted
+	Variable string
+}
+
+// streamCustomData converts the custom data of a stream to the list of maps used by the
+// custom_data attribute, sorted so the output is stable.
+func streamCustomData(s client.Stream) [][]keyValue {
+	customData := s.Attributes.CustomDataGet
+	if len(customData) == 0 {
+		customData = s.Attributes.CustomData
+	}
+
+	names := make([]string, 0, len(customData))
+	for name := range customData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result [][]keyValue
+	for _, name := range names {
+		// the "name" key is special and becomes the key of the custom data object
+		entry := []keyValue{{Key: "name", Value: name}}
+
+		keys := make([]string, 0, len(customData[name]))
+		for k := range customData[name] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			entry = append(entry, keyValue{Key: k, Value: customData[name][k]})
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// redactSecrets replaces the values of secret-looking custom data keys with references to
+// input variables named after the resource, the custom data object and the key. It returns
+// the names of the variables.
+func redactSecrets(resourceName string, customData [][]keyValue) []string {
+	var variables []string
+	for _, entry := range customData {
+		// the first key is always "name", see streamCustomData
+		name := identifier(entry[0].Value)
+		for i := range entry[1:] {
+			kv := &entry[i+1]
+			if !secretKeyPattern.MatchString(kv.Key) {
+				continue
+			}
+			kv.Variable = strings.Join([]string{resourceName, name, identifier(kv.Key)}, "_")
+			variables = append(variables, kv.Variable)
+		}
+	}
+	return variables
+}
+
+// importBlock is an import block adopting the resource at Address with the given ID
+type importBlock struct {
+	Address string
+	ID      string
+}
+
+// exportProjectDashboards writes the configuration of every dashboard in the project, or only
+// of those with the given label if it isn't empty, naming the resources with names, and
+// returns the import blocks that adopt them.
+func exportProjectDashboards(
+	ctx context.Context,
+	wr io.Writer,
+	c *client.Client,
+	project string,
+	names resourceNames,
+	label string,
+) ([]importBlock, error) {
+	dashboards, err := c.ListUnifiedDashboards(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("could not list dashboards: %v", err)
+	}
+
+	var imports []importBlock
+	for i := range dashboards {
+		listed := &dashboards[i]
+		// the list response carries the labels, so only the matching dashboards are fetched
+		if label != "" && !hasLabel(listed, label) {
+			continue
+		}
+		// but it doesn't necessarily include the full dashboard definition
+		d, err := c.GetUnifiedDashboard(ctx, project, listed.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get dashboard %v: %v", listed.ID, err)
+		}
+
+		name := names.next(d.Attributes.Name)
+		if err := renderHCL(wr, d, exportOptions{resourceName: name, projectName: project}); err != nil {
+			return nil, err
+		}
+		imports = append(imports, importBlock{
+			Address: dashboardResourceType(d) + "." + name,
+			ID:      project + "." + d.ID,
+		})
+	}
+	return imports, nil
+}
+
+// exportAllDashboards writes the configuration of every dashboard in the project, or only of
+// those with the given label if it isn't empty, with resource names derived from the dashboard
+// names, and returns how many were written
+func exportAllDashboards(ctx context.Context, wr io.Writer, c *client.Client, project string, label string) (int, error) {
+	imports, err := exportProjectDashboards(ctx, wr, c, project, resourceNames{}, label)
+	return len(imports), err
+}
+
+// formatLabel writes a label as key:value, or as its value alone if it has no key
+func formatLabel(l client.Label) string {
+	if l.Key == "" {
+		return l.Value
+	}
+	return l.Key + ":" + l.Value
+}
+
+// hasLabel reports whether the dashboard has the label, written as key:value or value
+func hasLabel(d *client.UnifiedDashboard, label string) bool {
+	for _, l := range d.Attributes.Labels {
+		if formatLabel(l) == label {
+			return true
+		}
+	}
+	return false
+}
+
+// exportProject writes the configuration of every supported resource (dashboards and streams)
+// in the project, followed by the import blocks that adopt them into a fresh Terraform state.
+// Unless revealSecrets is set, values that look like credentials are replaced with sensitive
+// input variables so the output can be committed.
+func exportProject(ctx context.Context, wr io.Writer, c *client.Client, project string, revealSecrets bool) error {
+	names := resourceNames{}
+	imports, err := exportProjectDashboards(ctx, wr, c, project, names, "")
+	if err != nil {
+		return err
+	}
+
+	streams, err := c.ListStreams(ctx, project)
+	if err != nil {
+		return fmt.Errorf("could not list streams: %v", err)
+	}
+	streamNames, secretVariables, err := writeStreams(wr, project, streams, names, revealSecrets)
+	if err != nil {
+		return err
+	}
+	for i, s := range streams {
+		imports = append(imports, importBlock{
+			Address: "lightstep_stream." + streamNames[i],
+			ID:      project + "." + s.ID,
+		})
+	}
+	return writeVariablesAndImports(wr, secretVariables, imports)
+}
+
+// writeStreams writes the configuration of the streams, naming the resources with names. It
+// returns the resource name of each stream and, unless revealSecrets is set, the sensitive
+// input variables that replace the credentials in their custom data.
+func writeStreams(wr io.Writer, project string, streams []client.Stream, names resourceNames, revealSecrets bool) ([]string, []string, error) {
+	st, err := template.New("").Funcs(template.FuncMap{"escapeHCLString": escapeHCLString}).Parse(streamTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stream parsing error: %v", err)
+	}
+
+	var resourceNames, secretVariables []string
+	for _, s := range streams {
+		name := names.next(s.Attributes.Name)
+		customData := streamCustomData(s)
+		if !revealSecrets {
+			secretVariables = append(secretVariables, redactSecrets(name, customData)...)
+		}
+		err := st.Execute(wr, struct {
+			ResourceName string
+			Project      string
+			Stream       client.Stream
+			CustomData   [][]keyValue
+		}{
+			ResourceName: name,
+			Project:      project,
+			Stream:       s,
+			CustomData:   customData,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not generate stream %v: %v", s.Attributes.Name, err)
+		}
+		resourceNames = append(resourceNames, name)
+	}
+	return resourceNames, secretVariables, nil
+}
+
+// writeVariablesAndImports writes the declarations of the secret input variables followed by
+// the import blocks
+func writeVariablesAndImports(wr io.Writer, secretVariables []string, imports []importBlock) error {
+	vt, err := template.New("").Parse(secretVariableTemplate)
+	if err != nil {
+		return fmt.Errorf("variable parsing error: %v", err)
+	}
+	for _, v := range secretVariables {
+		if err := vt.Execute(wr, v); err != nil {
+			return fmt.Errorf("could not generate variable %v: %v", v, err)
+		}
+	}
+
+	it, err := template.New("").Funcs(template.FuncMap{"escapeHCLString": escapeHCLString}).Parse(importTemplate)
+	if err != nil {
+		return fmt.Errorf("import parsing error: %v", err)
+	}
+	for _, i := range imports {
+		if err := it.Execute(wr, i); err != nil {
+			return fmt.Errorf("could not generate import block for %v: %v", i.Address, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// fixtureOrg serves a project with two dashboards (one with legacy queries) and two streams
+var fixtureOrg = map[string]string{
+	"/public/v0.2/my-org/projects/shop/metric_dashboards": `{"data": [
+		{"id": "d1", "type": "dashboard", "attributes": {"name": "Checkout"}},
+		{"id": "d2", "type": "dashboard", "attributes": {"name": "checkout"}}
+	]}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d1": `{"data": {"id": "d1", "type": "dashboard", "attributes": {
+		"name": "Checkout",
+		"charts": [{"title": "Requests", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric requests | rate"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d2": `{"data": {"id": "d2", "type": "dashboard", "attributes": {
+		"name": "checkout",
+		"charts": [{"title": "Legacy", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "single", "display-type": "line", "metric-query": {"metric": "requests", "timeseries-operator": "rate"}}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop/streams": `{"data": [
+		{"id": "s1", "type": "stream", "attributes": {"name": "Checkout errors", "query": "service IN (\"checkout\") AND \"error\" IN (\"true\")",
+			"custom-data": {"runbook": {"url": "https://example.com/runbook", "api-token": "s3cr3t"}}}},
+		{"id": "s2", "type": "stream", "attributes": {"name": "1 all spans", "query": "service IN (\"api\")", "retention": "30d", "color": "#3c6fd8"}}
+	]}`,
+}
+
+// fixtureClient returns a client for an API server serving the given responses by path
+func fixtureClient(t *testing.T, fixtures map[string]string) *client.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	return client.NewClient("api", "my-org", "public")
+}
+
+func TestExportProject(t *testing.T) {
+	c := fixtureClient(t, fixtureOrg)
+
+	var buf bytes.Buffer
+	require.NoError(t, exportProject(context.Background(), &buf, c, "shop", false))
+
+	file, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "adopt.tf")
+	require.False(t, diags.HasErrors(), "generated config does not parse: %v\n%s", diags, buf.String())
+
+	content, _ := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "resource", LabelNames: []string{"type", "name"}},
+			{Type: "import"},
+		},
+	})
+
+	var resources []string
+	imports := map[string]string{}
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "resource":
+			resources = append(resources, block.Labels[0]+"."+block.Labels[1])
+		case "import":
+			attrs, diags := block.Body.JustAttributes()
+			require.False(t, diags.HasErrors(), "%v", diags)
+
+			to := string(attrs["to"].Expr.Range().SliceBytes(buf.Bytes()))
+			id, diags := attrs["id"].Expr.Value(nil)
+			require.False(t, diags.HasErrors(), "%v", diags)
+			imports[to] = id.AsString()
+		}
+	}
+
+	assert.Equal(t, []string{
+		"lightstep_dashboard.checkout",
+		"lightstep_metric_dashboard.checkout_2",
+		"lightstep_stream.checkout_errors",
+		"lightstep_stream._1_all_spans",
+	}, resources)
+	assert.Equal(t, map[string]string{
+		"lightstep_dashboard.checkout":          "shop.d1",
+		"lightstep_metric_dashboard.checkout_2": "shop.d2",
+		"lightstep_stream.checkout_errors":      "shop.s1",
+		"lightstep_stream._1_all_spans":         "shop.s2",
+	}, imports)
+
+	out := buf.String()
+	assert.Contains(t, out, `project_name = "shop"`)
+	assert.Contains(t, out, `query        = "service IN (\"checkout\") AND \"error\" IN (\"true\")"`)
+	assert.Contains(t, out, `"url" = "https://example.com/runbook"`)
+	assert.Contains(t, out, `retention    = "30d"`)
+	assert.Equal(t, 1, strings.Count(out, "retention"), "streams that never expire have no retention")
+	assert.Contains(t, out, `color        = "#3c6fd8"`)
+	assert.Equal(t, 1, strings.Count(out, "color"), "streams without a color have none")
+}
+
+func TestExportProjectSecrets(t *testing.T) {
+	c := fixtureClient(t, fixtureOrg)
+
+	var buf bytes.Buffer
+	require.NoError(t, exportProject(context.Background(), &buf, c, "shop", false))
+	out := buf.String()
+
+	assert.NotContains(t, out, "s3cr3t")
+	assert.Contains(t, out, `"api-token" = var.checkout_errors_runbook_api_token`)
+	assert.Contains(t, out, `variable "checkout_errors_runbook_api_token" {
+  type      = string
+  sensitive = true
+}`)
+	assert.Contains(t, out, `"url" = "https://example.com/runbook"`, "other values are kept")
+
+	_, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "adopt.tf")
+	require.False(t, diags.HasErrors(), "generated config does not parse: %v\n%s", diags, out)
+
+	buf.Reset()
+	require.NoError(t, exportProject(context.Background(), &buf, c, "shop", true))
+	out = buf.String()
+
+	assert.Contains(t, out, `"api-token" = "s3cr3t"`)
+	assert.NotContains(t, out, "variable ")
+}
+
+func TestSecretKeyPattern(t *testing.T) {
+	for _, key := range []string{"api-token", "api_key", "apiKey", "client_secret", "password", "Authorization", "x-auth-header"} {
+		assert.True(t, secretKeyPattern.MatchString(key), key)
+	}
+	for _, key := range []string{"author", "monkey", "keyboard", "tokenizer", "url", "runbook_key_points"} {
+		assert.False(t, secretKeyPattern.MatchString(key), key)
+	}
+}
+
+func TestParseArgsRevealSecrets(t *testing.T) {
+	flags, positional, err := parseArgs([]string{"adopt", "shop"})
+	require.NoError(t, err)
+	assert.False(t, flags.revealSecrets)
+	assert.Equal(t, []string{"adopt", "shop"}, positional)
+
+	flags, positional, err = parseArgs([]string{"adopt", "--reveal-secrets", "shop"})
+	require.NoError(t, err)
+	assert.True(t, flags.revealSecrets)
+	assert.Equal(t, []string{"adopt", "shop"}, positional)
+}
+
+func TestExportAllDashboards(t *testing.T) {
+	c := fixtureClient(t, fixtureOrg)
+
+	var buf bytes.Buffer
+	exported, err := exportAllDashboards(context.Background(), &buf, c, "shop", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported)
+
+	file, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboards.tf")
+	require.False(t, diags.HasErrors(), "generated config does not parse: %v\n%s", diags, buf.String())
+
+	content, _ := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "resource", LabelNames: []string{"type", "name"}}},
+	})
+	var resources []string
+	for _, block := range content.Blocks {
+		resources = append(resources, block.Labels[0]+"."+block.Labels[1])
+	}
+	assert.Equal(t, []string{"lightstep_dashboard.checkout", "lightstep_metric_dashboard.checkout_2"}, resources,
+		"dashboards with the same identifier get a numeric suffix")
+	assert.NotContains(t, buf.String(), "import {", "only the dashboards are written")
+}
+
+func TestParseArgsAll(t *testing.T) {
+	flags, positional, err := parseArgs([]string{"dashboard", "shop", "--all"})
+	require.NoError(t, err)
+	assert.True(t, flags.all)
+	assert.Equal(t, []string{"dashboard", "shop"}, positional)
+
+	for _, args := range [][]string{
+		{"dashboard", "shop", "--all", "--format", "yaml"},
+		{"dashboard", "shop", "--all", "--module-dir", "module"},
+		{"dashboard", "shop", "--all", "--tql"},
+	} {
+		_, _, err := parseArgs(args)
+		assert.Error(t, err, "%v", args)
+	}
+}
@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/lightstep/terraform-provider-lightstep/lightstep"
+)
+
+// omitComputedAttributes removes the attributes and blocks that are only computed in the
+// provider's resource schemas (e.g. IDs and versions assigned by the API) from the resource
+// blocks of the HCL, since Terraform rejects them in configuration. The HCL is returned as is
+// if there are none, since rewriting it also reformats it.
+func omitComputedAttributes(src []byte) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(src, "exported.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("could not parse the generated HCL: %v", diags)
+	}
+
+	resources := lightstep.Provider().ResourcesMap
+	removed := 0
+	for _, block := range f.Body().Blocks() {
+		if block.Type() != "resource" || len(block.Labels()) == 0 {
+			continue
+		}
+		if r, ok := resources[block.Labels()[0]]; ok {
+			removed += omitComputed(block.Body(), r.Schema)
+		}
+	}
+	if removed == 0 {
+		return src, nil
+	}
+	return f.Bytes(), nil
+}
+
+// omitComputed removes the computed-only attributes and blocks of body, recursing into the
+// blocks that are kept, and returns how many were removed
+func omitComputed(body *hclwrite.Body, s map[string]*schema.Schema) int {
+	removed := 0
+	for name := range body.Attributes() {
+		if attribute, ok := s[name]; ok && computedOnly(attribute) {
+			body.RemoveAttribute(name)
+			removed++
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		nested, ok := s[block.Type()]
+		if !ok {
+			continue
+		}
+		if computedOnly(nested) {
+			body.RemoveBlock(block)
+			removed++
+			continue
+		}
+		if r, ok := nested.Elem.(*schema.Resource); ok {
+			removed += omitComputed(block.Body(), r.Schema)
+		}
+	}
+	return removed
+}
+
+// computedOnly reports whether the attribute is set by the provider and can't be configured
+func computedOnly(s *schema.Schema) bool {
+	return s.Computed && !s.Optional && !s.Required
+}
@@ -0,0 +1,87 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestOmitComputedAttributes(t *testing.T) {
+	src := `
+resource "lightstep_dashboard" "exported_dashboard" {
+  project_name   = "shop"
+  dashboard_name = "Checkout"
+  type           = "dashboard"
+  version        = "\"v1\""
+  time_range     = "1h"
+
+  group {
+    id              = "g1"
+    rank            = 0
+    visibility_type = "explicit"
+
+    chart {
+      id   = "c1"
+      name = "Requests"
+      rank = 1
+      type = "timeseries"
+    }
+  }
+}
+
+resource "lightstep_stream" "errors" {
+  project_name     = "shop"
+  stream_name      = "Errors"
+  query            = "error = true"
+  normalized_query = "\"error\" IN (\"true\")"
+}
+`
+
+	hcl, err := omitComputedAttributes([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, absent := range []string{"version", "id ", "normalized_query", `"dashboard"`} {
+		if strings.Contains(string(hcl), absent) {
+			t.Errorf("computed attribute %q was not omitted:\n%s", absent, hcl)
+		}
+	}
+	// attributes that are optional as well as computed, or required, are kept
+	for _, present := range []string{`time_range`, `rank`, `type`, `visibility_type`, `query`} {
+		if !strings.Contains(string(hcl), present) {
+			t.Errorf("attribute %q was omitted:\n%s", present, hcl)
+		}
+	}
+}
+
+func TestExportOmitsServerFields(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		ID:      "abc123",
+		Type:    "dashboard",
+		Version: `"v1"`,
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			Charts: []client.UnifiedChart{
+				{
+					ID:        "c1",
+					Title:     "Requests",
+					ChartType: "timeseries",
+					Rank:      1,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, absent := range []string{"abc123", "c1", "v1", "version", `type = "dashboard"`} {
+		if strings.Contains(buf.String(), absent) {
+			t.Errorf("generated HCL contains the server-only value %q:\n%s", absent, buf.String())
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+	"github.com/lightstep/terraform-provider-lightstep/lightstep"
+)
+
+const metricConditionTemplate = `
+resource "lightstep_metric_condition" "{{resourceName}}" {
+  project_name = "{{escapeHCLString projectName}}"
+  name = "{{escapeHCLString .Attributes.Name}}"
+{{- if .Attributes.Description}}
+  description = {{escapeHeredocString .Attributes.Description}}
+{{- end}}
+{{- if .Attributes.CustomData}}
+  custom_data = {{escapeHeredocString .Attributes.CustomData}}
+{{- end}}
+{{range .Attributes.Labels}}
+  label {
+{{- if .Key}}
+    key   = "{{escapeHCLString .Key}}"
+{{- end}}
+    value = "{{escapeHCLString .Value}}"
+  }
+{{end}}{{with .Attributes.Expression}}
+  expression {
+    is_multi   = {{.IsMulti}}
+    is_no_data = {{.IsNoData}}
+{{- if .Operand}}
+    operand    = "{{escapeHCLString .Operand}}"
+{{- end}}
+{{- if or .Thresholds.Critical .Thresholds.Warning}}
+    thresholds {
+{{- with .Thresholds.Critical}}
+      critical = "{{formatThreshold .}}"
+{{- end}}
+{{- with .Thresholds.Warning}}
+      warning  = "{{formatThreshold .}}"
+{{- end}}
+    }
+{{- end}}
+  }
+{{end}}{{range .Attributes.Queries}}
+  metric_query {
+    query_name = "{{escapeHCLString .Name}}"
+    display    = "{{escapeHCLString .Display}}"
+    hidden     = {{.Hidden}}
+{{- if .SpansQuery.Query}}
+
+    spans {
+      query    = "{{escapeHCLString .SpansQuery.Query}}"
+      operator = "{{escapeHCLString .SpansQuery.Operator}}"
+{{- with .SpansQuery.OperatorInputWindowMs}}
+      operator_input_window_ms = {{.}}
+{{- end}}
+{{- if .SpansQuery.GroupByKeys}}
+      group_by_keys = {{hclStringList .SpansQuery.GroupByKeys}}
+{{- end}}
+{{- if eq .SpansQuery.Operator "latency"}}
+      latency_percentiles = [{{range $i, $p := .SpansQuery.LatencyPercentiles}}{{if $i}}, {{end}}{{$p}}{{end}}]
+{{- end}}
+    }
+{{- end}}
+{{- if .TQLQuery}}
+    tql = {{escapeHeredocString .TQLQuery}}
+{{- end}}
+{{- if .Query.Metric}}
+    metric              = "{{escapeHCLString .Query.Metric}}"
+    timeseries_operator = "{{escapeHCLString .Query.TimeseriesOperator}}"
+{{- with .Query.TimeseriesOperatorInputWindowMs}}
+    timeseries_operator_input_window_ms = {{.}}
+{{- end}}
+{{- hclQueryFilters .Query.Filters "    "}}
+{{- if or .Query.GroupBy.Aggregation .Query.GroupBy.LabelKeys}}
+
+    group_by {
+      aggregation_method = "{{escapeHCLString .Query.GroupBy.Aggregation}}"
+      keys               = {{hclStringList .Query.GroupBy.LabelKeys}}
+    }
+{{- end}}
+{{- end}}
+{{- with finalWindowOperation .}}
+
+    final_window_operation {
+      operator        = "{{escapeHCLString .Operator}}"
+      input_window_ms = {{.InputWindowMs}}
+    }
+{{- end}}
+  }
+{{end}}{{range .Attributes.AlertingRules}}
+  alerting_rule {
+    id = "{{escapeHCLString .MessageDestinationID}}"
+{{- with updateInterval .UpdateInterval}}
+    update_interval = "{{escapeHCLString .}}"
+{{- end}}
+{{- with .MatchOn.GroupBy}}
+    include_filters = [{{range .}}
+      {
+        key   = "{{escapeHCLString .Key}}"
+        value = "{{escapeHCLString .Value}}"
+      },{{end}}
+    ]
+{{- end}}
+  }
+{{end}}}
+`
+
+// exportConditionToHCL renders the metric condition as a lightstep_metric_condition resource
+// of the project
+func exportConditionToHCL(wr io.Writer, project string, c *client.UnifiedCondition) error {
+	if c.Attributes.CompositeAlert != nil {
+		return fmt.Errorf("condition %v is a composite alert, which lightstep_metric_condition doesn't support", c.ID)
+	}
+
+	t, err := template.New("").Funcs(template.FuncMap{
+		"escapeHCLString":     escapeHCLString,
+		"escapeHeredocString": escapeHeredocString,
+		"hclStringList":       hclStringList,
+		"resourceName":        func() string { return "exported_condition" },
+		"projectName":         func() string { return project },
+		"formatThreshold": func(v float64) string {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		},
+		"hclQueryFilters": hclQueryFilters,
+		"finalWindowOperation": func(q client.MetricQueryWithAttributes) *client.FinalWindowOperation {
+			switch {
+			case q.Query.FinalWindowOperation != nil:
+				return q.Query.FinalWindowOperation
+			case q.CompositeQuery.FinalWindowOperation != nil:
+				return q.CompositeQuery.FinalWindowOperation
+			default:
+				return q.SpansQuery.FinalWindowOperation
+			}
+		},
+		"updateInterval": func(ms int) string {
+			// intervals the provider doesn't support are left out, like no interval
+			if interval, _ := lightstep.GetUpdateIntervalValue(ms).(string); interval != "invalid" {
+				return interval
+			}
+			return ""
+		},
+	}).Parse(metricConditionTemplate)
+	if err != nil {
+		return fmt.Errorf("condition parsing error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, c); err != nil {
+		return fmt.Errorf("could not generate template: %v", err)
+	}
+
+	hcl, err := omitComputedAttributes(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = wr.Write(hcl)
+	return err
+}
+
+// hclQueryFilters renders the filters of a metric query as the include_filters, exclude_filters
+// and filters attributes of lightstep_metric_condition and lightstep_metric_dashboard queries,
+// indented by indent. Each attribute is preceded by an empty line.
+func hclQueryFilters(filters []client.LabelFilter, indent string) string {
+	var b strings.Builder
+	for _, attr := range []struct {
+		name    string
+		operand string
+	}{{"include_filters", "eq"}, {"exclude_filters", "neq"}, {"filters", ""}} {
+		matching := filtersWithOperand(filters, attr.operand)
+		if len(matching) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n%s%s = [", indent, attr.name)
+		for _, f := range matching {
+			fmt.Fprintf(&b, "\n%s  {", indent)
+			if attr.operand != "" {
+				fmt.Fprintf(&b, "\n%s    key   = \"%s\"", indent, escapeHCLString(f.Key))
+				fmt.Fprintf(&b, "\n%s    value = \"%s\"", indent, escapeHCLString(f.Value))
+			} else {
+				fmt.Fprintf(&b, "\n%s    key     = \"%s\"", indent, escapeHCLString(f.Key))
+				if f.Operand != "exists" && f.Operand != "not_exists" {
+					fmt.Fprintf(&b, "\n%s    value   = \"%s\"", indent, escapeHCLString(f.Value))
+				}
+				fmt.Fprintf(&b, "\n%s    operand = \"%s\"", indent, escapeHCLString(f.Operand))
+			}
+			fmt.Fprintf(&b, "\n%s  },", indent)
+		}
+		fmt.Fprintf(&b, "\n%s]", indent)
+	}
+	return b.String()
+}
+
+// filtersWithOperand returns the filters with the operand, the ones that are neither "eq" nor
+// "neq" if operand is empty, following the include_filters, exclude_filters and filters split
+// of lightstep_metric_condition queries
+func filtersWithOperand(filters []client.LabelFilter, operand string) []client.LabelFilter {
+	var matching []client.LabelFilter
+	for _, f := range filters {
+		if f.Operand == operand || (operand == "" && f.Operand != "eq" && f.Operand != "neq") {
+			matching = append(matching, f)
+		}
+	}
+	return matching
+}
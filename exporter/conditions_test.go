@@ -0,0 +1,143 @@
+package exporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestExportConditionToHCL(t *testing.T) {
+	critical, warning := 10.5, 5.0
+	window := 3600000
+	condition := &client.UnifiedCondition{
+		ID: "c1",
+		Attributes: client.UnifiedConditionAttributes{
+			Name:        "Too many requests",
+			Description: "Requests of the \"checkout\" service",
+			Labels:      []client.Label{{Key: "team", Value: "payments"}, {Value: "critical"}},
+			Expression: &client.Expression{
+				SubAlertExpression: client.SubAlertExpression{
+					Operand:    "above",
+					Thresholds: client.Thresholds{Critical: &critical, Warning: &warning},
+				},
+				IsMulti: true,
+			},
+			Queries: []client.MetricQueryWithAttributes{{
+				Name:    "a",
+				Type:    "single",
+				Display: "line",
+				Query: client.MetricQuery{
+					Metric:                          "requests",
+					TimeseriesOperator:              "rate",
+					TimeseriesOperatorInputWindowMs: &window,
+					Filters: []client.LabelFilter{
+						{Key: "service", Value: "checkout", Operand: "eq"},
+						{Key: "region", Value: "eu-west-1", Operand: "neq"},
+						{Key: "method", Value: "^GET", Operand: "regexp"},
+						{Key: "canary", Operand: "exists"},
+					},
+					GroupBy:              client.GroupBy{LabelKeys: []string{"method"}, Aggregation: "sum"},
+					FinalWindowOperation: &client.FinalWindowOperation{Operator: "min", InputWindowMs: 30000},
+				},
+			}},
+			AlertingRules: []client.AlertingRule{
+				{MessageDestinationID: "d1", UpdateInterval: 3600000},
+				{
+					MessageDestinationID: "d2",
+					MatchOn:              client.MatchOn{GroupBy: []client.LabelFilter{{Key: "method", Value: "GET", Operand: "eq"}}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exportConditionToHCL(&buf, "terraform-shop", condition))
+	out := buf.String()
+
+	for _, expected := range []string{
+		`resource "lightstep_metric_condition" "exported_condition" {`,
+		`project_name = "terraform-shop"`,
+		`name = "Too many requests"`,
+		`description = <<EOT
+Requests of the "checkout" service
+EOT`,
+		`    key   = "team"`,
+		`    is_multi   = true`,
+		`    operand    = "above"`,
+		`      critical = "10.5"`,
+		`      warning  = "5"`,
+		`    metric              = "requests"`,
+		`    timeseries_operator_input_window_ms = 3600000`,
+		`    include_filters = [
+      {
+        key   = "service"
+        value = "checkout"
+      },
+    ]`,
+		`        key   = "region"`,
+		`        operand = "regexp"`,
+		`      {
+        key     = "canary"
+        operand = "exists"
+      },`,
+		`      keys               = ["method"]`,
+		`      input_window_ms = 30000`,
+		`    id = "d1"
+    update_interval = "1h"`,
+		`    id = "d2"
+    include_filters = [`,
+	} {
+		assert.Contains(t, out, expected)
+	}
+	assert.NotContains(t, out, "tql", "only the fields of the query's type are written")
+
+	_, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "condition.tf")
+	assert.False(t, diags.HasErrors(), "resulting HCL does not parse: %v", diags)
+}
+
+func TestExportSpansConditionToHCL(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportConditionToHCL(&buf, "terraform-shop", &client.UnifiedCondition{
+		Attributes: client.UnifiedConditionAttributes{
+			Name:       "Slow checkouts",
+			Expression: &client.Expression{SubAlertExpression: client.SubAlertExpression{IsNoData: true}},
+			Queries: []client.MetricQueryWithAttributes{{
+				Name:    "a",
+				Type:    "spans_single",
+				Display: "line",
+				SpansQuery: client.SpansQuery{
+					Query:                `"service" IN ("checkout")`,
+					Operator:             "latency",
+					LatencyPercentiles:   []float64{50, 99.9},
+					FinalWindowOperation: &client.FinalWindowOperation{Operator: "max", InputWindowMs: 60000},
+				},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `      query    = "\"service\" IN (\"checkout\")"`)
+	assert.Contains(t, out, `      latency_percentiles = [50, 99.9]`)
+	assert.Contains(t, out, `      operator        = "max"`)
+	assert.Contains(t, out, `    is_no_data = true`)
+	assert.NotContains(t, out, "thresholds", "empty thresholds are left out")
+	assert.NotContains(t, out, "alerting_rule")
+
+	_, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "condition.tf")
+	assert.False(t, diags.HasErrors(), "resulting HCL does not parse: %v", diags)
+}
+
+func TestExportCompositeConditionToHCL(t *testing.T) {
+	err := exportConditionToHCL(&bytes.Buffer{}, "terraform-shop", &client.UnifiedCondition{
+		ID:         "c1",
+		Attributes: client.UnifiedConditionAttributes{CompositeAlert: &client.CompositeAlert{}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "composite alert")
+}
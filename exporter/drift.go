@@ -0,0 +1,116 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// dashboardDrift lists the dashboards of a source project, by name, that are missing from a
+// target project or differ there
+type dashboardDrift struct {
+	Missing   []string
+	Different []string
+}
+
+func (d dashboardDrift) empty() bool {
+	return len(d.Missing) == 0 && len(d.Different) == 0
+}
+
+// diffProjectDashboards compares the dashboards of the source project with the dashboards of
+// the same name in the target project. Dashboards differ if their exported configurations do,
+// so IDs, versions and the project they're in are ignored.
+func diffProjectDashboards(ctx context.Context, c *client.Client, source string, target string) (dashboardDrift, error) {
+	var drift dashboardDrift
+
+	sourceConfigs, err := projectDashboardConfigs(ctx, c, source)
+	if err != nil {
+		return drift, fmt.Errorf("could not read the dashboards of project %v: %v", source, err)
+	}
+	targetConfigs, err := projectDashboardConfigs(ctx, c, target)
+	if err != nil {
+		return drift, fmt.Errorf("could not read the dashboards of project %v: %v", target, err)
+	}
+
+	names := make([]string, 0, len(sourceConfigs))
+	for name := range sourceConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		configs, ok := targetConfigs[name]
+		if !ok {
+			drift.Missing = append(drift.Missing, name)
+			continue
+		}
+		for _, config := range sourceConfigs[name] {
+			if !containsConfig(configs, config) {
+				drift.Different = append(drift.Different, name)
+				break
+			}
+		}
+	}
+	return drift, nil
+}
+
+// projectDashboardConfigs returns the exported configurations of the dashboards of a project
+// by dashboard name, several dashboards may have the same name
+func projectDashboardConfigs(ctx context.Context, c *client.Client, project string) (map[string][][]byte, error) {
+	dashboards, err := c.ListUnifiedDashboards(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("could not list dashboards: %v", err)
+	}
+
+	configs := make(map[string][][]byte, len(dashboards))
+	for _, listed := range dashboards {
+		// the list response doesn't necessarily include the full dashboard definition
+		d, err := c.GetUnifiedDashboard(ctx, project, listed.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get dashboard %v: %v", listed.ID, err)
+		}
+
+		var buf bytes.Buffer
+		if err := renderHCL(&buf, d, exportOptions{}); err != nil {
+			return nil, fmt.Errorf("could not export dashboard %v: %v", listed.ID, err)
+		}
+		configs[d.Attributes.Name] = append(configs[d.Attributes.Name], buf.Bytes())
+	}
+	return configs, nil
+}
+
+func containsConfig(configs [][]byte, config []byte) bool {
+	for _, c := range configs {
+		if bytes.Equal(c, config) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDriftSummary writes which dashboards of the source project are missing or differ in the
+// target project
+func writeDriftSummary(wr io.Writer, source string, target string, drift dashboardDrift) error {
+	if drift.empty() {
+		_, err := fmt.Fprintf(wr, "project %v has all the dashboards of project %v\n", target, source)
+		return err
+	}
+
+	for _, name := range drift.Missing {
+		if _, err := fmt.Fprintf(wr, "missing:   %q\n", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range drift.Different {
+		if _, err := fmt.Fprintf(wr, "different: %q\n", name); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(wr, "%d missing and %d different dashboards of project %v in project %v\n",
+		len(drift.Missing), len(drift.Different), source, target)
+	return err
+}
@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureMigration serves a source project with three dashboards and a target project where
+// one of them is missing and another has a different query
+var fixtureMigration = map[string]string{
+	"/public/v0.2/my-org/projects/shop/metric_dashboards": `{"data": [
+		{"id": "d1", "type": "dashboard", "attributes": {"name": "Checkout"}},
+		{"id": "d2", "type": "dashboard", "attributes": {"name": "Latency"}},
+		{"id": "d3", "type": "dashboard", "attributes": {"name": "Errors"}}
+	]}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d1": `{"data": {"id": "d1", "type": "dashboard", "attributes": {
+		"name": "Checkout",
+		"charts": [{"id": "c1", "title": "Requests", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric requests | rate"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d2": `{"data": {"id": "d2", "type": "dashboard", "attributes": {
+		"name": "Latency",
+		"charts": [{"id": "c2", "title": "p99", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric latency | delta | group_by [], sum | point percentile(value, 99.0)"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d3": `{"data": {"id": "d3", "type": "dashboard", "attributes": {
+		"name": "Errors",
+		"charts": [{"id": "c3", "title": "Errors", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric errors | rate"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop-staging/metric_dashboards": `{"data": [
+		{"id": "s1", "type": "dashboard", "attributes": {"name": "Checkout"}},
+		{"id": "s3", "type": "dashboard", "attributes": {"name": "Errors"}}
+	]}`,
+	// the same dashboard under other IDs
+	"/public/v0.2/my-org/projects/shop-staging/metric_dashboards/s1": `{"data": {"id": "s1", "type": "dashboard", "attributes": {
+		"name": "Checkout",
+		"charts": [{"id": "x1", "title": "Requests", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric requests | rate"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/shop-staging/metric_dashboards/s3": `{"data": {"id": "s3", "type": "dashboard", "attributes": {
+		"name": "Errors",
+		"charts": [{"id": "x3", "title": "Errors", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric errors | delta"}
+		]}]
+	}}}`,
+}
+
+func TestDiffProjectDashboards(t *testing.T) {
+	c := fixtureClient(t, fixtureMigration)
+
+	drift, err := diffProjectDashboards(context.Background(), c, "shop", "shop-staging")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Latency"}, drift.Missing)
+	assert.Equal(t, []string{"Errors"}, drift.Different)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeDriftSummary(&buf, "shop", "shop-staging", drift))
+	assert.Equal(t, "missing:   \"Latency\"\n"+
+		"different: \"Errors\"\n"+
+		"1 missing and 1 different dashboards of project shop in project shop-staging\n", buf.String())
+
+	// the target has no dashboards the source doesn't have
+	drift, err = diffProjectDashboards(context.Background(), c, "shop-staging", "shop")
+	require.NoError(t, err)
+	assert.Empty(t, drift.Missing)
+	assert.Equal(t, []string{"Errors"}, drift.Different)
+}
+
+func TestDiffProjectDashboardsNoDrift(t *testing.T) {
+	c := fixtureClient(t, fixtureMigration)
+
+	drift, err := diffProjectDashboards(context.Background(), c, "shop", "shop")
+	require.NoError(t, err)
+	assert.True(t, drift.empty())
+
+	var buf bytes.Buffer
+	require.NoError(t, writeDriftSummary(&buf, "shop", "shop", drift))
+	assert.Equal(t, "project shop has all the dashboards of project shop\n", buf.String())
+}
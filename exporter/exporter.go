@@ -1,56 +1,138 @@
 package exporter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 )
 
 const metricDashboardTemplate = `
-resource "lightstep_metric_dashboard" "exported_dashboard" {
-  project_name = var.project
-  dashboard_name = "{{.Attributes.Name}}"
+resource "lightstep_metric_dashboard" "{{resourceName}}" {
+  project_name = {{projectName}}
+  dashboard_name = "{{escapeHCLString .Attributes.Name}}"
   dashboard_description = {{escapeHeredocString .Attributes.Description}}
-{{range .Attributes.Charts}}
+{{- if .Attributes.Locked}}
+  protected = true
+{{- end}}
+{{- if .Attributes.TimeRange}}
+  time_range = "{{escapeHCLString .Attributes.TimeRange}}"
+{{- end}}
+{{- with .Attributes.AbsoluteTimeRange}}
+
+  absolute_time_range {
+    start = "{{formatTimestamp .Start}}"
+    end   = "{{formatTimestamp .End}}"
+  }
+{{- end}}
+{{range .Attributes.TemplateVariables}}
+  template_variable {
+    name                     = "{{escapeHCLString .Name}}"
+    suggestion_attribute_key = "{{escapeHCLString .SuggestionAttributeKey}}"
+    default_values           = {{templateVariableDefaults .}}
+  }
+{{end}}{{range .Attributes.TemplateVariablePresets}}
+  preset {
+    name = "{{escapeHCLString .Name}}"
+{{- range .Variables}}
+
+    variable {
+      name   = "{{escapeHCLString .Name}}"
+      values = {{hclStringList .Values}}
+    }
+{{- end}}
+  }
+{{end}}{{range .Attributes.EventOverlays}}
+  event_overlay {
+    name   = "{{escapeHCLString .Name}}"
+    source = "{{escapeHCLString .Source}}"
+{{- if .Query}}
+    query  = {{escapeHeredocString .Query}}
+{{- end}}
+  }
+{{end}}{{range .Attributes.LinkedDashboards}}
+  linked_dashboard {
+    dashboard_id = "{{escapeHCLString .ID}}"
+  }
+{{end}}{{with .Attributes.DefaultGroupBy}}
+  default_group_by {
+    keys = {{hclStringList .LabelKeys}}
+{{- if .Aggregation}}
+    aggregation_method = "{{escapeHCLString .Aggregation}}"
+{{- end}}
+  }
+{{end}}{{range .Attributes.Charts}}
   chart {
-    name = "{{.Title}}"
+    name = "{{escapeHCLString .Title}}"
     rank = "{{.Rank}}"
-    type = "{{.ChartType}}"
+    type = "{{escapeHCLString .ChartType}}"
+{{- if .Description}}
+    description = {{escapeHeredocString .Description}}
+{{- end}}
+{{- with stringValue .Subtitle}}
+    subtitle = "{{escapeHCLString .}}"
+{{- end}}
+{{- with .Precision}}
+    precision = {{.}}
+{{- end}}
+{{- if .ShowSparkLine}}
+    show_spark_line = true
+{{- end}}
+{{- with .DisplaySettings}}
+{{- if .Unit}}
+    display_unit = "{{escapeHCLString .Unit}}"
+{{- end}}
+{{- if .Scale}}
+    display_scale = {{.Scale}}
+{{- end}}
+{{- end}}
 {{range .MetricQueries}}
     query {
-      query_name          = "{{.Name}}"
-      display             = "{{.Display}}"
+      query_name          = "{{escapeHCLString .Name}}"
+      display             = "{{escapeHCLString .Display}}"
       hidden              = {{.Hidden}}
+{{- if .TimeShift}}
+      time_shift          = "{{escapeHCLString .TimeShift}}"
+{{- end}}
+{{- if .DisplayLabel}}
+      alias               = "{{escapeHCLString .DisplayLabel}}"
+{{- end}}
+{{- if .Baseline}}
+      baseline {
+        lookback = "{{escapeHCLString .Baseline.Lookback}}"
+{{- if .Baseline.Sensitivity}}
+        sensitivity = "{{escapeHCLString .Baseline.Sensitivity}}"
+{{- end}}
+      }
+{{- end}}
 {{if (and .SpansQuery .SpansQuery.Query) }}
       spans {
          query         = "{{escapeHCLString .SpansQuery.Query}}"
-         operator      = "{{.SpansQuery.Operator}}"
-         group_by_keys = [{{range .SpansQuery.GroupByKeys}}"{{.}}",{{end}}]{{if eq .SpansQuery.Operator "latency"}}
+         operator      = "{{escapeHCLString .SpansQuery.Operator}}"
+         group_by_keys = {{hclStringList .SpansQuery.GroupByKeys}}{{if eq .SpansQuery.Operator "latency"}}
          latency_percentiles = [{{range .SpansQuery.LatencyPercentiles}}{{.}},{{end}}]{{end}}
       }
 {{end}}{{if .TQLQuery}}
       tql                 = {{escapeHeredocString .TQLQuery}}
 {{end}}{{if .Query.Metric}}
-      metric              = "{{.Query.Metric}}"
-      timeseries_operator = "{{.Query.TimeseriesOperator}}"
-{{if .Query.Filters}}
-      include_filters = [{{range .Query.Filters}}
-        {
-          key   = "{{.Key}}"
-          value = "{{.Value}}"
-        },{{end}}
-      ]
-{{end}}
+      metric              = "{{escapeHCLString .Query.Metric}}"
+      timeseries_operator = "{{escapeHCLString .Query.TimeseriesOperator}}"
+{{- hclQueryFilters .Query.Filters "      "}}
 {{if .Query.GroupBy}}
       group_by {
-        aggregation_method = "{{.Query.GroupBy.Aggregation}}"
-        keys = [{{range .Query.GroupBy.LabelKeys}}"{{.}}",{{end}}]
+        aggregation_method = "{{escapeHCLString .Query.GroupBy.Aggregation}}"
+        keys = {{hclStringList .Query.GroupBy.LabelKeys}}
       }{{end}}
 {{end}}
     }
@@ -61,25 +143,108 @@ resource "lightstep_metric_dashboard" "exported_dashboard" {
 `
 
 const unifiedDashboardTemplate = `
-resource "lightstep_dashboard" "exported_dashboard" {
-  project_name = var.project
-  dashboard_name = "{{.Attributes.Name}}"
+resource "lightstep_dashboard" "{{resourceName}}" {
+  project_name = {{projectName}}
+  dashboard_name = "{{escapeHCLString .Attributes.Name}}"
   dashboard_description = {{escapeHeredocString .Attributes.Description}}
-{{range .Attributes.Charts}}
+{{- if .Attributes.Locked}}
+  protected = true
+{{- end}}
+{{- if .Attributes.TimeRange}}
+  time_range = "{{escapeHCLString .Attributes.TimeRange}}"
+{{- end}}
+{{- with .Attributes.AbsoluteTimeRange}}
+
+  absolute_time_range {
+    start = "{{formatTimestamp .Start}}"
+    end   = "{{formatTimestamp .End}}"
+  }
+{{- end}}
+{{range .Attributes.TemplateVariables}}
+  template_variable {
+    name                     = "{{escapeHCLString .Name}}"
+    suggestion_attribute_key = "{{escapeHCLString .SuggestionAttributeKey}}"
+    default_values           = {{templateVariableDefaults .}}
+  }
+{{end}}{{range .Attributes.TemplateVariablePresets}}
+  preset {
+    name = "{{escapeHCLString .Name}}"
+{{- range .Variables}}
+
+    variable {
+      name   = "{{escapeHCLString .Name}}"
+      values = {{hclStringList .Values}}
+    }
+{{- end}}
+  }
+{{end}}{{range .Attributes.EventOverlays}}
+  event_overlay {
+    name   = "{{escapeHCLString .Name}}"
+    source = "{{escapeHCLString .Source}}"
+{{- if .Query}}
+    query  = {{escapeHeredocString .Query}}
+{{- end}}
+  }
+{{end}}{{range .Attributes.LinkedDashboards}}
+  linked_dashboard {
+    dashboard_id = "{{escapeHCLString .ID}}"
+  }
+{{end}}{{with .Attributes.DefaultGroupBy}}
+  default_group_by {
+    keys = {{hclStringList .LabelKeys}}
+{{- if .Aggregation}}
+    aggregation_method = "{{escapeHCLString .Aggregation}}"
+{{- end}}
+  }
+{{end}}{{range .Attributes.Charts}}
   chart {
-    name = "{{.Title}}"
+    name = "{{escapeHCLString .Title}}"
     rank = "{{.Rank}}"
-    type = "{{.ChartType}}"
+    type = "{{escapeHCLString .ChartType}}"
+{{- if .Description}}
+    description = {{escapeHeredocString .Description}}
+{{- end}}
+{{- with stringValue .Subtitle}}
+    subtitle = "{{escapeHCLString .}}"
+{{- end}}
+{{- with .Precision}}
+    precision = {{.}}
+{{- end}}
+{{- if .ShowSparkLine}}
+    show_spark_line = true
+{{- end}}
+{{- with .DisplaySettings}}
+{{- if .Unit}}
+    display_unit = "{{escapeHCLString .Unit}}"
+{{- end}}
+{{- if .Scale}}
+    display_scale = {{.Scale}}
+{{- end}}
+{{- end}}
 {{range .MetricQueries}}
     query {
-      query_name          = "{{.Name}}"
-      display             = "{{.Display}}"
+      query_name          = "{{escapeHCLString .Name}}"
+      display             = "{{escapeHCLString .Display}}"
       hidden              = {{.Hidden}}
       query_string        = {{escapeHeredocString .TQLQuery}}
+{{- if .TimeShift}}
+      time_shift          = "{{escapeHCLString .TimeShift}}"
+{{- end}}
+{{- if .DisplayLabel}}
+      alias               = "{{escapeHCLString .DisplayLabel}}"
+{{- end}}
+{{- if .Baseline}}
+      baseline {
+        lookback = "{{escapeHCLString .Baseline.Lookback}}"
+{{- if .Baseline.Sensitivity}}
+        sensitivity = "{{escapeHCLString .Baseline.Sensitivity}}"
+{{- end}}
+      }
+{{- end}}
 {{- if .DependencyMapOptions}}
       dependency_map_options {
-        scope    = "{{.DependencyMapOptions.Scope}}"
-        map_type = "{{.DependencyMapOptions.MapType}}"
+        scope    = "{{escapeHCLString .DependencyMapOptions.Scope}}"
+        map_type = "{{escapeHCLString .DependencyMapOptions.MapType}}"
       }
 {{- end}}
     }
@@ -89,14 +254,68 @@ resource "lightstep_dashboard" "exported_dashboard" {
 }
 `
 
+const moduleVariablesTemplate = `variable "project" {
+  description = "Name of the Lightstep project to create the dashboard in"
+  type        = string
+}
+{{range .Attributes.TemplateVariables}}
+variable "{{variableName .Name}}" {
+  description = "Default values of the {{escapeHCLString .Name}} template variable"
+  type        = list(string)
+  default     = {{hclStringList .DefaultValues}}
+}
+{{end}}`
+
+const moduleOutputsTemplate = `output "dashboard_id" {
+  description = "ID of the dashboard"
+  value       = {{.DashboardIDRef}}
+}
+
+output "dashboard_url" {
+  description = "URL of the dashboard in the Lightstep UI"
+  value       = "https://app.lightstep.com/{{escapeHCLString .OrgName}}/dashboard/${ {{- .DashboardIDRef}}}"
+}
+`
+
+// exportOptions controls how a dashboard is rendered as HCL
+type exportOptions struct {
+	// moduleVariables renders template variable default values as references to
+	// module input variables (see moduleVariablesTemplate) instead of literals
+	moduleVariables bool
+	// variableNames are the names of the module input variables by template variable name,
+	// see moduleVariableNames
+	variableNames map[string]string
+	// resourceName is the name of the Terraform resource, "exported_dashboard" if empty
+	resourceName string
+	// projectName is rendered as the literal project_name if set, otherwise var.project is used
+	projectName string
+}
+
+// stringValue returns the string s points to, or "" if it's nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// escapeHCLString escapes input to be written in a double-quoted HCL string
 func escapeHCLString(input string) string {
 	// Escape "\" first so other the other escape codes don't get escaped
 	input = strings.Replace(input, "\\", "\\\\", -1)
 
 	input = strings.Replace(input, "\"", "\\\"", -1)
-	input = strings.Replace(input, "\n", "\\\t", -1)
-	input = strings.Replace(input, "\r", "\\\t", -1)
-	input = strings.Replace(input, "\t", "\\\t", -1)
+	input = strings.Replace(input, "\n", "\\n", -1)
+	input = strings.Replace(input, "\r", "\\r", -1)
+	input = strings.Replace(input, "\t", "\\t", -1)
+	return escapeHCLTemplate(input)
+}
+
+// escapeHCLTemplate escapes the template sequences of input, which HCL would otherwise
+// interpolate in quoted strings and heredocs
+func escapeHCLTemplate(input string) string {
+	input = strings.Replace(input, "${", "$${", -1)
+	input = strings.Replace(input, "%{", "%%{", -1)
 	return input
 }
 
@@ -108,12 +327,21 @@ func escapeHeredocString(input string) string {
 		strings.Contains(input, "\r") ||
 		strings.Contains(input, "\t") ||
 		strings.Contains(input, "\\") {
-		return "<<EOT\n" + input + "\nEOT"
+		return "<<EOT\n" + escapeHCLTemplate(input) + "\nEOT"
 	} else {
-		// No need to escape input since the other branch should be hit for any strings requiring
-		// escaping.
-		return `"` + input + `"`
+		// No need to escape input beyond its template sequences since the other branch should
+		// be hit for any strings requiring escaping.
+		return `"` + escapeHCLTemplate(input) + `"`
+	}
+}
+
+// hclStringList renders values as an HCL list of strings
+func hclStringList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, `"`+escapeHCLString(v)+`"`)
 	}
+	return "[" + strings.Join(quoted, ", ") + "]"
 }
 
 // dashboardUsesLegacyQuery returns true if any chart in the dashboard
@@ -132,17 +360,51 @@ func dashboardUsesLegacyQuery(d *client.UnifiedDashboard) bool {
 	return false
 }
 
+// dashboardResourceType returns the type of the resource the dashboard is exported as
+func dashboardResourceType(d *client.UnifiedDashboard) string {
+	// Use the legacy format if any chart uses a legacy query
+	if dashboardUsesLegacyQuery(d) {
+		return "lightstep_metric_dashboard"
+	}
+	return "lightstep_dashboard"
+}
+
 func exportToHCL(wr io.Writer, d *client.UnifiedDashboard) error {
+	return renderHCL(wr, d, exportOptions{})
+}
+
+func renderHCL(wr io.Writer, d *client.UnifiedDashboard, opts exportOptions) error {
 	t := template.New("").Funcs(template.FuncMap{
 		"escapeHCLString":     escapeHCLString,
 		"escapeHeredocString": escapeHeredocString,
+		"hclStringList":       hclStringList,
+		"stringValue":         stringValue,
+		"hclQueryFilters":     hclQueryFilters,
+		"formatTimestamp": func(t time.Time) string {
+			return t.Format(time.RFC3339Nano)
+		},
+		"templateVariableDefaults": func(tv client.TemplateVariable) string {
+			if opts.moduleVariables {
+				return "var." + opts.variableNames[tv.Name]
+			}
+			return hclStringList(tv.DefaultValues)
+		},
+		"resourceName": func() string {
+			if opts.resourceName != "" {
+				return opts.resourceName
+			}
+			return "exported_dashboard"
+		},
+		"projectName": func() string {
+			if opts.projectName != "" {
+				return `"` + escapeHCLString(opts.projectName) + `"`
+			}
+			return "var.project"
+		},
 	})
 
-	usesLegacyQuery := dashboardUsesLegacyQuery(d)
-
-	// Use the legacy format if any chart uses a legacy query
 	var hclTemplate string
-	if usesLegacyQuery {
+	if dashboardResourceType(d) == "lightstep_metric_dashboard" {
 		hclTemplate = metricDashboardTemplate
 	} else {
 		hclTemplate = unifiedDashboardTemplate
@@ -152,14 +414,293 @@ func exportToHCL(wr io.Writer, d *client.UnifiedDashboard) error {
 		return fmt.Errorf("dashboard parsing error: %v", err)
 	}
 
-	err = t.Execute(wr, d)
+	var buf bytes.Buffer
+	err = t.Execute(&buf, d)
 	if err != nil {
 		log.Fatalf("Could not generate template: %v", err)
 	}
+
+	hcl, err := omitComputedAttributes(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = wr.Write(hcl)
+	return err
+}
+
+// exportToModule writes the dashboard as a reusable Terraform module into dir:
+// main.tf holds the dashboard resource, variables.tf the project and template
+// variable inputs and outputs.tf the dashboard id and URL.
+// reservedVariableNames are the input variable names a module can't declare for a template
+// variable: the project variable of the module and the names Terraform reserves
+var reservedVariableNames = map[string]bool{
+	"project": true, "source": true, "version": true, "providers": true, "count": true,
+	"for_each": true, "lifecycle": true, "depends_on": true, "locals": true,
+}
+
+// moduleVariableNames returns the name of the module input variable of each template variable.
+// Characters that aren't allowed in Terraform identifiers are replaced by underscores, and names
+// that are reserved, start with a digit or are already taken get a template_ prefix.
+func moduleVariableNames(tvs []client.TemplateVariable) map[string]string {
+	names := make(map[string]string, len(tvs))
+	taken := map[string]bool{}
+	for _, tv := range tvs {
+		name := strings.Map(func(r rune) rune {
+			if r == '_' || r == '-' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+				return r
+			}
+			return '_'
+		}, tv.Name)
+		if name == "" || reservedVariableNames[name] || !(name[0] == '_' || unicode.IsLetter(rune(name[0]))) || taken[name] {
+			name = "template_" + name
+		}
+		for base, i := name, 2; taken[name]; i++ {
+			name = fmt.Sprintf("%v_%d", base, i)
+		}
+		taken[name] = true
+		names[tv.Name] = name
+	}
+	return names
+}
+
+func exportToModule(dir string, orgName string, d *client.UnifiedDashboard) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("could not create module directory: %v", err)
+	}
+
+	variableNames := moduleVariableNames(d.Attributes.TemplateVariables)
+	var mainTF bytes.Buffer
+	if err := renderHCL(&mainTF, d, exportOptions{moduleVariables: true, variableNames: variableNames}); err != nil {
+		return err
+	}
+
+	funcs := template.FuncMap{
+		"escapeHCLString": escapeHCLString,
+		"hclStringList":   hclStringList,
+		"variableName":    func(name string) string { return variableNames[name] },
+	}
+
+	var variablesTF bytes.Buffer
+	t, err := template.New("").Funcs(funcs).Parse(moduleVariablesTemplate)
+	if err != nil {
+		return fmt.Errorf("variables parsing error: %v", err)
+	}
+	if err := t.Execute(&variablesTF, d); err != nil {
+		return fmt.Errorf("could not generate variables: %v", err)
+	}
+
+	var outputsTF bytes.Buffer
+	t, err = template.New("").Funcs(funcs).Parse(moduleOutputsTemplate)
+	if err != nil {
+		return fmt.Errorf("outputs parsing error: %v", err)
+	}
+	err = t.Execute(&outputsTF, struct {
+		OrgName        string
+		DashboardIDRef string
+	}{
+		OrgName:        orgName,
+		DashboardIDRef: dashboardResourceType(d) + ".exported_dashboard.id",
+	})
+	if err != nil {
+		return fmt.Errorf("could not generate outputs: %v", err)
+	}
+
+	for name, content := range map[string][]byte{
+		"main.tf":      mainTF.Bytes(),
+		"variables.tf": variablesTF.Bytes(),
+		"outputs.tf":   outputsTF.Bytes(),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return fmt.Errorf("could not write %s: %v", name, err)
+		}
+	}
 	return nil
 }
 
+// exporterFlags holds the optional command line flags of the exporter
+type exporterFlags struct {
+	moduleDir     string
+	format        string
+	revealSecrets bool
+	outputDir     string
+	allProjects   bool
+	label         string
+	fromFile      string
+	scaffold      bool
+	output        string
+	force         bool
+	tql           bool
+	all           bool
+}
+
+// parseArgs parses the exporter flags, which may be given before, after or in between
+// the positional arguments, and returns the positional arguments in order.
+func parseArgs(args []string) (exporterFlags, []string, error) {
+	var flags exporterFlags
+
+	fs := flag.NewFlagSet("exporter", flag.ContinueOnError)
+	fs.StringVar(&flags.moduleDir, "module-dir", "", "write the dashboard as a reusable module (main.tf, variables.tf, outputs.tf) into this directory")
+	fs.StringVar(&flags.format, "format", "hcl", "output format, one of: hcl, yaml, json (Terraform JSON configuration syntax)")
+	fs.StringVar(&flags.outputDir, "output-dir", "", "directory the dashboards of each project are written to when exporting several projects")
+	fs.BoolVar(&flags.allProjects, "all-projects", false, "export the dashboards of every project in the organization")
+	fs.StringVar(&flags.fromFile, "from-file", "", "render the dashboard from this JSON file (a dashboard API response) instead of calling the API")
+	fs.StringVar(&flags.label, "label", "", "only export the dashboards with this label, written as key:value or value")
+	fs.BoolVar(&flags.scaffold, "scaffold", false, "also write a terraform block pinning the lightstep provider to the version of this exporter")
+	fs.StringVar(&flags.output, "o", "", "write the configuration to this file instead of stdout, creating its directory if needed")
+	fs.BoolVar(&flags.tql, "tql", false, "convert structured metric queries to TQL, exporting the dashboard with query strings only")
+	fs.BoolVar(&flags.force, "force", false, "overwrite the file given with -o if it already exists")
+	fs.BoolVar(&flags.all, "all", false, "export every dashboard of the project into one file instead of a single dashboard")
+	fs.BoolVar(&flags.revealSecrets, "reveal-secrets", false, "write secret values (e.g. tokens in stream custom data) instead of replacing them with sensitive variables")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return flags, nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if flags.format != "hcl" && flags.format != "yaml" && flags.format != "json" {
+		return flags, nil, fmt.Errorf("unsupported format %q, must be one of: hcl, yaml, json", flags.format)
+	}
+	if flags.moduleDir != "" && flags.format != "hcl" {
+		return flags, nil, fmt.Errorf("--module-dir can only be used with the hcl format")
+	}
+	if flags.scaffold && flags.format != "hcl" {
+		return flags, nil, fmt.Errorf("--scaffold can only be used with the hcl format")
+	}
+	if flags.output != "" && (flags.moduleDir != "" || flags.outputDir != "") {
+		return flags, nil, fmt.Errorf("-o can't be used with --module-dir or --output-dir, which write their own files")
+	}
+	if flags.force && flags.output == "" {
+		return flags, nil, fmt.Errorf("-force can only be used with -o")
+	}
+	if flags.all && (flags.moduleDir != "" || flags.format != "hcl" || flags.fromFile != "" || flags.tql) {
+		return flags, nil, fmt.Errorf("--all can only be used with the hcl format, without --module-dir, --from-file or --tql")
+	}
+	return flags, positional, nil
+}
+
+// loadDashboardFile reads a dashboard from a JSON file holding the response of the dashboard
+// API, so the configuration can be generated offline and reproducibly
+func loadDashboardFile(path string) (*client.UnifiedDashboard, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data *client.UnifiedDashboard `json:"data"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return nil, fmt.Errorf("could not parse %v: %v", path, err)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("%v has no dashboard, expected a dashboard API response such as {\"data\": {\"attributes\": ...}}", path)
+	}
+	return resp.Data, nil
+}
+
+// writeDashboard writes the dashboard to wr in the format selected by the flags, or to the
+// module directory
+func writeDashboard(wr io.Writer, flags exporterFlags, orgName string, project string, d *client.UnifiedDashboard) error {
+	if flags.moduleDir != "" {
+		if err := exportToModule(flags.moduleDir, orgName, d); err != nil {
+			return fmt.Errorf("could not export module: %v", err)
+		}
+		if flags.scaffold {
+			var versionsTF bytes.Buffer
+			if err := exportProviderRequirements(&versionsTF); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(flags.moduleDir, "versions.tf"), versionsTF.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("could not write versions.tf: %v", err)
+			}
+		}
+		return nil
+	}
+
+	if flags.format == "yaml" {
+		if err := exportToYAML(wr, project, d); err != nil {
+			return fmt.Errorf("could not export to YAML: %v", err)
+		}
+		return nil
+	}
+	if flags.format == "json" {
+		if err := exportToJSON(wr, d); err != nil {
+			return fmt.Errorf("could not export to JSON: %v", err)
+		}
+		return nil
+	}
+
+	if flags.scaffold {
+		if err := exportProviderRequirements(wr); err != nil {
+			return err
+		}
+	}
+	if err := exportToHCL(wr, d); err != nil {
+		return fmt.Errorf("could not export to HCL: %v", err)
+	}
+	return nil
+}
+
+// supportedResourceTypes are the resource types that can be exported by ID
+var supportedResourceTypes = []string{"dashboard", "lightstep_dashboard", "condition", "lightstep_metric_condition"}
+
 func Run(args ...string) error {
+	flags, positional, err := parseArgs(args[2:])
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	if flags.tql && (flags.fromFile != "" || len(positional) == 0 ||
+		(positional[0] != "dashboard" && positional[0] != "lightstep_dashboard")) {
+		log.Fatalf("error: --tql is only supported when exporting a dashboard from the API")
+	}
+
+	// with -o the configuration is only written once it has been fully rendered, so that a
+	// failure doesn't leave a partial file behind
+	var (
+		out      io.Writer = os.Stdout
+		rendered bytes.Buffer
+	)
+	if flags.output != "" {
+		if len(positional) > 0 && positional[0] == "diff" {
+			log.Fatalf("error: -o is not supported by diff")
+		}
+		if err := checkOutputFile(flags.output, flags.force); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		out = &rendered
+	}
+	done := func() error {
+		if flags.output == "" {
+			return nil
+		}
+		return writeOutputFile(flags.output, rendered.Bytes(), flags.force)
+	}
+
+	// --from-file renders a dashboard without calling the API, so no credentials are needed
+	if flags.fromFile != "" {
+		d, err := loadDashboardFile(flags.fromFile)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		var project string
+		if len(positional) > 0 {
+			project = positional[0]
+		}
+		if err := writeDashboard(out, flags, os.Getenv("LIGHTSTEP_ORG"), project, d); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		return done()
+	}
+
 	if len(os.Getenv("LIGHTSTEP_API_KEY")) == 0 {
 		log.Fatalf("error: LIGHTSTEP_API_KEY env variable must be set")
 	}
@@ -168,30 +709,157 @@ func Run(args ...string) error {
 		log.Fatalf("error: LIGHTSTEP_ORG env variable must be set")
 	}
 
-	// default to public API environment
-	lightstepEnv := "public"
-	if len(os.Getenv("LIGHTSTEP_ENV")) > 0 {
-		lightstepEnv = os.Getenv("LIGHTSTEP_ENV")
+	// the environment is resolved like the environment attribute of the provider
+	lightstepEnv := client.EnvFromEnvironment()
+	if err := client.CheckEnv(lightstepEnv); err != nil {
+		log.Printf("warning: %v", err)
 	}
 
-	if len(args) < 4 {
-		log.Fatalf("usage: %s export [resource-type] [project-name] [resource-id]", args[0])
+	c := client.NewClient(os.Getenv("LIGHTSTEP_API_KEY"), os.Getenv("LIGHTSTEP_ORG"), lightstepEnv)
+
+	// "adopt" exports every supported resource of a project along with import blocks
+	if len(positional) == 2 && positional[0] == "adopt" {
+		if flags.scaffold {
+			if err := exportProviderRequirements(out); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		if err := exportProject(context.Background(), out, c, positional[1], flags.revealSecrets); err != nil {
+			log.Fatalf("Could not export project: %v", err)
+		}
+		return done()
 	}
 
-	if args[2] != "dashboard" && args[2] != "lightstep_dashboard" {
-		log.Fatalf("error: only dashboard resources are supported at this time")
+	// "import" generates the streams of a CSV or JSON file, along with import blocks adopting
+	// the ones that already exist
+	if len(positional) > 0 && positional[0] == "import" {
+		if len(positional) != 3 {
+			log.Fatalf("usage: %s exporter import [--reveal-secrets] [--scaffold] [project-name] [streams.csv|streams.json]", args[0])
+		}
+		definitions, err := loadStreamDefinitions(positional[2])
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		existing, err := c.ListStreams(context.Background(), positional[1])
+		if err != nil {
+			log.Fatalf("error: could not list streams: %v", err)
+		}
+		if flags.scaffold {
+			if err := exportProviderRequirements(out); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		if err := importStreams(out, positional[1], definitions, existing, flags.revealSecrets); err != nil {
+			log.Fatalf("Could not import streams: %v", err)
+		}
+		return done()
 	}
 
-	c := client.NewClient(os.Getenv("LIGHTSTEP_API_KEY"), os.Getenv("LIGHTSTEP_ORG"), lightstepEnv)
-	d, err := c.GetUnifiedDashboard(context.Background(), args[3], args[4])
-	if err != nil {
-		log.Fatalf("error: could not get dashboard: %v", err)
+	// "dashboards" exports the dashboards of several projects into one directory per project
+	if len(positional) > 0 && positional[0] == "dashboards" {
+		if flags.outputDir == "" {
+			log.Fatalf("error: exporting the dashboards of several projects requires --output-dir")
+		}
+		if flags.scaffold {
+			log.Fatalf("error: --scaffold is not supported when exporting several projects")
+		}
+		projects, err := resolveProjects(context.Background(), c, positional[1:], flags.allProjects)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		exported, err := exportProjects(context.Background(), c, flags.outputDir, projects, flags.label)
+		if err != nil {
+			log.Fatalf("Could not export projects: %v", err)
+		}
+		if exported == 0 && flags.label != "" {
+			fmt.Fprintf(os.Stderr, "no dashboards labeled %q in projects %v, nothing was exported\n", flags.label, strings.Join(projects, ", "))
+		}
+		return nil
 	}
 
-	err = exportToHCL(os.Stdout, d)
+	// "diff" reports the dashboards of a source project missing or different in a target project
+	if len(positional) > 0 && positional[0] == "diff" {
+		if len(positional) != 3 {
+			log.Fatalf("usage: %s exporter diff [source-project] [target-project]", args[0])
+		}
+		drift, err := diffProjectDashboards(context.Background(), c, positional[1], positional[2])
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		if err := writeDriftSummary(os.Stdout, positional[1], positional[2], drift); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		if !drift.empty() {
+			log.Fatalf("error: the dashboards of project %v drifted from project %v", positional[2], positional[1])
+		}
+		return nil
+	}
+
+	// "dashboard <project> --all" exports every dashboard of the project into one file
+	if flags.all {
+		if len(positional) != 2 || (positional[0] != "dashboard" && positional[0] != "lightstep_dashboard") {
+			log.Fatalf("usage: %s exporter dashboard --all [-o file [-force]] [--label label] [--scaffold] [project-name]", args[0])
+		}
+		if flags.scaffold {
+			if err := exportProviderRequirements(out); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		exported, err := exportAllDashboards(context.Background(), out, c, positional[1], flags.label)
+		if err != nil {
+			log.Fatalf("Could not export dashboards: %v", err)
+		}
+		if exported == 0 {
+			fmt.Fprintf(os.Stderr, "no dashboards to export in project %v\n", positional[1])
+		}
+		return done()
+	}
+
+	if len(positional) < 3 {
+		log.Fatalf("usage: %s exporter [-o file [-force]] [--module-dir dir] [--format hcl|yaml|json] [--scaffold] [--tql] [resource-type] [project-name] [resource-id]\n"+
+			"       %s exporter dashboard --all [-o file [-force]] [--label label] [--scaffold] [project-name]\n"+
+			"       %s exporter adopt [-o file [-force]] [--reveal-secrets] [--scaffold] [project-name]\n"+
+			"       %s exporter dashboards --output-dir dir [--label label] [--all-projects | project-name...]\n"+
+			"       %s exporter diff [source-project] [target-project]\n"+
+			"       %s exporter import [--reveal-secrets] [--scaffold] [project-name] [streams.csv|streams.json]\n"+
+			"       %s exporter --from-file dashboard.json [--module-dir dir] [--format hcl|yaml|json] [project-name]", args[0], args[0], args[0], args[0], args[0], args[0], args[0])
+	}
+
+	switch positional[0] {
+	case "dashboard", "lightstep_dashboard":
+	case "condition", "lightstep_metric_condition":
+		if flags.moduleDir != "" || flags.format != "hcl" {
+			log.Fatalf("error: conditions can only be exported as HCL, without --module-dir")
+		}
+		cond, err := c.GetUnifiedCondition(context.Background(), positional[1], positional[2])
+		if err != nil {
+			log.Fatalf("error: could not get condition: %v", err)
+		}
+		if flags.scaffold {
+			if err := exportProviderRequirements(out); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		if err := exportConditionToHCL(out, positional[1], cond); err != nil {
+			log.Fatalf("error: could not export to HCL: %v", err)
+		}
+		return done()
+	default:
+		log.Fatalf("error: unsupported resource type %q, must be one of: %v", positional[0], strings.Join(supportedResourceTypes, ", "))
+	}
+
+	d, err := c.GetUnifiedDashboard(context.Background(), positional[1], positional[2])
 	if err != nil {
-		log.Fatalf("Could not export to HCL: %v", err)
+		log.Fatalf("error: could not get dashboard: %v", err)
+	}
+	if flags.tql {
+		if err := convertQueriesToTQL(context.Background(), c, positional[1], d); err != nil {
+			log.Fatalf("error: %v", err)
+		}
 	}
 
-	return nil
+	if err := writeDashboard(out, flags, c.OrgName(), positional[1], d); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	return done()
 }
@@ -3,8 +3,14 @@ package exporter
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 )
@@ -19,6 +25,9 @@ func TestExportToHCL(t *testing.T) {
 	testCases := []struct {
 		QueryString          string
 		DependencyMapOptions *client.DependencyMapOptions
+		TimeShift            string
+		DisplayLabel         string
+		Baseline             *client.Baseline
 		Expected             string
 	}{
 		{
@@ -63,6 +72,30 @@ EOT`,
       dependency_map_options {
         scope    = "all"
         map_type = "service"
+      }`,
+		},
+		{
+			QueryString: "metric requests | rate 10m",
+			TimeShift:   "168h",
+			Expected: `query_string        = "metric requests | rate 10m"
+      time_shift          = "168h"`,
+		},
+		{
+			QueryString:  "metric requests | rate 10m",
+			DisplayLabel: "p99 latency",
+			Expected: `query_string        = "metric requests | rate 10m"
+      alias               = "p99 latency"`,
+		},
+		{
+			QueryString: "metric requests | rate 10m",
+			Baseline: &client.Baseline{
+				Lookback:    "168h",
+				Sensitivity: "high",
+			},
+			Expected: `query_string        = "metric requests | rate 10m"
+      baseline {
+        lookback = "168h"
+        sensitivity = "high"
       }`,
 		},
 	}
@@ -82,6 +115,9 @@ EOT`,
 									Hidden:               false,
 									TQLQuery:             testCase.QueryString,
 									DependencyMapOptions: testCase.DependencyMapOptions,
+									TimeShift:            testCase.TimeShift,
+									DisplayLabel:         testCase.DisplayLabel,
+									Baseline:             testCase.Baseline,
 								},
 							},
 						},
@@ -100,3 +136,550 @@ EOT`,
 		})
 	}
 }
+
+func TestExportDefaultGroupBy(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			DefaultGroupBy: &client.GroupBy{
+				LabelKeys:   []string{"service", "region"},
+				Aggregation: "sum",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `  default_group_by {
+    keys = ["service", "region"]
+    aggregation_method = "sum"
+  }`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("resulting HCL does not contain the default group-by:\n%v", buf.String())
+	}
+}
+
+func TestExportDisplaySettings(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			Charts: []client.UnifiedChart{
+				{
+					Title:           "Memory",
+					ChartType:       "timeseries",
+					DisplaySettings: &client.ChartDisplaySettings{Unit: "MB", Scale: 0.000001},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `    display_unit = "MB"
+    display_scale = 1e-06`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("resulting HCL does not contain the display settings:\n%v", buf.String())
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportPrecision(t *testing.T) {
+	zero, two := 0, 2
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			Charts: []client.UnifiedChart{
+				{Title: "Errors", ChartType: "timeseries", Precision: &two},
+				{Title: "Hosts", ChartType: "timeseries", Precision: &zero},
+				{Title: "Requests", ChartType: "timeseries"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "precision = 2") || !strings.Contains(out, "precision = 0") {
+		t.Errorf("resulting HCL does not contain the precisions:\n%v", out)
+	}
+	if strings.Count(out, "precision") != 2 {
+		t.Errorf("charts without precision shouldn't have one in the resulting HCL:\n%v", out)
+	}
+}
+
+func TestExportSparkLine(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			Charts: []client.UnifiedChart{
+				{Title: "Errors", ChartType: "timeseries", ShowSparkLine: true},
+				{Title: "Requests", ChartType: "timeseries"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Count(out, "show_spark_line = true") != 1 || strings.Count(out, "show_spark_line") != 1 {
+		t.Errorf("only the chart showing a spark line should have one in the resulting HCL:\n%v", out)
+	}
+}
+
+func TestExportTemplateVariablePresets(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			TemplateVariables: []client.TemplateVariable{
+				{Name: "service", SuggestionAttributeKey: "service.name"},
+				{Name: "region", SuggestionAttributeKey: "cloud.region"},
+			},
+			TemplateVariablePresets: []client.TemplateVariablePreset{
+				{Name: "Checkout in us-east-1", Variables: []client.TemplateVariablePresetValue{
+					{Name: "service", Values: []string{"checkout"}},
+					{Name: "region", Values: []string{"us-east-1"}},
+				}},
+				{Name: "Frontends", Variables: []client.TemplateVariablePresetValue{
+					{Name: "service", Values: []string{"web", "ios"}},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`    name = "Checkout in us-east-1"`,
+		`      name   = "region"
+      values = ["us-east-1"]`,
+		`    name = "Frontends"`,
+		`      values = ["web", "ios"]`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("resulting HCL does not contain %q:\n%v", expected, out)
+		}
+	}
+	if strings.Count(out, "preset {") != 2 {
+		t.Errorf("resulting HCL should have two presets:\n%v", out)
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportEventOverlays(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			EventOverlays: []client.EventOverlay{
+				{Name: "Checkout deploys", Source: "deploys", Query: `service = "checkout"`},
+				{Name: "Incidents", Source: "events"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`    name   = "Checkout deploys"
+    source = "deploys"
+    query  = <<EOT
+service = "checkout"
+EOT`,
+		`    name   = "Incidents"
+    source = "events"
+  }`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("resulting HCL does not contain %q:\n%v", expected, out)
+		}
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportAbsoluteTimeRange(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			AbsoluteTimeRange: &client.AbsoluteTimeRange{
+				Start: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 6, 1, 12, 30, 0, 0, time.FixedZone("", 2*60*60)),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `  absolute_time_range {
+    start = "2023-06-01T00:00:00Z"
+    end   = "2023-06-01T12:30:00+02:00"
+  }`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("resulting HCL does not contain %q:\n%v", expected, buf.String())
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportLinkedDashboards(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name:             "Test dashboard",
+			LinkedDashboards: []client.DashboardLink{{ID: "d1"}, {ID: "d2"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`  linked_dashboard {
+    dashboard_id = "d1"
+  }`,
+		`    dashboard_id = "d2"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("resulting HCL does not contain %q:\n%v", expected, out)
+		}
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportChartDescription(t *testing.T) {
+	subtitle, emptySubtitle := "p99", ""
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			Charts: []client.UnifiedChart{
+				{Title: "Latency", ChartType: "timeseries", Description: "Latency of \"checkout\" requests", Subtitle: &subtitle},
+				{Title: "Errors", ChartType: "timeseries", Subtitle: &emptySubtitle},
+				{Title: "Requests", ChartType: "timeseries"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`    description = <<EOT
+Latency of "checkout" requests
+EOT`,
+		`    subtitle = "p99"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("resulting HCL does not contain %q:\n%v", expected, out)
+		}
+	}
+	if strings.Count(out, "    description =") != 1 || strings.Count(out, "subtitle") != 1 {
+		t.Errorf("charts without a description or subtitle shouldn't have one in the resulting HCL:\n%v", out)
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportQueryFilterOperands(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Filters",
+			Charts: []client.UnifiedChart{
+				{
+					Title:     "Requests",
+					ChartType: "timeseries",
+					MetricQueries: []client.MetricQueryWithAttributes{
+						{
+							Name:    "a",
+							Display: "line",
+							Query: client.MetricQuery{
+								Metric:             "requests",
+								TimeseriesOperator: "rate",
+								Filters: []client.LabelFilter{
+									{Key: "service", Value: "checkout", Operand: "eq"},
+									{Key: "region", Value: "us-east", Operand: "neq"},
+									{Key: "canary", Operand: "exists"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		`resource "lightstep_metric_dashboard"`,
+		`      include_filters = [
+        {
+          key   = "service"
+          value = "checkout"
+        },
+      ]`,
+		`      exclude_filters = [
+        {
+          key   = "region"
+          value = "us-east"
+        },
+      ]`,
+		`      filters = [
+        {
+          key     = "canary"
+          operand = "exists"
+        },
+      ]`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("resulting HCL does not contain %q:\n%v", expected, out)
+		}
+	}
+	if strings.Contains(out, `value = ""`) {
+		t.Errorf("filters without a value shouldn't be exported with an empty one:\n%v", out)
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("resulting HCL does not parse: %v", diags)
+	}
+}
+
+func TestExportEscapesStrings(t *testing.T) {
+	name := `My "Prod" \ Dashboard ${var.env}`
+	var buf bytes.Buffer
+	err := exportToHCL(&buf, &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: name,
+			Charts: []client.UnifiedChart{
+				{
+					Title:     "Requests\tby \"service\"",
+					ChartType: "timeseries",
+					MetricQueries: []client.MetricQueryWithAttributes{
+						{
+							Name:    "a",
+							Display: "line",
+							Query: client.MetricQuery{
+								Metric:             "requests",
+								TimeseriesOperator: "rate",
+								Filters:            []client.LabelFilter{{Key: "service", Value: "web \"frontend\"\n", Operand: "eq"}},
+								GroupBy:            client.GroupBy{Aggregation: "sum", LabelKeys: []string{`"quoted"`}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf")
+	if diags.HasErrors() {
+		t.Fatalf("resulting HCL does not parse: %v\n%v", diags, buf.String())
+	}
+	dashboard := f.Body.(*hclsyntax.Body).Blocks[0]
+	value, diags := dashboard.Body.Attributes["dashboard_name"].Expr.Value(nil)
+	if diags.HasErrors() {
+		t.Fatalf("dashboard_name can't be evaluated: %v", diags)
+	}
+	if value.AsString() != name {
+		t.Errorf("expected dashboard_name %q, got %q", name, value.AsString())
+	}
+}
+
+func TestExportToModule(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "module")
+
+	err := exportToModule(dir, "my-org", &client.UnifiedDashboard{
+		ID: "abc123",
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			TemplateVariables: []client.TemplateVariable{
+				{
+					Name:                   "service",
+					DefaultValues:          []string{"api", "web"},
+					SuggestionAttributeKey: "service.name",
+				},
+			},
+			Charts: []client.UnifiedChart{
+				{
+					Title:     "Requests",
+					ChartType: "timeseries",
+					MetricQueries: []client.MetricQueryWithAttributes{
+						{
+							Name:     "a",
+							Display:  "line",
+							TQLQuery: "metric requests | filter service == $service | rate | group_by [], sum",
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"main.tf": {
+			`resource "lightstep_dashboard" "exported_dashboard"`,
+			`default_values           = var.service`,
+		},
+		"variables.tf": {
+			`variable "project"`,
+			`variable "service"`,
+			`default     = ["api", "web"]`,
+		},
+		"outputs.tf": {
+			`value       = lightstep_dashboard.exported_dashboard.id`,
+			`value       = "https://app.lightstep.com/my-org/dashboard/${lightstep_dashboard.exported_dashboard.id}"`,
+		},
+	}
+
+	parser := hclparse.NewParser()
+	for name, substrings := range expected {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("could not read %s: %v", name, err)
+		}
+
+		if _, diags := parser.ParseHCL(content, name); diags.HasErrors() {
+			t.Errorf("%s does not parse: %v\n%s", name, diags, content)
+		}
+
+		for _, substring := range substrings {
+			if !strings.Contains(string(content), substring) {
+				t.Errorf("%s does not contain %q:\n%s", name, substring, content)
+			}
+		}
+	}
+}
+
+func TestExportToModuleVariableNames(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "module")
+
+	err := exportToModule(dir, "my-org", &client.UnifiedDashboard{
+		ID: "abc123",
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			TemplateVariables: []client.TemplateVariable{
+				{Name: "project", DefaultValues: []string{"checkout"}},
+				{Name: `say "hi"`, DefaultValues: []string{"hi"}},
+				{Name: "say__hi_", DefaultValues: []string{"hello"}},
+				{Name: "1st", DefaultValues: []string{"a"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"main.tf": {
+			`default_values           = var.template_project`,
+			`default_values           = var.say__hi_`,
+			`default_values           = var.template_say__hi_`,
+			`default_values           = var.template_1st`,
+		},
+		"variables.tf": {
+			`variable "project"`,
+			`variable "template_project"`,
+			`variable "say__hi_"`,
+			`description = "Default values of the say \"hi\" template variable"`,
+			`variable "template_say__hi_"`,
+			`variable "template_1st"`,
+		},
+	}
+
+	parser := hclparse.NewParser()
+	for name, substrings := range expected {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("could not read %s: %v", name, err)
+		}
+
+		if _, diags := parser.ParseHCL(content, name); diags.HasErrors() {
+			t.Errorf("%s does not parse: %v\n%s", name, diags, content)
+		}
+
+		for _, substring := range substrings {
+			if !strings.Contains(string(content), substring) {
+				t.Errorf("%s does not contain %q:\n%s", name, substring, content)
+			}
+		}
+	}
+}
+
+func TestExportFromFile(t *testing.T) {
+	d, err := loadDashboardFile(filepath.Join("testdata", "dashboard.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := exportToHCL(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+
+	// the expected configuration is checked in next to the fixture
+	expected, err := os.ReadFile(filepath.Join("testdata", "dashboard.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(expected) {
+		t.Errorf("HCL rendered from testdata/dashboard.json doesn't match testdata/dashboard.tf, got:\n%s", buf.String())
+	}
+
+	if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "dashboard.tf"); diags.HasErrors() {
+		t.Errorf("generated config does not parse: %v", diags)
+	}
+}
+
+func TestLoadDashboardFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"invalid.json": `{"data": `,
+		"empty.json":   `{"errors": ["not found"]}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadDashboardFile(path); err == nil {
+			t.Errorf("expected an error loading %s", name)
+		}
+	}
+
+	if _, err := loadDashboardFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error loading a missing file")
+	}
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// checkOutputFile fails if the file the configuration is written to with -o already exists,
+// unless it can be overwritten. It's checked before calling the API so that the exporter
+// fails fast.
+func checkOutputFile(path string, force bool) error {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	case info.IsDir():
+		return fmt.Errorf("%v is a directory", path)
+	case !force:
+		return fmt.Errorf("%v already exists, pass -force to overwrite it", path)
+	}
+	return nil
+}
+
+// writeOutputFile writes the rendered configuration to path, creating its parent directories
+func writeOutputFile(path string, content []byte, force bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create the directory of %v: %v", path, err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%v already exists, pass -force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close() // nolint: errcheck
+		return fmt.Errorf("could not write %v: %v", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputArgs(t *testing.T) {
+	flags, positional, err := parseArgs([]string{"dashboard", "-o", "out/dashboard.tf", "my-project", "abc123", "-force"})
+	require.NoError(t, err)
+	assert.Equal(t, "out/dashboard.tf", flags.output)
+	assert.True(t, flags.force)
+	assert.Equal(t, []string{"dashboard", "my-project", "abc123"}, positional)
+
+	_, _, err = parseArgs([]string{"-force", "dashboard", "my-project", "abc123"})
+	assert.Error(t, err, "-force without -o")
+	_, _, err = parseArgs([]string{"-o", "main.tf", "--module-dir", "out", "dashboard", "my-project", "abc123"})
+	assert.Error(t, err, "-o with --module-dir")
+}
+
+func TestWriteOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "dashboard.tf")
+
+	require.NoError(t, checkOutputFile(path, false))
+	require.NoError(t, writeOutputFile(path, []byte("first"), false), "the parent directories are created")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+
+	err = checkOutputFile(path, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pass -force to overwrite it")
+	require.Error(t, writeOutputFile(path, []byte("second"), false))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content), "the file isn't overwritten")
+
+	require.NoError(t, checkOutputFile(path, true))
+	require.NoError(t, writeOutputFile(path, []byte("second"), true))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	assert.Error(t, checkOutputFile(filepath.Dir(path), true), "directories can't be overwritten")
+}
@@ -0,0 +1,67 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// resolveProjects returns the given projects, or every project of the organization if
+// allProjects is set
+func resolveProjects(ctx context.Context, c *client.Client, projects []string, allProjects bool) ([]string, error) {
+	if !allProjects {
+		if len(projects) == 0 {
+			return nil, fmt.Errorf("no projects given, pass project names or --all-projects")
+		}
+		return projects, nil
+	}
+	if len(projects) > 0 {
+		return nil, fmt.Errorf("project names can't be combined with --all-projects")
+	}
+
+	listed, err := c.ListProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list projects: %v", err)
+	}
+	projects = make([]string, 0, len(listed))
+	for _, p := range listed {
+		projects = append(projects, p.ID)
+	}
+	return projects, nil
+}
+
+// exportProjects writes the dashboards of each project to <dir>/<project>/dashboards.tf and
+// returns how many were exported. If label isn't empty, only the dashboards with that label are
+// exported and projects without any are skipped. The projects are exported one after the other
+// with the same client, so the rate limit applies to the run as a whole.
+func exportProjects(ctx context.Context, c *client.Client, dir string, projects []string, label string) (int, error) {
+	var exported int
+	for _, project := range projects {
+		if project == "" || project == "." || project == ".." || filepath.Base(project) != project {
+			return exported, fmt.Errorf("project name %q can't be used as a directory name", project)
+		}
+
+		var buf bytes.Buffer
+		imports, err := exportProjectDashboards(ctx, &buf, c, project, resourceNames{}, label)
+		if err != nil {
+			return exported, fmt.Errorf("could not export project %v: %v", project, err)
+		}
+		exported += len(imports)
+		if label != "" && len(imports) == 0 {
+			continue
+		}
+
+		projectDir := filepath.Join(dir, project)
+		if err := os.MkdirAll(projectDir, 0o755); err != nil {
+			return exported, fmt.Errorf("could not create directory for project %v: %v", project, err)
+		}
+		if err := os.WriteFile(filepath.Join(projectDir, "dashboards.tf"), buf.Bytes(), 0o644); err != nil {
+			return exported, fmt.Errorf("could not write dashboards of project %v: %v", project, err)
+		}
+	}
+	return exported, nil
+}
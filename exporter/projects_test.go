@@ -0,0 +1,125 @@
+package exporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureProjects serves an organization with two projects with one dashboard each, labeled
+// with the team that owns it
+var fixtureProjects = map[string]string{
+	"/public/v0.2/my-org/projects": `{"data": [
+		{"id": "shop", "type": "project"},
+		{"id": "ops", "type": "project"}
+	]}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards": `{"data": [
+		{"id": "d1", "type": "dashboard", "attributes": {"name": "Checkout",
+			"labels": [{"label_key": "team", "label_value": "payments"}, {"label_key": "", "label_value": "tier1"}]}}
+	]}`,
+	"/public/v0.2/my-org/projects/shop/metric_dashboards/d1": `{"data": {"id": "d1", "type": "dashboard", "attributes": {
+		"name": "Checkout",
+		"labels": [{"label_key": "team", "label_value": "payments"}, {"label_key": "", "label_value": "tier1"}],
+		"charts": [{"title": "Requests", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric requests | rate"}
+		]}]
+	}}}`,
+	"/public/v0.2/my-org/projects/ops/metric_dashboards": `{"data": [
+		{"id": "d9", "type": "dashboard", "attributes": {"name": "Hosts",
+			"labels": [{"label_key": "team", "label_value": "infra"}]}}
+	]}`,
+	"/public/v0.2/my-org/projects/ops/metric_dashboards/d9": `{"data": {"id": "d9", "type": "dashboard", "attributes": {
+		"name": "Hosts",
+		"labels": [{"label_key": "team", "label_value": "infra"}],
+		"charts": [{"title": "CPU", "chart-type": "timeseries", "rank": 0, "metric-queries": [
+			{"query-name": "a", "query-type": "tql", "display-type": "line", "tql-query": "metric cpu.utilization | latest"}
+		]}]
+	}}}`,
+}
+
+func TestExportProjects(t *testing.T) {
+	c := fixtureClient(t, fixtureProjects)
+
+	projects, err := resolveProjects(context.Background(), c, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop", "ops"}, projects)
+
+	dir := t.TempDir()
+	exported, err := exportProjects(context.Background(), c, dir, projects, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported)
+
+	shop, err := os.ReadFile(filepath.Join(dir, "shop", "dashboards.tf"))
+	require.NoError(t, err)
+	ops, err := os.ReadFile(filepath.Join(dir, "ops", "dashboards.tf"))
+	require.NoError(t, err)
+
+	for name, content := range map[string][]byte{"shop": shop, "ops": ops} {
+		_, diags := hclparse.NewParser().ParseHCL(content, name+".tf")
+		require.False(t, diags.HasErrors(), "%s does not parse: %v", name, diags)
+	}
+
+	assert.Contains(t, string(shop), `resource "lightstep_dashboard" "checkout"`)
+	assert.Contains(t, string(shop), `project_name = "shop"`)
+	assert.NotContains(t, string(shop), "Hosts")
+
+	assert.Contains(t, string(ops), `resource "lightstep_dashboard" "hosts"`)
+	assert.Contains(t, string(ops), `project_name = "ops"`)
+	assert.NotContains(t, string(ops), "Checkout")
+}
+
+func TestExportProjectsByLabel(t *testing.T) {
+	// only the dashboards whose labels match in the list response are fetched
+	fixtures := map[string]string{}
+	for path, body := range fixtureProjects {
+		fixtures[path] = body
+	}
+	delete(fixtures, "/public/v0.2/my-org/projects/ops/metric_dashboards/d9")
+	c := fixtureClient(t, fixtures)
+	projects := []string{"shop", "ops"}
+
+	for _, label := range []string{"team:payments", "tier1"} {
+		dir := t.TempDir()
+		exported, err := exportProjects(context.Background(), c, dir, projects, label)
+		require.NoError(t, err)
+		assert.Equal(t, 1, exported, label)
+
+		shop, err := os.ReadFile(filepath.Join(dir, "shop", "dashboards.tf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(shop), `resource "lightstep_dashboard" "checkout"`)
+
+		_, err = os.Stat(filepath.Join(dir, "ops"))
+		assert.True(t, os.IsNotExist(err), "projects without matching dashboards are skipped")
+	}
+
+	dir := t.TempDir()
+	exported, err := exportProjects(context.Background(), c, dir, projects, "team:search")
+	require.NoError(t, err)
+	assert.Equal(t, 0, exported)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is written when no dashboard matches")
+}
+
+func TestResolveProjects(t *testing.T) {
+	projects, err := resolveProjects(context.Background(), nil, []string{"shop", "ops"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop", "ops"}, projects)
+
+	_, err = resolveProjects(context.Background(), nil, nil, false)
+	assert.Error(t, err)
+
+	_, err = resolveProjects(context.Background(), nil, []string{"shop"}, true)
+	assert.Error(t, err)
+}
+
+func TestExportProjectsInvalidName(t *testing.T) {
+	c := fixtureClient(t, fixtureProjects)
+	_, err := exportProjects(context.Background(), c, t.TempDir(), []string{"../shop"}, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/lightstep/terraform-provider-lightstep/version"
+)
+
+// providerRequirementsTemplate pins the provider to the version that performed the export, so
+// the exported configuration is applied with the provider that generated it. Development builds
+// have no release to pin, so the version is left out.
+const providerRequirementsTemplate = `terraform {
+  required_providers {
+    lightstep = {
+      source  = "lightstep/lightstep"
+{{- if .}}
+      version = "{{escapeHCLString .}}"
+{{- end}}
+    }
+  }
+}
+`
+
+// exportProviderRequirements writes a terraform block requiring the lightstep provider at
+// version.ProviderVersion, or at any version for development builds
+func exportProviderRequirements(wr io.Writer) error {
+	t, err := template.New("").Funcs(template.FuncMap{"escapeHCLString": escapeHCLString}).Parse(providerRequirementsTemplate)
+	if err != nil {
+		return fmt.Errorf("provider requirements parsing error: %v", err)
+	}
+	providerVersion := version.ProviderVersion
+	if providerVersion == "dev" {
+		providerVersion = ""
+	}
+	if err := t.Execute(wr, providerVersion); err != nil {
+		return fmt.Errorf("could not generate provider requirements: %v", err)
+	}
+	return nil
+}
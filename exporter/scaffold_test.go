@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+	"github.com/lightstep/terraform-provider-lightstep/version"
+)
+
+// setProviderVersion overrides version.ProviderVersion for the duration of the test
+func setProviderVersion(t *testing.T, v string) {
+	previous := version.ProviderVersion
+	version.ProviderVersion = v
+	t.Cleanup(func() { version.ProviderVersion = previous })
+}
+
+func TestExportProviderRequirements(t *testing.T) {
+	for _, tc := range []struct {
+		providerVersion string
+		expected        string
+	}{
+		{"1.70.0", `version = "1.70.0"`},
+		// development builds have no release to pin
+		{"dev", ""},
+	} {
+		t.Run(tc.providerVersion, func(t *testing.T) {
+			setProviderVersion(t, tc.providerVersion)
+
+			var buf bytes.Buffer
+			if err := exportProviderRequirements(&buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "versions.tf"); diags.HasErrors() {
+				t.Errorf("provider requirements do not parse: %v\n%s", diags, buf.String())
+			}
+			if !strings.Contains(buf.String(), `source  = "lightstep/lightstep"`) {
+				t.Errorf("provider requirements do not contain the source:\n%s", buf.String())
+			}
+			if tc.expected == "" && strings.Contains(buf.String(), "version") {
+				t.Errorf("provider requirements shouldn't constrain the version:\n%s", buf.String())
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("provider requirements do not contain %q:\n%s", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestScaffoldModule(t *testing.T) {
+	setProviderVersion(t, "1.70.0")
+	dir := filepath.Join(t.TempDir(), "module")
+	flags := exporterFlags{moduleDir: dir, format: "hcl", scaffold: true}
+
+	err := writeDashboard(io.Discard, flags, "my-org", "", &client.UnifiedDashboard{
+		ID:         "abc123",
+		Attributes: client.UnifiedDashboardAttributes{Name: "Test dashboard"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		t.Fatalf("could not read versions.tf: %v", err)
+	}
+	if !strings.Contains(string(content), `version = "1.70.0"`) {
+		t.Errorf("versions.tf does not pin version 1.70.0:\n%s", content)
+	}
+}
+
+func TestParseArgsScaffold(t *testing.T) {
+	flags, _, err := parseArgs([]string{"dashboard", "--scaffold", "my-project", "abc123"})
+	if err != nil || !flags.scaffold {
+		t.Errorf("expected --scaffold to be set, got %v (err: %v)", flags.scaffold, err)
+	}
+
+	_, _, err = parseArgs([]string{"--scaffold", "--format", "yaml", "dashboard", "my-project", "abc123"})
+	if err == nil {
+		t.Errorf("expected --scaffold with the yaml format to fail")
+	}
+}
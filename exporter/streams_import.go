@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+	"github.com/lightstep/terraform-provider-lightstep/lightstep"
+)
+
+// streamDefinition is a stream read from a CSV or JSON file, e.g. a spreadsheet of the streams
+// of a team
+type streamDefinition struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// CustomData is keyed by the name of the custom data object, like in the API
+	CustomData map[string]map[string]string `json:"custom_data,omitempty"`
+}
+
+// loadStreamDefinitions reads the streams of a .csv or .json file and validates them
+func loadStreamDefinitions(path string) ([]streamDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	var streams []streamDefinition
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		streams, err = parseStreamsCSV(f)
+	case ".json":
+		streams, err = parseStreamsJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported file %v, expected a .csv or .json file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %v: %v", path, err)
+	}
+	return streams, nil
+}
+
+// parseStreamsCSV reads streams from a CSV file with a header row naming its name, query and
+// optional custom_data columns. The custom data of a row is written as a JSON object, e.g.
+// {"runbook": {"url": "https://example.com"}}.
+func parseStreamsCSV(r io.Reader) ([]streamDefinition, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "query"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %v column", required)
+		}
+	}
+
+	var streams []streamDefinition
+	for i, record := range records[1:] {
+		// the header is line 1
+		line := i + 2
+		s := streamDefinition{
+			Name:  strings.TrimSpace(record[columns["name"]]),
+			Query: strings.TrimSpace(record[columns["query"]]),
+		}
+		if c, ok := columns["custom_data"]; ok && strings.TrimSpace(record[c]) != "" {
+			if err := json.Unmarshal([]byte(record[c]), &s.CustomData); err != nil {
+				return nil, fmt.Errorf("line %d: custom_data must be a JSON object of objects of strings: %v", line, err)
+			}
+		}
+		if err := validateStreamDefinition(s); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		streams = append(streams, s)
+	}
+	return streams, nil
+}
+
+// parseStreamsJSON reads streams from a JSON array of stream definitions
+func parseStreamsJSON(r io.Reader) ([]streamDefinition, error) {
+	var streams []streamDefinition
+	if err := json.NewDecoder(r).Decode(&streams); err != nil {
+		return nil, err
+	}
+	for i, s := range streams {
+		if err := validateStreamDefinition(s); err != nil {
+			return nil, fmt.Errorf("stream %d: %v", i, err)
+		}
+	}
+	return streams, nil
+}
+
+func validateStreamDefinition(s streamDefinition) error {
+	if s.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if err := lightstep.ValidateStreamQuery(s.Query); err != nil {
+		return fmt.Errorf("invalid query of stream %q: %v", s.Name, err)
+	}
+	return nil
+}
+
+// importStreams writes the configuration of the streams in the project. The streams that
+// already exist, i.e. that have the name of one of the existing streams, are followed by the
+// import blocks that adopt them. Unless revealSecrets is set, values that look like credentials
+// are replaced with sensitive input variables so the output can be committed.
+func importStreams(wr io.Writer, project string, definitions []streamDefinition, existing []client.Stream, revealSecrets bool) error {
+	existingIDs := map[string]string{}
+	for _, s := range existing {
+		existingIDs[s.Attributes.Name] = s.ID
+	}
+
+	streams := make([]client.Stream, 0, len(definitions))
+	for _, d := range definitions {
+		streams = append(streams, client.Stream{
+			Attributes: client.StreamAttributes{
+				Name:       d.Name,
+				Query:      d.Query,
+				CustomData: d.CustomData,
+			},
+		})
+	}
+
+	names, secretVariables, err := writeStreams(wr, project, streams, resourceNames{}, revealSecrets)
+	if err != nil {
+		return err
+	}
+
+	var imports []importBlock
+	for i, s := range streams {
+		if id, ok := existingIDs[s.Attributes.Name]; ok {
+			imports = append(imports, importBlock{
+				Address: "lightstep_stream." + names[i],
+				ID:      project + "." + id,
+			})
+		}
+	}
+	return writeVariablesAndImports(wr, secretVariables, imports)
+}
@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestImportStreamsFromCSV(t *testing.T) {
+	definitions, err := loadStreamDefinitions(filepath.Join("testdata", "streams.csv"))
+	require.NoError(t, err)
+	require.Len(t, definitions, 2)
+
+	// the second stream already exists
+	existing := []client.Stream{{ID: "s2", Attributes: client.StreamAttributes{Name: "All API spans"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, importStreams(&buf, "shop", definitions, existing, false))
+	out := buf.String()
+
+	file, diags := hclparse.NewParser().ParseHCL(buf.Bytes(), "streams.tf")
+	require.False(t, diags.HasErrors(), "generated config does not parse: %v\n%s", diags, out)
+	content, _ := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "resource", LabelNames: []string{"type", "name"}},
+			{Type: "variable", LabelNames: []string{"name"}},
+			{Type: "import"},
+		},
+	})
+
+	var resources, variables []string
+	imports := 0
+	for _, b := range content.Blocks {
+		switch b.Type {
+		case "resource":
+			resources = append(resources, strings.Join(b.Labels, "."))
+		case "variable":
+			variables = append(variables, b.Labels[0])
+		case "import":
+			imports++
+		}
+	}
+	assert.Equal(t, []string{"lightstep_stream.checkout_errors", "lightstep_stream.all_api_spans"}, resources)
+	assert.Equal(t, []string{"checkout_errors_runbook_api_token"}, variables)
+	assert.Equal(t, 1, imports, "only existing streams are imported")
+
+	assert.Contains(t, out, `query        = "service IN (\"checkout\") AND \"error\" IN (\"true\")"`)
+	assert.Contains(t, out, `"url" = "https://example.com/runbook"`)
+	assert.Contains(t, out, `"api_token" = var.checkout_errors_runbook_api_token`)
+	assert.NotContains(t, out, "s3cr3t")
+	assert.Contains(t, out, "to = lightstep_stream.all_api_spans\n  id = \"shop.s2\"")
+}
+
+func TestParseStreams(t *testing.T) {
+	streams, err := parseStreamsJSON(strings.NewReader(`[
+		{"name": "Errors", "query": "\"error\" IN (\"true\")", "custom_data": {"links": {"url": "https://example.com"}}}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []streamDefinition{{
+		Name:       "Errors",
+		Query:      `"error" IN ("true")`,
+		CustomData: map[string]map[string]string{"links": {"url": "https://example.com"}},
+	}}, streams)
+
+	for _, tc := range []struct {
+		csv         string
+		expectedErr string
+	}{
+		{csv: "name,query\nErrors,\"service IN (\"\"api\"\"\"\n", expectedErr: `line 2: invalid query of stream "Errors"`},
+		{csv: "name,query\n,\"service IN (\"\"api\"\")\"\n", expectedErr: "line 2: name must not be empty"},
+		{csv: "name,custom_data\nErrors,\n", expectedErr: "missing query column"},
+		{csv: "name,query,custom_data\nErrors,\"service IN (\"\"api\"\")\",runbook\n", expectedErr: "line 2: custom_data must be a JSON object"},
+	} {
+		_, err := parseStreamsCSV(strings.NewReader(tc.csv))
+		require.Error(t, err, tc.csv)
+		assert.Contains(t, err.Error(), tc.expectedErr)
+	}
+
+	_, err = parseStreamsJSON(strings.NewReader(`[{"name": "Errors", "query": ""}]`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `stream 0: invalid query of stream "Errors"`)
+}
@@ -0,0 +1,41 @@
+
+resource "lightstep_dashboard" "exported_dashboard" {
+  project_name = var.project
+  dashboard_name = "Checkout"
+  dashboard_description = "Checkout service overview"
+
+  template_variable {
+    name                     = "service"
+    suggestion_attribute_key = "service.name"
+    default_values           = ["checkout"]
+  }
+
+  chart {
+    name = "Requests"
+    rank = "0"
+    type = "timeseries"
+
+    query {
+      query_name          = "a"
+      display             = "line"
+      hidden              = false
+      query_string        = "metric requests | filter service == $service | rate | group_by [], sum"
+    }
+
+  }
+
+  chart {
+    name = "Latency"
+    rank = "1"
+    type = "timeseries"
+
+    query {
+      query_name          = "a"
+      display             = "line"
+      hidden              = false
+      query_string        = "spans latency | filter service == $service | delta | group_by [], sum | point percentile(value, 99.0)"
+    }
+
+  }
+
+}
@@ -0,0 +1,124 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// tfJSONConfig is the document written when exporting a dashboard to JSON, in the JSON
+// configuration syntax of Terraform (https://developer.hashicorp.com/terraform/language/syntax/json):
+//
+//	{"resource": {"lightstep_dashboard": {"<name>": {<attributes and blocks>}}}}
+//
+// Nested blocks, e.g. chart or group, are written as arrays of objects, so the document can
+// be saved as a .tf.json file or read by tools expecting the same structure.
+type tfJSONConfig struct {
+	Resource map[string]map[string]tfJSONBody `json:"resource"`
+}
+
+// tfJSONBody holds the attributes and nested blocks of a block, by name
+type tfJSONBody map[string]interface{}
+
+// exportToJSON renders the dashboard the same way as exportToHCL and converts the resource
+// to the JSON configuration syntax, so both formats describe the same configuration
+func exportToJSON(wr io.Writer, d *client.UnifiedDashboard) error {
+	var buf bytes.Buffer
+	if err := exportToHCL(&buf, d); err != nil {
+		return err
+	}
+
+	f, diags := hclsyntax.ParseConfig(buf.Bytes(), "exported.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("could not parse the generated HCL: %v", diags)
+	}
+
+	config := tfJSONConfig{Resource: map[string]map[string]tfJSONBody{}}
+	for _, block := range f.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		body, err := hclBodyToJSON(buf.Bytes(), block.Body)
+		if err != nil {
+			return err
+		}
+		resourceType, name := block.Labels[0], block.Labels[1]
+		if config.Resource[resourceType] == nil {
+			config.Resource[resourceType] = map[string]tfJSONBody{}
+		}
+		config.Resource[resourceType][name] = body
+	}
+
+	enc := json.NewEncoder(wr)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(config); err != nil {
+		return fmt.Errorf("could not write JSON: %v", err)
+	}
+	return nil
+}
+
+// hclBodyToJSON converts the attributes and nested blocks of body, parsed from src. Attributes
+// referencing variables, e.g. project_name = var.project, are written as the "${var.project}"
+// templates of the JSON syntax, the others are evaluated.
+func hclBodyToJSON(src []byte, body *hclsyntax.Body) (tfJSONBody, error) {
+	out := tfJSONBody{}
+	for name, attribute := range body.Attributes {
+		if len(attribute.Expr.Variables()) > 0 {
+			out[name] = "${" + string(attribute.Expr.Range().SliceBytes(src)) + "}"
+			continue
+		}
+
+		v, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("could not evaluate %v: %v", name, diags)
+		}
+		raw, err := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("could not convert %v: %v", name, err)
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("could not convert %v: %v", name, err)
+		}
+		out[name] = escapeJSONTemplates(value)
+	}
+
+	for _, block := range body.Blocks {
+		nested, err := hclBodyToJSON(src, block.Body)
+		if err != nil {
+			return nil, err
+		}
+		blocks, _ := out[block.Type].([]tfJSONBody)
+		out[block.Type] = append(blocks, nested)
+	}
+	return out, nil
+}
+
+// escapeJSONTemplates escapes the template sequences of the strings of v, since Terraform
+// interprets the strings of JSON configurations as templates too
+func escapeJSONTemplates(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return escapeHCLTemplate(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = escapeJSONTemplates(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = escapeJSONTemplates(v[k])
+		}
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+	"github.com/lightstep/terraform-provider-lightstep/lightstep"
+)
+
+func TestExportToJSON(t *testing.T) {
+	d := &client.UnifiedDashboard{
+		ID: "abc123",
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Test dashboard",
+			TemplateVariables: []client.TemplateVariable{
+				{Name: "service", DefaultValues: []string{"api"}, SuggestionAttributeKey: "service.name"},
+			},
+			Charts: []client.UnifiedChart{
+				{
+					Title:     "Requests",
+					ChartType: "timeseries",
+					Rank:      1,
+					MetricQueries: []client.MetricQueryWithAttributes{
+						{
+							Name:     "a",
+							Type:     "tql",
+							Display:  "line",
+							TQLQuery: `metric requests | filter service == "${service}" | rate`,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exportToJSON(&buf, d))
+
+	var config struct {
+		Resource map[string]map[string]map[string]interface{} `json:"resource"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &config), buf.String())
+	require.Len(t, config.Resource["lightstep_dashboard"], 1)
+	dashboard := config.Resource["lightstep_dashboard"]["exported_dashboard"]
+	require.NotNil(t, dashboard, buf.String())
+
+	assert.Equal(t, "${var.project}", dashboard["project_name"])
+	assert.Equal(t, "Test dashboard", dashboard["dashboard_name"])
+	chart := dashboard["chart"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Requests", chart["name"])
+	query := chart["query"].([]interface{})[0].(map[string]interface{})
+	// the query is a heredoc in HCL, which ends with a newline
+	assert.Equal(t, "metric requests | filter service == \"$${service}\" | rate\n", query["query_string"],
+		"template sequences are escaped, since Terraform interprets JSON strings as templates")
+
+	// every attribute and block matches the schema of the resource
+	r := lightstep.Provider().ResourcesMap["lightstep_dashboard"]
+	assertMatchesSchema(t, "lightstep_dashboard", dashboard, r.Schema)
+}
+
+// assertMatchesSchema checks that the keys of body are in the schema, with nested blocks as
+// arrays of objects
+func assertMatchesSchema(t *testing.T, path string, body map[string]interface{}, s map[string]*schema.Schema) {
+	for name, value := range body {
+		attribute, ok := s[name]
+		if !assert.True(t, ok, "%v.%v is not in the schema", path, name) {
+			continue
+		}
+		nested, isBlock := attribute.Elem.(*schema.Resource)
+		if !isBlock {
+			continue
+		}
+		blocks, ok := value.([]interface{})
+		if !assert.True(t, ok, "%v.%v should be an array of blocks", path, name) {
+			continue
+		}
+		for _, b := range blocks {
+			block, ok := b.(map[string]interface{})
+			if assert.True(t, ok, "%v.%v should be an array of objects", path, name) {
+				assertMatchesSchema(t, path+"."+name, block, nested.Schema)
+			}
+		}
+	}
+}
+
+func TestParseArgsJSON(t *testing.T) {
+	flags, _, err := parseArgs([]string{"--format", "json", "dashboard", "p", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, "json", flags.format)
+
+	_, _, err = parseArgs([]string{"--format", "json", "--module-dir", "out", "dashboard", "p", "d"})
+	assert.EqualError(t, err, "--module-dir can only be used with the hcl format")
+}
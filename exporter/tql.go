@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+	"github.com/lightstep/terraform-provider-lightstep/lightstep"
+)
+
+// convertQueriesToTQL replaces the structured queries of the dashboard's charts with their
+// TQL equivalent, as translated by the API, so that the dashboard is exported with TQL queries
+// only
+func convertQueriesToTQL(ctx context.Context, c *client.Client, project string, d *client.UnifiedDashboard) error {
+	convert := func(charts []client.UnifiedChart) error {
+		for i := range charts {
+			if err := convertChartQueriesToTQL(ctx, c, project, &charts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := convert(d.Attributes.Charts); err != nil {
+		return err
+	}
+	for _, g := range d.Attributes.Groups {
+		if err := convert(g.Charts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertChartQueriesToTQL(ctx context.Context, c *client.Client, project string, chart *client.UnifiedChart) error {
+	var structured []client.MetricQueryWithAttributes
+	for _, q := range chart.MetricQueries {
+		if q.TQLQuery == "" {
+			structured = append(structured, q)
+		}
+	}
+	if len(structured) == 0 {
+		return nil
+	}
+
+	translated, err := c.TranslateQueries(ctx, project, structured)
+	if err != nil {
+		return fmt.Errorf("could not translate the queries of chart %q to TQL: %v", chart.Title, err)
+	}
+	// formula query names come back with different whitespace and parentheses
+	tql := map[string]string{}
+	for _, t := range translated {
+		tql[lightstep.SimplifyQueryName(t.Name)] = t.TQL
+	}
+
+	for i, q := range chart.MetricQueries {
+		if q.TQLQuery != "" {
+			continue
+		}
+		translation, ok := tql[lightstep.SimplifyQueryName(q.Name)]
+		if !ok || translation == "" {
+			return fmt.Errorf("no TQL translation for query %q of chart %q", q.Name, chart.Title)
+		}
+		chart.MetricQueries[i] = client.MetricQueryWithAttributes{
+			Name:               q.Name,
+			Type:               "tql",
+			Hidden:             q.Hidden,
+			Display:            q.Display,
+			DisplayTypeOptions: q.DisplayTypeOptions,
+			TQLQuery:           translation,
+			HiddenQueries:      q.HiddenQueries,
+			TimeShift:          q.TimeShift,
+			DisplayLabel:       q.DisplayLabel,
+			Baseline:           q.Baseline,
+		}
+	}
+	return nil
+}
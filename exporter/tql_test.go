@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestConvertQueriesToTQL(t *testing.T) {
+	c := fixtureClient(t, map[string]string{
+		"/public/v0.2/my-org/projects/shop/query_translation": `{"data": {"queries": [
+			{"query-name": "a", "tql-query": "metric requests | rate | group_by [\"service\"], sum"}
+		]}}`,
+	})
+
+	d := &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Checkout",
+			Charts: []client.UnifiedChart{{
+				Title:     "Requests",
+				ChartType: "timeseries",
+				MetricQueries: []client.MetricQueryWithAttributes{
+					{
+						Name:    "a",
+						Type:    "single",
+						Display: "line",
+						Query: client.MetricQuery{
+							Metric:             "requests",
+							TimeseriesOperator: "rate",
+							GroupBy:            client.GroupBy{LabelKeys: []string{"service"}, Aggregation: "sum"},
+						},
+					},
+					{Name: "b", Type: "tql", Display: "line", TQLQuery: "metric errors | rate | group_by [], sum"},
+				},
+			}},
+		},
+	}
+	require.NoError(t, convertQueriesToTQL(context.Background(), c, "shop", d))
+
+	queries := d.Attributes.Charts[0].MetricQueries
+	assert.Equal(t, "tql", queries[0].Type)
+	assert.Equal(t, `metric requests | rate | group_by ["service"], sum`, queries[0].TQLQuery)
+	assert.Empty(t, queries[0].Query.Metric, "the structured query is dropped")
+	assert.Equal(t, "metric errors | rate | group_by [], sum", queries[1].TQLQuery, "TQL queries are kept")
+
+	var buf bytes.Buffer
+	require.NoError(t, exportToHCL(&buf, d))
+	out := buf.String()
+	assert.Contains(t, out, `resource "lightstep_dashboard"`)
+	assert.Contains(t, out, `query_string        = <<EOT
+metric requests | rate | group_by ["service"], sum
+EOT`)
+	assert.NotContains(t, out, "timeseries_operator")
+}
+
+func TestConvertFormulaQueriesToTQL(t *testing.T) {
+	c := fixtureClient(t, map[string]string{
+		"/public/v0.2/my-org/projects/shop/query_translation": `{"data": {"queries": [
+			{"query-name": "a", "tql-query": "metric errors | rate | group_by [], sum"},
+			{"query-name": "b", "tql-query": "metric requests | rate | group_by [], sum"},
+			{"query-name": "(a/b)*100", "tql-query": "with a = metric errors | rate | group_by [], sum; b = metric requests | rate | group_by [], sum; join (a / b) * 100"}
+		]}}`,
+	})
+
+	d := &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Charts: []client.UnifiedChart{{
+				Title: "Error ratio",
+				MetricQueries: []client.MetricQueryWithAttributes{
+					{Name: "a", Type: "single", Hidden: true, Query: client.MetricQuery{Metric: "errors"}},
+					{Name: "b", Type: "single", Hidden: true, Query: client.MetricQuery{Metric: "requests"}},
+					{Name: "( a / b ) * 100", Type: "composite", Display: "line"},
+				},
+			}},
+		},
+	}
+	require.NoError(t, convertQueriesToTQL(context.Background(), c, "shop", d))
+
+	formula := d.Attributes.Charts[0].MetricQueries[2]
+	assert.Equal(t, "( a / b ) * 100", formula.Name, "the configured name is kept")
+	assert.Equal(t, "tql", formula.Type)
+	assert.Contains(t, formula.TQLQuery, "join (a / b) * 100")
+}
+
+func TestConvertQueriesToTQLMissingTranslation(t *testing.T) {
+	c := fixtureClient(t, map[string]string{
+		"/public/v0.2/my-org/projects/shop/query_translation": `{"data": {"queries": []}}`,
+	})
+
+	d := &client.UnifiedDashboard{
+		Attributes: client.UnifiedDashboardAttributes{
+			Groups: []client.UnifiedGroup{{
+				Charts: []client.UnifiedChart{{
+					Title:         "Requests",
+					MetricQueries: []client.MetricQueryWithAttributes{{Name: "a", Query: client.MetricQuery{Metric: "requests"}}},
+				}},
+			}},
+		},
+	}
+	err := convertQueriesToTQL(context.Background(), c, "shop", d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no TQL translation for query "a" of chart "Requests"`)
+}
@@ -0,0 +1,75 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+const (
+	yamlAPIVersion    = "lightstep.com/v1"
+	yamlDashboardKind = "Dashboard"
+)
+
+// yamlDashboard is the document written when exporting a dashboard to YAML:
+//
+//	apiVersion: lightstep.com/v1
+//	kind: Dashboard
+//	project: <project name>
+//	id: <dashboard id>
+//	spec: <dashboard attributes>
+//
+// spec uses the same field names as the dashboard attributes of the Lightstep Public API
+// (https://api-docs.lightstep.com/reference/dashboards), so it is produced from the client
+// types and their JSON tags.
+type yamlDashboard struct {
+	APIVersion string                            `json:"apiVersion"`
+	Kind       string                            `json:"kind"`
+	Project    string                            `json:"project"`
+	ID         string                            `json:"id"`
+	Spec       client.UnifiedDashboardAttributes `json:"spec"`
+}
+
+func exportToYAML(wr io.Writer, project string, d *client.UnifiedDashboard) error {
+	doc, err := json.Marshal(yamlDashboard{
+		APIVersion: yamlAPIVersion,
+		Kind:       yamlDashboardKind,
+		Project:    project,
+		ID:         d.ID,
+		Spec:       d.Attributes,
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal dashboard: %v", err)
+	}
+
+	// JSON is valid YAML, so decoding it into a node keeps the field order of the structs
+	var node yaml.Node
+	if err := yaml.Unmarshal(doc, &node); err != nil {
+		return fmt.Errorf("could not convert dashboard to YAML: %v", err)
+	}
+	setYAMLBlockStyle(&node)
+
+	enc := yaml.NewEncoder(wr)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("could not write YAML: %v", err)
+	}
+	return enc.Close()
+}
+
+// setYAMLBlockStyle replaces the JSON flow style with the block style usually found in YAML
+// files and writes multi-line strings (e.g. queries) as literal blocks.
+func setYAMLBlockStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && strings.Contains(node.Value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	for _, n := range node.Content {
+		setYAMLBlockStyle(n)
+	}
+}
@@ -0,0 +1,98 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestExportToYAML(t *testing.T) {
+	subtitle := "p99"
+	d := &client.UnifiedDashboard{
+		ID: "abc123",
+		Attributes: client.UnifiedDashboardAttributes{
+			Name:        "Test dashboard",
+			Description: "Requests: overview",
+			Labels:      []client.Label{{Key: "team", Value: "api"}},
+			TemplateVariables: []client.TemplateVariable{
+				{
+					Name:                   "service",
+					DefaultValues:          []string{"api", "true"},
+					SuggestionAttributeKey: "service.name",
+				},
+			},
+			Groups: []client.UnifiedGroup{
+				{
+					Rank:           0,
+					Title:          "Overview",
+					VisibilityType: "explicit",
+					Charts: []client.UnifiedChart{
+						{
+							Title:     "Requests",
+							ChartType: "timeseries",
+							Rank:      1,
+							Position:  client.UnifiedPosition{XPos: 0, YPos: 0, Width: 16, Height: 8},
+							Subtitle:  &subtitle,
+							MetricQueries: []client.MetricQueryWithAttributes{
+								{
+									Name:     "a",
+									Type:     "tql",
+									Display:  "line",
+									TQLQuery: "with\n  a = metric requests | rate;\njoin a, a = 0",
+									DependencyMapOptions: &client.DependencyMapOptions{
+										Scope:   "all",
+										MapType: "service",
+									},
+									TimeShift: "168h",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exportToYAML(&buf, "my-project", d))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "apiVersion: lightstep.com/v1\nkind: Dashboard\nproject: my-project\nid: abc123\nspec:\n"), out)
+	assert.Contains(t, out, "tql-query: |-\n", "multi-line queries are written as literal blocks")
+
+	// re-parse the YAML and make sure nothing was lost on the way
+	var generic interface{}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &generic))
+	asJSON, err := json.Marshal(generic)
+	require.NoError(t, err)
+
+	var parsed yamlDashboard
+	require.NoError(t, json.Unmarshal(asJSON, &parsed))
+	assert.Equal(t, yamlDashboard{
+		APIVersion: "lightstep.com/v1",
+		Kind:       "Dashboard",
+		Project:    "my-project",
+		ID:         "abc123",
+		Spec:       d.Attributes,
+	}, parsed)
+}
+
+func TestParseArgsFormat(t *testing.T) {
+	flags, positional, err := parseArgs([]string{"dashboard", "--format", "yaml", "my-project", "abc123"})
+	require.NoError(t, err)
+	assert.Equal(t, "yaml", flags.format)
+	assert.Equal(t, []string{"dashboard", "my-project", "abc123"}, positional)
+
+	_, _, err = parseArgs([]string{"--format", "toml", "dashboard", "my-project", "abc123"})
+	assert.Error(t, err)
+
+	_, _, err = parseArgs([]string{"--format", "yaml", "--module-dir", "out", "dashboard", "my-project", "abc123"})
+	assert.Error(t, err)
+}
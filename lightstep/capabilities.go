@@ -0,0 +1,137 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// builtinCapabilities are used for validation when the API doesn't report its capabilities.
+// An empty list doesn't restrict the attribute.
+var builtinCapabilities = client.Capabilities{
+	ChartTypes: []string{"timeseries"},
+	Operators:  []string{"above", "below"},
+}
+
+// attributeGetter is implemented by schema.ResourceData and schema.ResourceDiff
+type attributeGetter interface {
+	Get(key string) interface{}
+}
+
+// getCapabilities returns the capabilities reported by the API, or the built-in ones if they
+// can't be fetched.
+func getCapabilities(ctx context.Context, m interface{}) *client.Capabilities {
+	meta, ok := m.(*providerMeta)
+	if !ok || meta == nil {
+		return &builtinCapabilities
+	}
+
+	capabilities, err := meta.client.GetCapabilities(ctx)
+	if err != nil || capabilities == nil {
+		log.Printf("[DEBUG] using built-in capabilities, could not get them from the API: %v", err)
+		return &builtinCapabilities
+	}
+	return capabilities
+}
+
+// checkCapability returns an error if value isn't one of allowed (ignoring case)
+func checkCapability(attribute string, value string, allowed []string) error {
+	if value == "" || len(allowed) == 0 {
+		return nil
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %s to be one of %q, got %s", attribute, allowed, value)
+}
+
+// checkChartCapabilities checks the type and display unit of every chart in the set
+func checkChartCapabilities(charts *schema.Set, capabilities *client.Capabilities) error {
+	for _, c := range charts.List() {
+		chart := c.(map[string]interface{})
+		name, _ := chart["name"].(string)
+
+		chartType, _ := chart["type"].(string)
+		if err := checkCapability("type", chartType, capabilities.ChartTypes); err != nil {
+			return fmt.Errorf("chart %q: %v", name, err)
+		}
+		displayUnit, _ := chart["display_unit"].(string)
+		if err := checkCapability("display_unit", displayUnit, capabilities.Units); err != nil {
+			return fmt.Errorf("chart %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// validateDashboardCapabilities checks the charts of a dashboard, including those in groups,
+// against the capabilities of the API. It's called when applying rather than planning, so that
+// plans don't need the API.
+func validateDashboardCapabilities(ctx context.Context, d attributeGetter, m interface{}) error {
+	capabilities := getCapabilities(ctx, m)
+
+	if charts, ok := d.Get("chart").(*schema.Set); ok {
+		if err := checkChartCapabilities(charts, capabilities); err != nil {
+			return err
+		}
+	}
+
+	groups, _ := d.Get("group").(*schema.Set)
+	if groups == nil {
+		return nil
+	}
+	for _, g := range groups.List() {
+		charts, ok := g.(map[string]interface{})["chart"].(*schema.Set)
+		if !ok {
+			continue
+		}
+		if err := checkChartCapabilities(charts, capabilities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExpressionCapabilities checks the operand of an alert expression block
+func checkExpressionCapabilities(expressions []interface{}, capabilities *client.Capabilities) error {
+	if len(expressions) == 0 || expressions[0] == nil {
+		return nil
+	}
+	operand, _ := expressions[0].(map[string]interface{})["operand"].(string)
+	return checkCapability("operand", operand, capabilities.Operators)
+}
+
+// validateAlertCapabilities checks the expressions of an alert, including those of composite
+// sub alerts, against the capabilities of the API. Like validateDashboardCapabilities, it's
+// called when applying.
+func validateAlertCapabilities(ctx context.Context, d attributeGetter, m interface{}) error {
+	capabilities := getCapabilities(ctx, m)
+
+	expressions, _ := d.Get("expression").([]interface{})
+	if err := checkExpressionCapabilities(expressions, capabilities); err != nil {
+		return err
+	}
+
+	compositeAlerts, _ := d.Get("composite_alert").([]interface{})
+	if len(compositeAlerts) == 0 || compositeAlerts[0] == nil {
+		return nil
+	}
+	alerts, ok := compositeAlerts[0].(map[string]interface{})["alert"].(*schema.Set)
+	if !ok {
+		return nil
+	}
+	for _, a := range alerts.List() {
+		alert := a.(map[string]interface{})
+		expressions, _ := alert["expression"].([]interface{})
+		if err := checkExpressionCapabilities(expressions, capabilities); err != nil {
+			return fmt.Errorf("composite alert %q: %v", alert["name"], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package lightstep
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+const capabilitiesFixture = `{"data": {"attributes": {
+	"chart-types": ["timeseries", "heatmap"],
+	"operators": ["above", "below", "outside"],
+	"units": ["B", "KB", "MB"]
+}}}`
+
+func capabilitiesMeta(t *testing.T, status int, body string) *providerMeta {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	return &providerMeta{client: client.NewClient("api", "blars", "staging")}
+}
+
+func TestGetCapabilities(t *testing.T) {
+	capabilities := getCapabilities(context.Background(), capabilitiesMeta(t, http.StatusOK, capabilitiesFixture))
+	assert.Equal(t, []string{"timeseries", "heatmap"}, capabilities.ChartTypes)
+
+	capabilities = getCapabilities(context.Background(), capabilitiesMeta(t, http.StatusNotFound, `{}`))
+	assert.Equal(t, &builtinCapabilities, capabilities, "falls back to the built-in capabilities")
+}
+
+func TestCheckChartCapabilities(t *testing.T) {
+	fromAPI := getCapabilities(context.Background(), capabilitiesMeta(t, http.StatusOK, capabilitiesFixture))
+
+	chartSet := func(chartType string, displayUnit string) *schema.Set {
+		d := schema.TestResourceDataRaw(t, resourceUnifiedDashboard(UnifiedChartSchema).Schema, map[string]interface{}{
+			"project_name":   "p",
+			"dashboard_name": "d",
+			"chart": []interface{}{
+				map[string]interface{}{
+					"name":         "Memory",
+					"rank":         0,
+					"type":         chartType,
+					"display_unit": displayUnit,
+				},
+			},
+		})
+		return d.Get("chart").(*schema.Set)
+	}
+
+	testCases := []struct {
+		name         string
+		chartType    string
+		displayUnit  string
+		capabilities *client.Capabilities
+		expectError  string
+	}{
+		{
+			name:         "supported by the API",
+			chartType:    "heatmap",
+			displayUnit:  "MB",
+			capabilities: fromAPI,
+		},
+		{
+			name:         "chart type is case insensitive",
+			chartType:    "TimeSeries",
+			capabilities: fromAPI,
+		},
+		{
+			name:         "unsupported chart type",
+			chartType:    "pie",
+			capabilities: fromAPI,
+			expectError:  `chart "Memory": expected type to be one of ["timeseries" "heatmap"], got pie`,
+		},
+		{
+			name:         "unsupported unit",
+			chartType:    "timeseries",
+			displayUnit:  "furlongs",
+			capabilities: fromAPI,
+			expectError:  `expected display_unit to be one of ["B" "KB" "MB"], got furlongs`,
+		},
+		{
+			name:         "built-in chart types",
+			chartType:    "heatmap",
+			capabilities: &builtinCapabilities,
+			expectError:  `expected type to be one of ["timeseries"], got heatmap`,
+		},
+		{
+			name:         "built-in capabilities don't restrict units",
+			chartType:    "timeseries",
+			displayUnit:  "furlongs",
+			capabilities: &builtinCapabilities,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkChartCapabilities(chartSet(tc.chartType, tc.displayUnit), tc.capabilities)
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckExpressionCapabilities(t *testing.T) {
+	fromAPI := getCapabilities(context.Background(), capabilitiesMeta(t, http.StatusOK, capabilitiesFixture))
+	expression := func(operand string) []interface{} {
+		return []interface{}{map[string]interface{}{"operand": operand}}
+	}
+
+	assert.NoError(t, checkExpressionCapabilities(expression("outside"), fromAPI))
+	assert.NoError(t, checkExpressionCapabilities(expression(""), fromAPI), "operand is optional")
+	assert.Error(t, checkExpressionCapabilities(expression("outside"), &builtinCapabilities))
+	assert.NoError(t, checkExpressionCapabilities(nil, &builtinCapabilities))
+}
+
+func TestValidateDashboardCapabilities(t *testing.T) {
+	meta := capabilitiesMeta(t, http.StatusOK, capabilitiesFixture)
+	config := dashboardConfig(1, 1)
+	d := schema.TestResourceDataRaw(t, resourceUnifiedDashboard(UnifiedChartSchema).Schema, config)
+	assert.NoError(t, validateDashboardCapabilities(context.Background(), d, meta))
+
+	config["group"].([]interface{})[0].(map[string]interface{})["chart"].([]interface{})[0].(map[string]interface{})["display_unit"] = "furlongs"
+	d = schema.TestResourceDataRaw(t, resourceUnifiedDashboard(UnifiedChartSchema).Schema, config)
+	err := validateDashboardCapabilities(context.Background(), d, meta)
+	require.Error(t, err, "grouped charts are checked too")
+	assert.Contains(t, err.Error(), "got furlongs")
+}
+
+func TestValidateDashboardCapabilitiesFromAPI(t *testing.T) {
+	config := dashboardConfig(1, 0)
+	config["chart"].([]interface{})[0].(map[string]interface{})["type"] = "heatmap"
+
+	// the schema doesn't restrict the chart types, the API reports which it supports
+	r := resourceUnifiedDashboard(UnifiedChartSchema)
+	assert.False(t, r.Validate(terraform.NewResourceConfigRaw(config)).HasError())
+
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	assert.NoError(t, validateDashboardCapabilities(context.Background(), d, capabilitiesMeta(t, http.StatusOK, capabilitiesFixture)))
+
+	err := validateDashboardCapabilities(context.Background(), d, capabilitiesMeta(t, http.StatusNotFound, `{}`))
+	require.Error(t, err, "the built-in chart types are used when the API doesn't report them")
+	assert.Contains(t, err.Error(), "got heatmap")
+}
@@ -1,45 +1,35 @@
 package lightstep
 
-var (
-	validUpdateInterval = map[string]int{
-		"2m":  120000,
-		"5m":  300000,
-		"10m": 600000,
-		"15m": 900000,
-		"20m": 1200000,
-		"30m": 1800000,
-		"40m": 2400000,
-		"50m": 3000000,
-		"1h":  3600000,
-		"2h":  7200000,
-		"3h":  10800000,
-		"4h":  14400000,
-		"5h":  18000000,
-		"6h":  21600000,
-		"12h": 43200000,
-		"1d":  86400000,
-		"7d":  604800000,
-		"14d": 1209600000,
-	}
-)
+// validUpdateIntervals are the intervals at which a notification can be re-sent, the API
+// takes them in milliseconds
+var validUpdateIntervals = []string{
+	"2m", "5m", "10m", "15m", "20m", "30m", "40m", "50m",
+	"1h", "2h", "3h", "4h", "5h", "6h", "12h",
+	"1d", "7d", "14d",
+}
 
 func GetValidUpdateInterval() []string {
-	var res []string
-	for k := range validUpdateInterval {
-		res = append(res, k)
-	}
-	return res
+	return validUpdateIntervals
 }
 
-func GetUpdateIntervalValue(in int) interface{} {
-	for k, v := range validUpdateInterval {
-		if v == in {
-			return k
+// updateIntervalMillis returns the milliseconds of a valid update interval
+func updateIntervalMillis(interval string) (int, bool) {
+	for _, valid := range validUpdateIntervals {
+		if interval == valid {
+			ms, err := durationMillis(interval)
+			return ms, err == nil
 		}
 	}
+	return 0, false
+}
+
+func GetUpdateIntervalValue(in int) interface{} {
 	if in == 0 {
 		return ""
 	}
+	if _, ok := updateIntervalMillis(formatMillis(in)); ok {
+		return formatMillis(in)
+	}
 	// This isn't a valid value according to the validation fun in the schema. The only
 	// reason we return this here is so terraform can tell there's a difference between
 	// no update interval and an update interval not supported by terraform.
@@ -0,0 +1,92 @@
+package lightstep
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// credentialsProfile is the section of the credentials file that is read
+const credentialsProfile = "default"
+
+// credentials are the API key and organization read from a credentials file
+type credentials struct {
+	apiKey       string
+	organization string
+}
+
+// defaultCredentialsFile returns ~/.lightstep/credentials, or "" if there's no home directory
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".lightstep", "credentials")
+}
+
+// readCredentialsFile reads the api_key and organization of the [default] section of an INI
+// credentials file, in the style of the AWS CLI:
+//
+//	[default]
+//	api_key      = ...
+//	organization = my-org
+//
+// Keys before the first section belong to the default section too. Lines starting with # or ;
+// are comments.
+func readCredentialsFile(path string) (credentials, error) {
+	var creds credentials
+
+	f, err := os.Open(path)
+	if err != nil {
+		return creds, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	section := credentialsProfile
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return creds, fmt.Errorf("%v:%d: expected key = value, got %q", path, lineNumber, line)
+		}
+		if section != credentialsProfile {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "api_key":
+			creds.apiKey = strings.TrimSpace(value)
+		case "organization":
+			creds.organization = strings.TrimSpace(value)
+		}
+	}
+	return creds, scanner.Err()
+}
+
+// loadCredentials reads the credentials file at path, or the default one if path is empty. A
+// missing default file isn't an error since the credentials may be configured otherwise.
+func loadCredentials(path string) (credentials, error) {
+	if path != "" {
+		return readCredentialsFile(path)
+	}
+
+	path = defaultCredentialsFile()
+	if path == "" {
+		return credentials{}, nil
+	}
+	creds, err := readCredentialsFile(path)
+	if os.IsNotExist(err) {
+		return credentials{}, nil
+	}
+	return creds, err
+}
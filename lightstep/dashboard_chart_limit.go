@@ -0,0 +1,99 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dashboardChartLimit returns the opt-in number of charts above which dashboards are reported
+// and whether exceeding it fails the plan rather than logging a warning. The provider's
+// dashboard_chart_limit and dashboard_chart_limit_action attributes take precedence over the
+// LIGHTSTEP_DASHBOARD_CHART_LIMIT and LIGHTSTEP_DASHBOARD_CHART_LIMIT_ACTION env vars. A zero
+// limit disables the check.
+func dashboardChartLimit(d *schema.ResourceData) (limit int, failPlan bool) {
+	action := os.Getenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT_ACTION")
+	if v, ok := d.GetOk("dashboard_chart_limit_action"); ok {
+		action = v.(string)
+	}
+
+	//nolint:staticcheck // GetOk can't tell an explicit 0, which disables the check, from an unset limit
+	if v, ok := d.GetOkExists("dashboard_chart_limit"); ok {
+		return v.(int), action == "error"
+	}
+	limit, err := strconv.Atoi(os.Getenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT"))
+	if err != nil || limit < 0 {
+		return 0, false
+	}
+	return limit, action == "error"
+}
+
+// countDashboardCharts returns the number of charts of a dashboard, including those in groups
+func countDashboardCharts(charts *schema.Set, groups *schema.Set) int {
+	var count int
+	if charts != nil {
+		count += charts.Len()
+	}
+	if groups == nil {
+		return count
+	}
+	for _, g := range groups.List() {
+		if groupCharts, ok := g.(map[string]interface{})["chart"].(*schema.Set); ok {
+			count += groupCharts.Len()
+		}
+	}
+	return count
+}
+
+// dashboardChartLimitMessage describes a dashboard with more charts than the limit, or returns
+// "" if it's within the limit.
+func dashboardChartLimitMessage(name string, count int, limit int) string {
+	if limit <= 0 || count <= limit {
+		return ""
+	}
+	return fmt.Sprintf("dashboard %q has %d charts which exceeds the limit of %d, large dashboards may be "+
+		"rejected by the API or slow to render, consider splitting it", name, count, limit)
+}
+
+// checkDashboardChartLimit is a CustomizeDiff function that fails the plan of dashboards with
+// more charts than the limit when the provider is configured to. Otherwise they are warned about
+// by dashboardChartLimitWarning, since CustomizeDiff can't return warnings.
+func checkDashboardChartLimit(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta, ok := m.(*providerMeta)
+	if !ok || meta == nil || !meta.failDashboardChartLimit {
+		return nil
+	}
+
+	charts, _ := d.Get("chart").(*schema.Set)
+	groups, _ := d.Get("group").(*schema.Set)
+	message := dashboardChartLimitMessage(d.Get("dashboard_name").(string), countDashboardCharts(charts, groups), meta.dashboardChartLimit)
+	if message == "" {
+		return nil
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// dashboardChartLimitWarning returns a warning for a created or updated dashboard with more
+// charts than the limit.
+func dashboardChartLimitWarning(d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta, ok := m.(*providerMeta)
+	if !ok || meta == nil {
+		return nil
+	}
+
+	charts, _ := d.Get("chart").(*schema.Set)
+	groups, _ := d.Get("group").(*schema.Set)
+	message := dashboardChartLimitMessage(d.Get("dashboard_name").(string), countDashboardCharts(charts, groups), meta.dashboardChartLimit)
+	if message == "" {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Dashboard exceeds the chart limit",
+		Detail:   message,
+	}}
+}
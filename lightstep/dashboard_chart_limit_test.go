@@ -0,0 +1,137 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dashboardConfig returns the configuration of a dashboard with topLevel charts and a group
+// with grouped charts
+func dashboardConfig(topLevel int, grouped int) map[string]interface{} {
+	chart := func(i int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": fmt.Sprintf("chart %d", i),
+			"rank": i,
+			"type": "timeseries",
+			"query": []interface{}{
+				map[string]interface{}{
+					"query_name":   "a",
+					"display":      "line",
+					"hidden":       false,
+					"query_string": "metric requests | rate",
+				},
+			},
+		}
+	}
+
+	var charts, groupCharts []interface{}
+	for i := 0; i < topLevel; i++ {
+		charts = append(charts, chart(i))
+	}
+	for i := 0; i < grouped; i++ {
+		groupCharts = append(groupCharts, chart(topLevel+i))
+	}
+	return map[string]interface{}{
+		"project_name":   "p",
+		"dashboard_name": "Big dashboard",
+		"chart":          charts,
+		"group": []interface{}{
+			map[string]interface{}{
+				"rank":            0,
+				"title":           "Group",
+				"visibility_type": "explicit",
+				"chart":           groupCharts,
+			},
+		},
+	}
+}
+
+func TestCountDashboardCharts(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUnifiedDashboard(UnifiedChartSchema).Schema, dashboardConfig(2, 3))
+	charts, _ := d.Get("chart").(*schema.Set)
+	groups, _ := d.Get("group").(*schema.Set)
+	assert.Equal(t, 5, countDashboardCharts(charts, groups))
+	assert.Equal(t, 0, countDashboardCharts(nil, nil))
+}
+
+func TestDashboardChartLimitMessage(t *testing.T) {
+	assert.Empty(t, dashboardChartLimitMessage("d", 30, 0), "a zero limit disables the check")
+	assert.Empty(t, dashboardChartLimitMessage("d", 29, 30))
+	assert.Empty(t, dashboardChartLimitMessage("d", 30, 30))
+	assert.Equal(t, `dashboard "d" has 31 charts which exceeds the limit of 30, large dashboards may be rejected by the API or slow to render, consider splitting it`,
+		dashboardChartLimitMessage("d", 31, 30))
+}
+
+func TestDashboardChartLimit(t *testing.T) {
+	providerData := func(config map[string]interface{}) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, Provider().Schema, config)
+	}
+
+	t.Setenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT", "")
+	t.Setenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT_ACTION", "")
+	limit, failPlan := dashboardChartLimit(providerData(nil))
+	assert.Equal(t, 0, limit)
+	assert.False(t, failPlan)
+
+	t.Setenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT", "many")
+	limit, _ = dashboardChartLimit(providerData(nil))
+	assert.Equal(t, 0, limit)
+
+	t.Setenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT", "40")
+	t.Setenv("LIGHTSTEP_DASHBOARD_CHART_LIMIT_ACTION", "error")
+	limit, failPlan = dashboardChartLimit(providerData(nil))
+	assert.Equal(t, 40, limit)
+	assert.True(t, failPlan)
+
+	// the provider attributes take precedence over the env vars
+	limit, failPlan = dashboardChartLimit(providerData(map[string]interface{}{
+		"dashboard_chart_limit":        20,
+		"dashboard_chart_limit_action": "warn",
+	}))
+	assert.Equal(t, 20, limit)
+	assert.False(t, failPlan)
+
+	// an explicit 0 disables the check set by the env var
+	limit, _ = dashboardChartLimit(providerData(map[string]interface{}{
+		"dashboard_chart_limit": 0,
+	}))
+	assert.Equal(t, 0, limit)
+}
+
+func TestCheckDashboardChartLimit(t *testing.T) {
+	// plans don't call the API, so the meta doesn't need a client
+	meta := &providerMeta{dashboardChartLimit: 4}
+	diff := func(topLevel int, grouped int) error {
+		r := resourceUnifiedDashboard(UnifiedChartSchema)
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(dashboardConfig(topLevel, grouped)), meta)
+		return err
+	}
+
+	require.NoError(t, diff(2, 2), "under the limit")
+	require.NoError(t, diff(3, 2), "over the limit only warns by default")
+
+	meta.failDashboardChartLimit = true
+	require.NoError(t, diff(2, 2), "under the limit")
+	err := diff(3, 2)
+	require.Error(t, err, "over the limit")
+	assert.Contains(t, err.Error(), `dashboard "Big dashboard" has 5 charts which exceeds the limit of 4`)
+}
+
+func TestDashboardChartLimitWarning(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUnifiedDashboard(UnifiedChartSchema).Schema, dashboardConfig(3, 2))
+
+	assert.Empty(t, dashboardChartLimitWarning(d, &providerMeta{}), "no limit")
+	assert.Empty(t, dashboardChartLimitWarning(d, &providerMeta{dashboardChartLimit: 5}), "within the limit")
+
+	diags := dashboardChartLimitWarning(d, &providerMeta{dashboardChartLimit: 4})
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Detail, `dashboard "Big dashboard" has 5 charts which exceeds the limit of 4`)
+}
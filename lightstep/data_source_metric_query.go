@@ -0,0 +1,79 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// metricQueryOutputPeriod is the resolution requested for lightstep_metric_query, only the
+// most recent point is used so it doesn't need to be fine-grained
+const metricQueryOutputPeriod = 60 * time.Second
+
+func dataSourceMetricQuery() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to run a single-series TQL query and read its most recent value, e.g. to assert on live metrics in a module.",
+		ReadContext: dataSourceLightstepMetricQueryRead,
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"query": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "TQL query returning a single series",
+			},
+			"lookback": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "10m",
+				ValidateFunc: validatePositiveDuration,
+				Description:  "How far back to query for points, as a duration such as 10m or 1h",
+			},
+			// Computed
+			"last_value": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Most recent value of the series",
+			},
+		},
+	}
+}
+
+func dataSourceLightstepMetricQueryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*providerMeta).client
+
+	project := d.Get("project_name").(string)
+	query := d.Get("query").(string)
+	lookback, err := parseDuration(d.Get("lookback").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("invalid lookback: %v", err))
+	}
+
+	now := time.Now().UTC().Truncate(metricQueryOutputPeriod)
+	result, err := c.QueryMetric(ctx, project, client.QueryTimeseriesRequest{
+		Query:        query,
+		OldestTime:   now.Add(-lookback),
+		YoungestTime: now,
+		OutputPeriod: int64(metricQueryOutputPeriod / time.Second),
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to run query: %v", err))
+	}
+
+	value, err := result.LastValue()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read last value of %q: %v", query, err))
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", project, query))
+	if err := d.Set("last_value", value); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
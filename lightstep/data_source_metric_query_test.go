@@ -0,0 +1,28 @@
+package lightstep
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMetricQueryDatasource(t *testing.T) {
+	queryConfig := `
+data "lightstep_metric_query" "constant" {
+  project_name = "` + testProject + `"
+  query        = "constant 42"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: queryConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lightstep_metric_query.constant", "last_value", "42"),
+				),
+			},
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,45 +21,127 @@ func dataSourceStream() *schema.Resource {
 				Required: true,
 			},
 			"stream_id": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"stream_id", "stream_name", "query"},
+				Description:  "ID of the stream. Exactly one of `stream_id`, `stream_name` and `query` must be set.",
 			},
-			// Computed
 			"stream_name": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the stream to look up, an error is reported if several streams of the project have it.",
+			},
+			"query": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Query of the stream to look up, whitespace differences outside of quoted values are ignored. An error is reported if several streams of the project have it.",
 			},
+			// Computed
 			"stream_query": {
 				Description: "Stream query",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"custom_data": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+				},
+			},
 		},
 	}
 }
 
 func dataSourceLightstepStreamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
-	s, err := c.GetStream(ctx, d.Get("project_name").(string), d.Get("stream_id").(string))
-	if err != nil {
-		apiErr, ok := err.(client.APIResponseCarrier)
-		if !ok {
-			return diag.FromErr(fmt.Errorf("failed to get stream: %v", err))
-		}
+	c := m.(*providerMeta).client
+	projectName := d.Get("project_name").(string)
+
+	var s *client.Stream
+	if id, ok := d.GetOk("stream_id"); ok {
+		var err error
+		s, err = c.GetStream(ctx, projectName, id.(string))
+		if err != nil {
+			apiErr, ok := err.(client.APIResponseCarrier)
+			if !ok {
+				return diag.FromErr(fmt.Errorf("failed to get stream: %v", err))
+			}
 
-		if apiErr.GetStatusCode() == http.StatusNotFound {
-			d.SetId("")
-			return diag.FromErr(fmt.Errorf("stream not found: %v", apiErr))
+			if apiErr.GetStatusCode() == http.StatusNotFound {
+				d.SetId("")
+				return diag.FromErr(fmt.Errorf("stream not found: %v", apiErr))
+			}
+			return diag.FromErr(fmt.Errorf("failed to get stream: %v", apiErr))
+		}
+	} else {
+		streams, err := c.ListStreams(ctx, projectName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to list streams: %v", err))
+		}
+		s, err = findStream(streams, d.Get("stream_name").(string), d.Get("query").(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("in project %v: %v", projectName, err))
 		}
-		return diag.FromErr(fmt.Errorf("failed to get stream: %v", apiErr))
 	}
+
 	d.SetId(s.ID)
+	if err := d.Set("stream_id", s.ID); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("stream_name", s.Attributes.Name); err != nil {
 		return diag.FromErr(err)
 	}
-
+	if err := d.Set("query", s.Attributes.Query); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("stream_query", s.Attributes.Query); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("custom_data", flattenStreamCustomData(*s)); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
+
+// noStreamError is returned by findStream when no stream matches
+type noStreamError struct {
+	lookup string
+}
+
+func (e noStreamError) Error() string {
+	return fmt.Sprintf("no stream %v", e.lookup)
+}
+
+// findStream returns the only stream with the name, or else with the query
+func findStream(streams []client.Stream, name string, query string) (*client.Stream, error) {
+	var (
+		matches []client.Stream
+		lookup  string
+	)
+	for _, s := range streams {
+		if name != "" && s.Attributes.Name == name ||
+			name == "" && normalizeQueryWhitespace(s.Attributes.Query) == normalizeQueryWhitespace(query) {
+			matches = append(matches, s)
+		}
+	}
+	if name != "" {
+		lookup = fmt.Sprintf("named %q", name)
+	} else {
+		lookup = fmt.Sprintf("with query %q", query)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, noStreamError{lookup: lookup}
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, s := range matches {
+		ids = append(ids, s.ID)
+	}
+	return nil, fmt.Errorf("%d streams %v (%v), look the stream up by stream_id instead", len(matches), lookup, strings.Join(ids, ", "))
+}
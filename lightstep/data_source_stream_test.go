@@ -1,9 +1,16 @@
 package lightstep
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 )
@@ -40,3 +47,87 @@ data "lightstep_stream" "stream_ds" {
 		},
 	})
 }
+
+func TestAccStreamDatasourceByName(t *testing.T) {
+	streamConfig := `
+resource "lightstep_stream" "aggie_errors_ds_name" {
+  project_name = "` + testProject + `"
+  stream_name  = "Aggie Errors DS by name"
+  query        = "service IN (\"aggie_ds_name\") AND \"error\" IN (\"true\")"
+  custom_data = [
+    {
+      "name" = "runbook"
+      "url"  = "https://example.com/runbook"
+    },
+  ]
+}
+
+data "lightstep_stream" "by_name" {
+  project_name = "` + testProject + `"
+  stream_name  = lightstep_stream.aggie_errors_ds_name.stream_name
+}
+`
+	var stream client.Stream
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: streamConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists("lightstep_stream.aggie_errors_ds_name", &stream),
+					resource.TestCheckResourceAttrPair("data.lightstep_stream.by_name", "id", "lightstep_stream.aggie_errors_ds_name", "id"),
+					resource.TestCheckResourceAttr("data.lightstep_stream.by_name", "query", "service IN (\"aggie_ds_name\") AND \"error\" IN (\"true\")"),
+					resource.TestCheckResourceAttr("data.lightstep_stream.by_name", "custom_data.0.url", "https://example.com/runbook"),
+				),
+			},
+		},
+	})
+}
+
+func TestStreamDatasourceLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [
+			{"id": "s1", "type": "stream", "attributes": {"name": "Errors", "query": "\"error\" IN (\"true\")",
+				"custom-data": {"runbook": {"url": "https://example.com/runbook"}}}},
+			{"id": "s2", "type": "stream", "attributes": {"name": "API", "query": "service IN (\"api\")"}},
+			{"id": "s3", "type": "stream", "attributes": {"name": "API", "query": "service IN (\"api\", \"web\")"}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	read := func(attributes map[string]string) (*schema.ResourceData, diag.Diagnostics) {
+		d := dataSourceStream().TestResourceData()
+		require.NoError(t, d.Set("project_name", "tacoman"))
+		for k, v := range attributes {
+			require.NoError(t, d.Set(k, v))
+		}
+		return d, dataSourceLightstepStreamRead(context.Background(), d, meta)
+	}
+
+	d, diags := read(map[string]string{"stream_name": "Errors"})
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "s1", d.Id())
+	assert.Equal(t, "s1", d.Get("stream_id"))
+	assert.Equal(t, `"error" IN ("true")`, d.Get("query"))
+	assert.Equal(t, "https://example.com/runbook", d.Get("custom_data.0.url"))
+
+	// whitespace differences in the query don't matter
+	d, diags = read(map[string]string{"query": `service IN ( "api", "web" )`})
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "s3", d.Id())
+	assert.Equal(t, "API", d.Get("stream_name"))
+
+	_, diags = read(map[string]string{"stream_name": "API"})
+	require.True(t, diags.HasError())
+	assert.Equal(t, `in project tacoman: 2 streams named "API" (s2, s3), look the stream up by stream_id instead`, diags[0].Summary)
+
+	_, diags = read(map[string]string{"stream_name": "Latency"})
+	require.True(t, diags.HasError())
+	assert.Equal(t, `in project tacoman: no stream named "Latency"`, diags[0].Summary)
+}
@@ -0,0 +1,91 @@
+package lightstep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const day = 24 * time.Hour
+
+// parseDuration parses a duration attribute: a Go duration such as "30s", "1h" or "1h30m", or
+// a whole number of days such as "7d", the way update intervals and retentions are written
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("expected a whole number of days such as \"7d\", got %q", s)
+		}
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration such as \"30s\", \"1h\" or \"7d\", got %q", s)
+	}
+	return d, nil
+}
+
+// formatDuration writes d the way parseDuration reads it: whole days as e.g. "7d", otherwise
+// without the zero units Go adds, e.g. "1h30m" rather than "1h30m0s"
+func formatDuration(d time.Duration) string {
+	if d != 0 && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// durationMillis parses a duration attribute into the milliseconds the API expects
+func durationMillis(s string) (int, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d%time.Millisecond != 0 {
+		return 0, fmt.Errorf("expected a duration in whole milliseconds, got %q", s)
+	}
+	return int(d.Milliseconds()), nil
+}
+
+// formatMillis formats milliseconds returned by the API as a duration attribute
+func formatMillis(ms int) string {
+	return formatDuration(time.Duration(ms) * time.Millisecond)
+}
+
+// validatePositiveDuration checks that a duration attribute, such as a query time shift or
+// lookback, is a positive Go duration, e.g. "1h" or "168h". Days aren't accepted since these
+// attributes are sent to the API as written.
+func validatePositiveDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	d, err := parseDuration(v)
+	if err != nil || strings.HasSuffix(v, "d") {
+		return nil, []error{fmt.Errorf("expected %s to be a duration such as \"1h\" or \"168h\", got %q", k, v)}
+	}
+	if d <= 0 {
+		return nil, []error{fmt.Errorf("expected %s to be a positive duration, got %q", k, v)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentDuration ignores differences between equal durations written
+// differently, e.g. 60m in the configuration and 1h returned by the API
+func suppressEquivalentDuration(_, old, new string, _ *schema.ResourceData) bool {
+	oldDuration, oldErr := parseDuration(old)
+	newDuration, newErr := parseDuration(new)
+	return oldErr == nil && newErr == nil && oldDuration == newDuration
+}
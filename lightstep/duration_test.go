@@ -0,0 +1,88 @@
+package lightstep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"30s":   30 * time.Second,
+		"1h":    time.Hour,
+		"1h30m": 90 * time.Minute,
+		"500ms": 500 * time.Millisecond,
+		"7d":    7 * 24 * time.Hour,
+		"0d":    0,
+	} {
+		d, err := parseDuration(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, d, s)
+	}
+
+	for _, invalid := range []string{"", "1", "one hour", "1.5d", "d", "1w"} {
+		_, err := parseDuration(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                       "0s",
+		500 * time.Millisecond:  "500ms",
+		30 * time.Second:        "30s",
+		2 * time.Minute:         "2m",
+		90 * time.Minute:        "1h30m",
+		12 * time.Hour:          "12h",
+		36 * time.Hour:          "36h",
+		14 * 24 * time.Hour:     "14d",
+		time.Hour + time.Second: "1h0m1s",
+	} {
+		s := formatDuration(d)
+		assert.Equal(t, expected, s)
+
+		// formatted durations parse back to the same duration
+		parsed, err := parseDuration(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, d, parsed, s)
+	}
+}
+
+func TestDurationMillis(t *testing.T) {
+	for _, s := range GetValidUpdateInterval() {
+		ms, err := durationMillis(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, formatMillis(ms), "update interval %v round-trips through milliseconds", s)
+		assert.Equal(t, s, GetUpdateIntervalValue(ms))
+	}
+
+	ms, err := durationMillis("1h")
+	require.NoError(t, err)
+	assert.Equal(t, 3600000, ms)
+
+	_, err = durationMillis("1us")
+	assert.Error(t, err)
+	_, err = durationMillis("soon")
+	assert.Error(t, err)
+
+	assert.Equal(t, "", GetUpdateIntervalValue(0))
+	assert.Equal(t, "invalid", GetUpdateIntervalValue(61000))
+}
+
+func TestValidatePositiveDuration(t *testing.T) {
+	for _, valid := range []string{"1h", "168h", "90m", "30s"} {
+		_, errs := validatePositiveDuration(valid, "time_shift")
+		assert.Empty(t, errs, valid)
+	}
+	for _, invalid := range []string{"", "0s", "-1h", "7d", "one week"} {
+		_, errs := validatePositiveDuration(invalid, "time_shift")
+		assert.Len(t, errs, 1, invalid)
+	}
+
+	assert.True(t, suppressEquivalentDuration("time_range", "60m", "1h", nil))
+	assert.True(t, suppressEquivalentDuration("time_range", "1d", "24h", nil))
+	assert.False(t, suppressEquivalentDuration("time_range", "1h", "2h", nil))
+	assert.False(t, suppressEquivalentDuration("time_range", "", "1h", nil))
+}
@@ -2,7 +2,6 @@ package lightstep
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"regexp"
 	"strings"
@@ -132,22 +131,7 @@ func compareUpdatedLegacyQueries(
 	}
 
 	// Step 1: call the SaaS to translate the legacy queries to UQL
-	type Response struct {
-		Data struct {
-			Queries []struct {
-				QueryName   string `json:"query-name"`
-				QueryString string `json:"tql-query"`
-			} `json:"queries"`
-		} `json:"data"`
-	}
-
-	resp := Response{}
-	req := map[string]interface{}{
-		"data": map[string]interface{}{
-			"queries": priorQueries,
-		},
-	}
-	err := c.CallAPI(context.Background(), "POST", fmt.Sprintf("projects/%v/query_translation", projectName), req, &resp)
+	translated, err := c.TranslateQueries(ctx, projectName, priorQueries)
 	if err != nil {
 		// don't short circuit; terraform saves invalid input in state, so we need to just assume the queries did
 		// change in this case (as long as the new ones are valid)
@@ -155,8 +139,8 @@ func compareUpdatedLegacyQueries(
 	}
 
 	priorUQL := make(map[string]string)
-	for _, q := range resp.Data.Queries {
-		priorUQL[simplifyQueryName(q.QueryName)] = q.QueryString
+	for _, q := range translated {
+		priorUQL[simplifyQueryName(q.Name)] = q.TQL
 	}
 
 	// Step 2: map the updated quries for comparison
@@ -194,3 +178,9 @@ var simplifyQueryNameRE = regexp.MustCompile(`[()\s]`)
 func simplifyQueryName(s string) string {
 	return simplifyQueryNameRE.ReplaceAllString(s, "")
 }
+
+// SimplifyQueryName simplifies a query name the way the provider compares them, for the tools
+// matching the queries of API responses by name
+func SimplifyQueryName(s string) string {
+	return simplifyQueryName(s)
+}
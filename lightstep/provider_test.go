@@ -1,10 +1,22 @@
 package lightstep
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -42,3 +54,303 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("LIGHTSTEP_ORG must be set")
 	}
 }
+
+func TestProviderClientOptions(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_KEY", "api-key")
+	t.Setenv("LIGHTSTEP_API_RATE_LIMIT", "5")
+	t.Setenv("LIGHTSTEP_API_READ_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_WRITE_RATE_LIMIT", "")
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "")
+	t.Setenv("LIGHTSTEP_API_TIMEOUT_SECONDS", "")
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization":    "org-name",
+		"retry_max":       2,
+		"timeout_seconds": 15,
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+
+	opts := p.Meta().(*providerMeta).client.Options()
+	assert.Equal(t, 5, opts.RateLimitPerSecond, "falls back to the env var")
+	assert.Equal(t, 2, opts.RetryMax)
+	assert.Equal(t, 15, opts.TimeoutSeconds)
+
+	// the provider attribute takes precedence over the env var
+	p = Provider()
+	diags = p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization":     "org-name",
+		"rate_limit":       1,
+		"write_rate_limit": 3,
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+	opts = p.Meta().(*providerMeta).client.Options()
+	assert.Equal(t, 1, opts.RateLimitPerSecond)
+	assert.Equal(t, 1, opts.ReadRateLimitPerSecond, "defaults to rate_limit")
+	assert.Equal(t, 3, opts.WriteRateLimitPerSecond)
+
+	// zero retries is a valid setting, not the client default
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "3")
+	p = Provider()
+	diags = p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization": "org-name",
+		"retry_max":    0,
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, client.NoRetries, p.Meta().(*providerMeta).client.Options().RetryMax)
+
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "0")
+	p = Provider()
+	diags = p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization": "org-name",
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, client.NoRetries, p.Meta().(*providerMeta).client.Options().RetryMax)
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "")
+
+	t.Setenv("LIGHTSTEP_API_TIMEOUT_SECONDS", "soon")
+	diags = Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization": "org-name",
+	}))
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Detail, "LIGHTSTEP_API_TIMEOUT_SECONDS must be an integer of at least 1")
+}
+
+func TestProviderEnvironmentWarning(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_KEY", "api-key")
+	t.Setenv("LIGHTSTEP_API_BASE_URL", "")
+	t.Setenv("LIGHTSTEP_API_PUBLIC_ENVS", "sandbox")
+
+	configure := func(env string) diag.Diagnostics {
+		return Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"organization": "org-name",
+			"environment":  env,
+		}))
+	}
+
+	for _, env := range []string{"public", "staging", "sandbox"} {
+		assert.Empty(t, configure(env), env)
+	}
+
+	diags := configure("prod")
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity, "unknown environments may be new regions")
+	assert.Equal(t, "Unknown Lightstep environment", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "https://api-prod.lightstep.com")
+
+	t.Setenv("LIGHTSTEP_ENV", "prod")
+	diags = Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization": "org-name",
+	}))
+	require.Len(t, diags, 1, "the environment falls back to LIGHTSTEP_ENV")
+	assert.Contains(t, diags[0].Detail, `The environment "prod" is not one of`)
+}
+
+func TestProviderInsecureSkipVerifyWarning(t *testing.T) {
+	t.Setenv("LIGHTSTEP_API_KEY", "api-key")
+	t.Setenv("LIGHTSTEP_API_BASE_URL", "")
+
+	configure := func(insecure bool) diag.Diagnostics {
+		return Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"organization":         "org-name",
+			"environment":          "public",
+			"insecure_skip_verify": insecure,
+		}))
+	}
+
+	t.Setenv("LIGHTSTEP_API_INSECURE", "true")
+	assert.Empty(t, configure(false), "the env var alone doesn't disable the verification")
+
+	diags := configure(true)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "TLS certificate verification is disabled", diags[0].Summary)
+
+	t.Setenv("LIGHTSTEP_API_INSECURE", "")
+	diags = configure(true)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "insecure_skip_verify is ignored", diags[0].Summary)
+}
+
+func TestProviderCredentialsFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LIGHTSTEP_API_KEY", "")
+	t.Setenv("LIGHTSTEP_ORG", "")
+	t.Setenv("LIGHTSTEP_CREDENTIALS_FILE", "")
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+
+	var authorization, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, path = r.Header.Get("Authorization"), r.URL.Path
+		_, err := w.Write([]byte(`{"data": []}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(credentialsFile, []byte(`
+# used when nothing else is configured
+[default]
+api_key      = file-key
+organization = file-org
+
+[staging]
+api_key      = staging-key
+organization = staging-org
+`), 0o600))
+
+	configure := func(config map[string]interface{}) (*client.Client, diag.Diagnostics) {
+		p := Provider()
+		diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(config))
+		meta, _ := p.Meta().(*providerMeta)
+		if meta == nil {
+			return nil, diags
+		}
+		return meta.client, diags
+	}
+
+	c, diags := configure(map[string]interface{}{"credentials_file": credentialsFile})
+	require.False(t, diags.HasError(), "%v", diags)
+	_, err := c.ListProjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "bearer file-key", authorization)
+	assert.Equal(t, "/public/v0.2/file-org/projects", path)
+
+	// the env vars take precedence over the file, which fills in what's missing
+	t.Setenv("LIGHTSTEP_API_KEY", "env-key")
+	t.Setenv("LIGHTSTEP_CREDENTIALS_FILE", credentialsFile)
+	c, diags = configure(map[string]interface{}{})
+	require.False(t, diags.HasError(), "%v", diags)
+	_, err = c.ListProjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "bearer env-key", authorization)
+	assert.Equal(t, "file-org", c.OrgName())
+
+	// and the provider attributes take precedence over everything
+	c, diags = configure(map[string]interface{}{"api_key": "attribute-key", "organization": "attribute-org"})
+	require.False(t, diags.HasError(), "%v", diags)
+	_, err = c.ListProjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "bearer attribute-key", authorization)
+	assert.Equal(t, "attribute-org", c.OrgName())
+
+	// a credentials file that is set explicitly must exist
+	t.Setenv("LIGHTSTEP_API_KEY", "")
+	_, diags = configure(map[string]interface{}{"credentials_file": filepath.Join(t.TempDir(), "missing")})
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Invalid credentials file", diags[0].Summary)
+
+	// without any credentials
+	t.Setenv("LIGHTSTEP_CREDENTIALS_FILE", "")
+	_, diags = configure(map[string]interface{}{"organization": "org-name"})
+	require.True(t, diags.HasError())
+	assert.Equal(t, "No api key found", diags[0].Summary)
+}
+
+func TestDeleteAlreadyDeleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"errors": ["not found"]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	for name, r := range Provider().ResourcesMap {
+		d := r.TestResourceData()
+		d.SetId("gone")
+		if _, ok := r.Schema["project_name"]; ok {
+			require.NoError(t, d.Set("project_name", "tacoman"), name)
+		}
+
+		diags := r.DeleteContext(context.Background(), d, meta)
+		assert.False(t, diags.HasError(), "%v: %v", name, diags)
+		assert.Empty(t, d.Id(), "%v is removed from the state", name)
+	}
+}
+
+func TestReadCredentialsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+
+	require.NoError(t, os.WriteFile(path, []byte("api_key = top-level-key\n; comment\n[default]\norganization=my-org\n"), 0o600))
+	creds, err := readCredentialsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, credentials{apiKey: "top-level-key", organization: "my-org"}, creds)
+
+	require.NoError(t, os.WriteFile(path, []byte("[default]\napi_key\n"), 0o600))
+	_, err = readCredentialsFile(path)
+	assert.ErrorContains(t, err, "credentials:2: expected key = value")
+}
+
+func TestAccProviderEnvironment(t *testing.T) {
+	var stream client.Stream
+
+	// the environment is configured once in the provider block instead of LIGHTSTEP_ENV
+	config := `
+provider "lightstep" {
+  environment = "` + client.EnvFromEnvironment() + `"
+}
+
+resource "lightstep_stream" "provider_environment" {
+  project_name = "` + testProject + `"
+  stream_name  = "Provider Environment"
+  query        = "service IN (\"provider-environment\")"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists("lightstep_stream.provider_environment", &stream),
+					resource.TestCheckResourceAttr("lightstep_stream.provider_environment", "stream_name", "Provider Environment"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProviderClientOptions(t *testing.T) {
+	config := `
+provider "lightstep" {
+  rate_limit      = 1
+  retry_max       = 2
+  timeout_seconds = 30
+}
+
+resource "lightstep_stream" "client_options" {
+  project_name = "` + testProject + `"
+  stream_name  = "Provider Client Options"
+  query        = "service IN (\"client-options\")"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						opts := testAccProvider.Meta().(*providerMeta).client.Options()
+						if opts.RateLimitPerSecond != 1 || opts.RetryMax != 2 || opts.TimeoutSeconds != 30 {
+							return fmt.Errorf("provider configuration was not applied to the client: %+v", opts)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,144 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// QueryComplexity returns a rough estimate of how expensive a query string is to evaluate.
+// Every pipeline stage, additional filter condition and named sub-query adds one point and
+// every join adds two. The score is a heuristic only and is not computed by the API.
+func QueryComplexity(query string) int {
+	if strings.TrimSpace(query) == "" {
+		return 0
+	}
+
+	// the first pipeline stage
+	complexity := 1
+
+	var (
+		inQuotes bool
+		word     strings.Builder
+	)
+	// words are only scored outside of quoted strings, so filter values never count
+	scoreWord := func() {
+		switch strings.ToLower(word.String()) {
+		case "and", "or":
+			complexity++
+		case "join":
+			complexity += 2
+		}
+		word.Reset()
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if inQuotes {
+			if r == '\\' {
+				i++
+			} else if r == '"' {
+				inQuotes = false
+			}
+			continue
+		}
+
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+			word.WriteRune(r)
+			continue
+		}
+		scoreWord()
+
+		switch {
+		case r == '"':
+			inQuotes = true
+		case (r == '|' || r == '&') && i+1 < len(runes) && runes[i+1] == r:
+			// "||" and "&&" combine filter conditions
+			complexity++
+			i++
+		case r == '|':
+			complexity++
+		case r == ';':
+			complexity++
+		}
+	}
+	scoreWord()
+
+	return complexity
+}
+
+// queryComplexityThreshold returns the opt-in complexity above which queries are warned about,
+// set via the LIGHTSTEP_QUERY_COMPLEXITY_THRESHOLD env var. Zero disables the check.
+func queryComplexityThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv("LIGHTSTEP_QUERY_COMPLEXITY_THRESHOLD"))
+	if err != nil || threshold < 0 {
+		return 0
+	}
+	return threshold
+}
+
+// queryComplexityWarnings returns a warning for every query whose complexity exceeds threshold.
+func queryComplexityWarnings(queries []string, threshold int) []string {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var warnings []string
+	for _, q := range queries {
+		if c := QueryComplexity(q); c > threshold {
+			warnings = append(warnings, fmt.Sprintf(
+				"query has an estimated complexity of %d which exceeds the threshold of %d and may be slow or rejected: %q",
+				c, threshold, q))
+		}
+	}
+	return warnings
+}
+
+// collectQueryStrings returns the values of all "query_string" attributes nested in v.
+func collectQueryStrings(v interface{}) []string {
+	var queries []string
+	switch v := v.(type) {
+	case *schema.Set:
+		queries = append(queries, collectQueryStrings(v.List())...)
+	case []interface{}:
+		for _, e := range v {
+			queries = append(queries, collectQueryStrings(e)...)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			if s, ok := e.(string); ok && k == "query_string" && s != "" {
+				queries = append(queries, s)
+				continue
+			}
+			queries = append(queries, collectQueryStrings(e)...)
+		}
+	}
+	return queries
+}
+
+// warnQueryComplexity is a CustomizeDiff function that logs a warning for overly complex
+// query strings in the given nested attributes. It never fails the plan.
+func warnQueryComplexity(attributes ...string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		threshold := queryComplexityThreshold()
+		if threshold == 0 {
+			return nil
+		}
+
+		var queries []string
+		for _, attr := range attributes {
+			queries = append(queries, collectQueryStrings(d.Get(attr))...)
+		}
+		for _, w := range queryComplexityWarnings(queries, threshold) {
+			log.Printf("[WARN] %s", w)
+		}
+		return nil
+	}
+}
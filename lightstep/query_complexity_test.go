@@ -0,0 +1,95 @@
+package lightstep
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryComplexity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{
+			name:     "empty",
+			query:    "  ",
+			expected: 0,
+		},
+		{
+			name:     "single stage",
+			query:    "metric requests",
+			expected: 1,
+		},
+		{
+			name:     "simple pipeline",
+			query:    "metric requests | rate 10m | group_by [], sum",
+			expected: 3,
+		},
+		{
+			name:     "combined filter conditions",
+			query:    `metric requests | filter service == "api" && region == "us" || env == "prod" | rate`,
+			expected: 5,
+		},
+		{
+			name:     "operators inside quoted values are ignored",
+			query:    `spans count | filter service == "a | b and c || d;" | rate`,
+			expected: 3,
+		},
+		{
+			name: "join of named sub-queries",
+			query: `with
+	a = metric requests | filter (service == "cats" and region == "us") | rate | group_by [], sum;
+	b = metric errors | filter (service == "cats") | rate | group_by [], sum;
+join a / b, a = 0, b = 0`,
+			// 1 first stage + 6 pipes + 1 "and" + 2 ";" + 2 join
+			expected: 12,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, QueryComplexity(tc.query))
+		})
+	}
+}
+
+func TestQueryComplexityWarnings(t *testing.T) {
+	simple := "metric requests | rate"
+	complex := `metric requests | filter a == "1" && b == "2" && c == "3" | rate | group_by [], sum`
+
+	assert.Empty(t, queryComplexityWarnings([]string{simple, complex}, 0), "a zero threshold disables warnings")
+	assert.Empty(t, queryComplexityWarnings([]string{simple}, 5))
+
+	warnings := queryComplexityWarnings([]string{simple, complex}, 5)
+	if assert.Len(t, warnings, 1) {
+		assert.Contains(t, warnings[0], "estimated complexity of 6 which exceeds the threshold of 5")
+	}
+}
+
+func TestQueryComplexityThreshold(t *testing.T) {
+	t.Setenv("LIGHTSTEP_QUERY_COMPLEXITY_THRESHOLD", "")
+	assert.Equal(t, 0, queryComplexityThreshold())
+
+	t.Setenv("LIGHTSTEP_QUERY_COMPLEXITY_THRESHOLD", "not-a-number")
+	assert.Equal(t, 0, queryComplexityThreshold())
+
+	t.Setenv("LIGHTSTEP_QUERY_COMPLEXITY_THRESHOLD", "20")
+	assert.Equal(t, 20, queryComplexityThreshold())
+}
+
+func TestCollectQueryStrings(t *testing.T) {
+	charts := schema.NewSet(func(interface{}) int { return 1 }, []interface{}{
+		map[string]interface{}{
+			"name": "Chart",
+			"query": []interface{}{
+				map[string]interface{}{"query_name": "a", "query_string": "metric a | rate"},
+				map[string]interface{}{"query_name": "b", "query_string": ""},
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"metric a | rate"}, collectQueryStrings(charts))
+}
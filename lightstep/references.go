@@ -0,0 +1,108 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+// reference is the ID of a stream or dashboard configured in the attribute at path
+type reference struct {
+	path string
+	id   string
+}
+
+// configuredStreamReferences returns the known stream IDs of a string or list of strings
+// attribute. IDs of resources that aren't created yet are unknown and skipped.
+func configuredStreamReferences(config cty.Value, attribute string) []reference {
+	value := config.GetAttr(attribute)
+	if value.IsNull() || !value.IsKnown() {
+		return nil
+	}
+	if value.Type() == cty.String {
+		return []reference{{path: attribute, id: value.AsString()}}
+	}
+
+	var refs []reference
+	for i, v := range value.AsValueSlice() {
+		if v.IsNull() || !v.IsKnown() {
+			continue
+		}
+		refs = append(refs, reference{path: fmt.Sprintf("%s[%d]", attribute, i), id: v.AsString()})
+	}
+	return refs
+}
+
+// configuredLinkedDashboardReferences returns the known dashboard IDs of the linked_dashboard
+// blocks. Links by dashboard_name are resolved, and checked, when applying.
+func configuredLinkedDashboardReferences(d *schema.ResourceDiff) []reference {
+	var refs []reference
+	for i, l := range d.Get("linked_dashboard").([]interface{}) {
+		link, _ := l.(map[string]interface{})
+		id, _ := link["dashboard_id"].(string)
+		if id == "" || !d.NewValueKnown(fmt.Sprintf("linked_dashboard.%d.dashboard_id", i)) {
+			continue
+		}
+		refs = append(refs, reference{path: fmt.Sprintf("linked_dashboard[%d].dashboard_id", i), id: id})
+	}
+	return refs
+}
+
+// checkReferences is a CustomizeDiff function that, when the provider sets validate_references,
+// checks with exists that the references returned by configured are in the project. kind names
+// what is referenced in the errors.
+func checkReferences(kind string, configured func(d *schema.ResourceDiff) []reference,
+	exists func(c *client.Client, ctx context.Context, project string, id string) (bool, error)) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		meta, ok := m.(*providerMeta)
+		if !ok || meta == nil || !meta.validateReferences {
+			return nil
+		}
+
+		if !d.NewValueKnown("project_name") {
+			return nil
+		}
+		project := d.Get("project_name").(string)
+
+		var missing []string
+		for _, ref := range configured(d) {
+			found, err := exists(meta.client, ctx, project, ref.id)
+			if err != nil {
+				return fmt.Errorf("could not list the %ss of project %v to check %v: %v", kind, project, ref.path, err)
+			}
+			if !found {
+				missing = append(missing, fmt.Sprintf("%v: %v %q does not exist in project %v", ref.path, kind, ref.id, project))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%s", strings.Join(missing, "; "))
+		}
+		return nil
+	}
+}
+
+// validateStreamReferences checks that the streams referenced by the attributes exist, see
+// checkReferences
+func validateStreamReferences(attributes ...string) schema.CustomizeDiffFunc {
+	configured := func(d *schema.ResourceDiff) []reference {
+		config := d.GetRawConfig()
+		if config.IsNull() {
+			return nil
+		}
+		var refs []reference
+		for _, attribute := range attributes {
+			refs = append(refs, configuredStreamReferences(config, attribute)...)
+		}
+		return refs
+	}
+	return checkReferences("stream", configured, (*client.Client).StreamIDExists)
+}
+
+// validateDashboardReferences checks that the dashboards linked to by ID exist, see
+// checkReferences
+var validateDashboardReferences = checkReferences("dashboard", configuredLinkedDashboardReferences, (*client.Client).UnifiedDashboardExists)
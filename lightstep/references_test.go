@@ -0,0 +1,54 @@
+package lightstep
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestValidateDashboardReferences(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [{"id": "d1", "attributes": {"name": "Checkout"}}]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging"), validateReferences: true}
+
+	diff := func(links ...map[string]interface{}) error {
+		var linked []interface{}
+		for _, l := range links {
+			linked = append(linked, l)
+		}
+		_, err := resourceUnifiedDashboard(UnifiedChartSchema).Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_name":     "tacoman",
+			"dashboard_name":   "Links",
+			"linked_dashboard": linked,
+		}), meta)
+		return err
+	}
+
+	require.NoError(t, diff(map[string]interface{}{"dashboard_id": "d1"}, map[string]interface{}{"dashboard_name": "Checkout"}))
+	assert.Equal(t, 1, calls)
+
+	err := diff(map[string]interface{}{"dashboard_id": "d1"}, map[string]interface{}{"dashboard_id": "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `linked_dashboard[1].dashboard_id: dashboard "missing" does not exist in project tacoman`)
+
+	meta.validateReferences = false
+	calls = 0
+	require.NoError(t, diff(map[string]interface{}{"dashboard_id": "missing"}), "only checked when the provider asks for it")
+	assert.Equal(t, 0, calls)
+}
@@ -3,6 +3,8 @@ package lightstep
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
@@ -10,6 +12,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccAlert(t *testing.T) {
@@ -580,6 +583,315 @@ EOT
 	})
 }
 
+func TestAccAlertEvaluationWindow(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	conditionConfig := func(evaluationWindow string) string {
+		return fmt.Sprintf(`
+resource "lightstep_alert" "test" {
+  project_name      = "%s"
+  name              = "High request rate"
+  evaluation_window = "%s"
+
+  expression {
+    is_multi = false
+    operand  = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  query {
+    query_name   = "a"
+    hidden       = false
+    display      = "line"
+    query_string = "metric requests | rate | group_by [], sum"
+  }
+}
+`, testProject, evaluationWindow)
+	}
+
+	resourceName := "lightstep_alert.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      conditionConfig("five minutes"),
+				ExpectError: regexp.MustCompile("evaluation_window"),
+			},
+			{
+				Config: conditionConfig("5m"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "evaluation_window", "5m"),
+				),
+			},
+			{
+				Config: conditionConfig("15m"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "evaluation_window", "15m"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
+
+func TestAccAlertSeverity(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	conditionConfig := func(name string, severity string) string {
+		return fmt.Sprintf(`
+resource "lightstep_alert" "%[2]s" {
+  project_name = "%[1]s"
+  name         = "High request rate (%[3]s)"
+  severity     = "%[3]s"
+
+  expression {
+    is_multi = false
+    operand  = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  query {
+    query_name   = "a"
+    hidden       = false
+    display      = "line"
+    query_string = "metric requests | rate | group_by [], sum"
+  }
+}
+`, testProject, name, severity)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      conditionConfig("test", "urgent"),
+				ExpectError: regexp.MustCompile("expected severity to be one of"),
+			},
+			{
+				Config: conditionConfig("critical", "critical") + conditionConfig("warning", "warning"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists("lightstep_alert.critical", &condition),
+					resource.TestCheckResourceAttr("lightstep_alert.critical", "severity", "critical"),
+					testAccChecLightstepAlertExists("lightstep_alert.warning", &condition),
+					resource.TestCheckResourceAttr("lightstep_alert.warning", "severity", "warning"),
+				),
+			},
+			{
+				ResourceName:        "lightstep_alert.warning",
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
+
+func TestAccAlertNoData(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	conditionConfig := func(noData string) string {
+		return fmt.Sprintf(`
+resource "lightstep_alert" "test" {
+  project_name = "%s"
+  name         = "High request rate"
+  no_data      = "%s"
+
+  expression {
+    is_multi = false
+    operand  = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  query {
+    query_name   = "a"
+    hidden       = false
+    display      = "line"
+    query_string = "metric requests | rate | group_by [], sum"
+  }
+}
+`, testProject, noData)
+	}
+
+	steps := []resource.TestStep{
+		{
+			Config:      conditionConfig("ignore"),
+			ExpectError: regexp.MustCompile("expected no_data to be one of"),
+		},
+	}
+	for _, noData := range []string{"alert", "no_alert", "keep_previous"} {
+		steps = append(steps, resource.TestStep{
+			Config: conditionConfig(noData),
+			Check: resource.ComposeTestCheckFunc(
+				testAccChecLightstepAlertExists("lightstep_alert.test", &condition),
+				resource.TestCheckResourceAttr("lightstep_alert.test", "no_data", noData),
+			),
+		})
+	}
+	steps = append(steps, resource.TestStep{
+		ResourceName:        "lightstep_alert.test",
+		ImportState:         true,
+		ImportStateVerify:   true,
+		ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps:        steps,
+	})
+}
+
+func TestAccAlertRenotify(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	conditionConfig := func(renotify string) string {
+		return fmt.Sprintf(`
+resource "lightstep_alert" "test" {
+  project_name = "%s"
+  name         = "High request rate"
+  renotify     = "%s"
+
+  expression {
+    is_multi = false
+    operand  = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  query {
+    query_name   = "a"
+    hidden       = false
+    display      = "line"
+    query_string = "metric requests | rate | group_by [], sum"
+  }
+}
+`, testProject, renotify)
+	}
+
+	resourceName := "lightstep_alert.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      conditionConfig("hourly"),
+				ExpectError: regexp.MustCompile("invalid renotify"),
+			},
+			{
+				Config: conditionConfig("1h"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "renotify", "1h"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+			{
+				Config: conditionConfig("never"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "renotify", "never"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateRenotify(t *testing.T) {
+	for _, valid := range []string{"never", "30m", "1h", "1d"} {
+		_, errs := validateRenotify(valid, "renotify")
+		assert.Empty(t, errs, valid)
+	}
+	for _, invalid := range []string{"", "hourly", "0s", "-1h", "1us"} {
+		_, errs := validateRenotify(invalid, "renotify")
+		assert.Len(t, errs, 1, invalid)
+	}
+
+	assert.True(t, suppressEquivalentRenotify("renotify", "1h", "60m", nil))
+	assert.True(t, suppressEquivalentRenotify("renotify", "never", "never", nil))
+	assert.False(t, suppressEquivalentRenotify("renotify", "1h", "never", nil))
+}
+
+func TestRenotifyUpdateIntervalConflict(t *testing.T) {
+	diff := func(renotify string) error {
+		config := map[string]interface{}{
+			"project_name": "tacoman",
+			"name":         "High request rate",
+			"alerting_rule": []interface{}{
+				map[string]interface{}{"id": "dest1", "update_interval": "1h"},
+			},
+		}
+		if renotify != "" {
+			config["renotify"] = renotify
+		}
+		_, err := resourceUnifiedCondition(UnifiedConditionSchema).Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+		return err
+	}
+
+	assert.ErrorContains(t, diff("1h"), "renotify conflicts with the update_interval of alerting_rule dest1")
+	assert.NoError(t, diff("never"))
+	assert.NoError(t, diff(""))
+}
+
+func TestNoDataConflict(t *testing.T) {
+	diff := func(noData string, isNoData bool) error {
+		config := map[string]interface{}{
+			"project_name": "tacoman",
+			"name":         "No requests",
+			"expression":   []interface{}{map[string]interface{}{"is_no_data": isNoData}},
+		}
+		if noData != "" {
+			config["no_data"] = noData
+		}
+		_, err := resourceUnifiedCondition(UnifiedConditionSchema).Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+		return err
+	}
+
+	assert.ErrorContains(t, diff("no_alert", true), `no_data = "no_alert" conflicts with expression.is_no_data`)
+	assert.ErrorContains(t, diff("keep_previous", true), "conflicts with expression.is_no_data")
+	assert.NoError(t, diff("alert", true))
+	assert.NoError(t, diff("", true))
+	assert.NoError(t, diff("no_alert", false))
+}
+
+func TestRenotifyOmittedWhenUnset(t *testing.T) {
+	d := resourceUnifiedCondition(UnifiedConditionSchema).TestResourceData()
+	attributes, err := getUnifiedConditionAttributesFromResource(d, UnifiedConditionSchema)
+	assert.NoError(t, err)
+	assert.Nil(t, attributes.RenotifyIntervalMS, "an unset renotify keeps the interval set outside of Terraform")
+
+	assert.NoError(t, d.Set("renotify", "never"))
+	attributes, err = getUnifiedConditionAttributesFromResource(d, UnifiedConditionSchema)
+	assert.NoError(t, err)
+	if assert.NotNil(t, attributes.RenotifyIntervalMS) {
+		assert.Equal(t, 0, *attributes.RenotifyIntervalMS, "never is sent to clear the interval")
+	}
+}
+
 func TestAccCompositeAlert(t *testing.T) {
 	var compositeCondition client.UnifiedCondition
 
@@ -923,7 +1235,7 @@ func testAccChecLightstepAlertExists(resourceName string, condition *client.Unif
 			return fmt.Errorf("id is not set")
 		}
 
-		providerClient := testAccProvider.Meta().(*client.Client)
+		providerClient := testAccProvider.Meta().(*providerMeta).client
 		cond, err := providerClient.GetUnifiedCondition(context.Background(), testProject, tfCondition.Primary.ID)
 		if err != nil {
 			return err
@@ -987,3 +1299,111 @@ resource "lightstep_alert" "test" {
 		},
 	})
 }
+
+func TestAccAlertMultipleDestinations(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	alertConfig := func(webhookDestinationID string) string {
+		return `
+resource "lightstep_slack_destination" "slack" {
+  project_name = "` + testProject + `"
+  channel      = "#emergency-room"
+}
+
+resource "lightstep_webhook_destination" "webhook" {
+  project_name     = "` + testProject + `"
+  destination_name = "Alert Destinations Acceptance Test"
+  url              = "https://www.lightstep.com"
+}
+
+resource "lightstep_alert" "test" {
+  project_name = "` + testProject + `"
+  name         = "Too many requests"
+
+  expression {
+    is_multi   = false
+    is_no_data = false
+    operand    = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  query {
+    query_name   = "a"
+    hidden       = false
+    query_string = "metric requests | rate 1h, 30s | group_by [], sum"
+  }
+
+  alerting_rule {
+    id              = lightstep_slack_destination.slack.id
+    update_interval = "1h"
+  }
+
+  alerting_rule {
+    id              = ` + webhookDestinationID + `
+    update_interval = "1d"
+  }
+}
+`
+	}
+
+	resourceName := "lightstep_alert.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      alertConfig(`"does-not-exist"`),
+				ExpectError: regexp.MustCompile("alerting_rule references destination does-not-exist which does not exist"),
+			},
+			{
+				Config: alertConfig("lightstep_webhook_destination.webhook.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccChecLightstepAlertExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "alerting_rule.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "alerting_rule.*", map[string]string{
+						"update_interval": "1h",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "alerting_rule.*", map[string]string{
+						"update_interval": "1d",
+					}),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "alerting_rule.*.id", "lightstep_slack_destination.slack", "id"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "alerting_rule.*.id", "lightstep_webhook_destination.webhook", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateAlertingRuleDestinations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public/v0.2/blars/projects/p/destinations/exists":
+			_, err := w.Write([]byte(`{"data": {"id": "exists", "type": "destination"}}`))
+			assert.NoError(t, err)
+		case "/public/v0.2/blars/projects/p/destinations/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	c := client.NewClient("api", "blars", "staging")
+
+	rule := func(id string) client.AlertingRule {
+		return client.AlertingRule{MessageDestinationID: id}
+	}
+
+	err := validateAlertingRuleDestinations(context.Background(), c, "p", []client.AlertingRule{rule("exists")})
+	assert.NoError(t, err)
+
+	err = validateAlertingRuleDestinations(context.Background(), c, "p", []client.AlertingRule{rule("exists"), rule("missing")})
+	assert.EqualError(t, err, "alerting_rule references destination missing which does not exist in project p")
+
+	err = validateAlertingRuleDestinations(context.Background(), c, "p", []client.AlertingRule{rule("forbidden")})
+	assert.ErrorContains(t, err, "failed to get destination forbidden referenced by alerting_rule")
+}
@@ -53,9 +53,9 @@ func resourceAlertingRule() *schema.Resource {
 func resourceAlertingRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 
-	updateIntervalMS := validUpdateInterval[d.Get("update_interval").(string)]
+	updateIntervalMS, _ := updateIntervalMillis(d.Get("update_interval").(string))
 
 	rule, err := client.CreateAlertingRule(
 		ctx,
@@ -78,7 +78,7 @@ func resourceAlertingRuleCreate(ctx context.Context, d *schema.ResourceData, m i
 func resourceAlertingRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	rule, err := c.GetAlertingRule(ctx, d.Get("project_name").(string), d.Id())
 	if err != nil {
 		apiErr, ok := err.(client.APIResponseCarrier)
@@ -105,16 +105,17 @@ func resourceAlertingRuleRead(ctx context.Context, d *schema.ResourceData, m int
 func resourceAlertingRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := m.(*client.Client)
-	if err := client.DeleteAlertingRule(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	client := m.(*providerMeta).client
+	if err := client.DeleteAlertingRule(ctx, d.Get("project_name").(string), d.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete alerting rule: %v", err))
 	}
 
+	d.SetId("")
 	return diags
 }
 
 func resourceAlertingRuleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
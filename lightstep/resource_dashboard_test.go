@@ -3,6 +3,8 @@ package lightstep
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccDashboard(t *testing.T) {
@@ -663,280 +667,1255 @@ EOT
 	})
 }
 
-func testGetMetricDashboardDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
-	for _, r := range s.RootModule().Resources {
-		if r.Type != "metric_alert" {
-			continue
-		}
-
-		s, err := conn.GetUnifiedDashboard(context.Background(), testProject, r.Primary.ID)
-		if err == nil {
-			if s.ID == r.Primary.ID {
-				return fmt.Errorf("metric dashboard with ID (%v) still exists.", r.Primary.ID)
-			}
-		}
-	}
-	return nil
-}
-
-func testAccCheckMetricDashboardExists(resourceName string, dashboard *client.UnifiedDashboard) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		tfDashboard, ok := s.RootModule().Resources[resourceName]
-		if !ok {
-			return fmt.Errorf("not found: %s", resourceName)
-		}
+// testAccGroupedChartConfig returns the configuration of lightstep_dashboard.test with a single
+// timeseries chart named "requests", which has the given attributes and queries. The chart is
+// defined in an explicit group so the imported state matches the config.
+func testAccGroupedChartConfig(dashboardName string, chartAttributes string, queries ...string) string {
+	return fmt.Sprintf(`
+resource "lightstep_dashboard" "test" {
+  project_name   = %q
+  dashboard_name = %q
 
-		if tfDashboard.Primary.ID == "" {
-			return fmt.Errorf("id is not set")
-		}
+  group {
+    rank            = 0
+    title           = "Requests"
+    visibility_type = "explicit"
 
-		c := testAccProvider.Meta().(*client.Client)
-		dash, err := c.GetUnifiedDashboard(context.Background(), testProject, tfDashboard.Primary.ID)
-		if err != nil {
-			return err
-		}
+    chart {
+      name = "requests"
+      rank = 0
+      type = "timeseries"
+      %s
+%s
+    }
+  }
+}
+`, testProject, dashboardName, chartAttributes, strings.Join(queries, ""))
+}
 
-		dashboard = dash
-		return nil
-	}
+// testAccChartQuery returns a query block for testAccGroupedChartConfig, with the given
+// attributes added to it
+func testAccChartQuery(name string, display string, attributes string) string {
+	return fmt.Sprintf(`
+      query {
+        query_name   = %q
+        display      = %q
+        hidden       = false
+        query_string = "metric requests | rate | group_by [], sum"
+        %s
+      }
+`, name, display, attributes)
 }
 
-func TestLegacyImplicitGroup(t *testing.T) {
+func TestAccDashboardTimeShift(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	baseConfig := `
-resource "lightstep_dashboard" "test_implicit_group" {
+	invalidTimeShiftConfig := `
+resource "lightstep_dashboard" "test" {
   project_name   = "` + testProject + `"
-  dashboard_name = "test legacy implicit groups"
-
-  # we declare this implicit group explicitly; it is thus _not_ a legacy implicit group
-  group {
-    rank            = 0
-    title           = ""
-    visibility_type = "implicit"
+  dashboard_name = "Acceptance Test Dashboard with Time Shift"
 
-    chart {
-      name   = "Kool Khart"
-      type   = "timeseries"
-      rank   = 0
-      x_pos  = 0
-      y_pos  = 0
-      width  = 0
-      height = 0
+  chart {
+    name = "Chart Number One"
+    rank = 1
+    type = "timeseries"
 
-      query {
-        query_name   = "a"
-        display      = "line"
-        hidden       = false
-        query_string = "metric cpu.utilization | delta | group_by[], sum"
-      }
+    query {
+      hidden       = false
+      query_name   = "a"
+      display      = "line"
+      query_string = "metric m | rate"
+      time_shift   = "one week"
     }
   }
 }
 `
 
-	resourceName := "lightstep_dashboard.test_implicit_group"
+	timeShiftConfig := testAccGroupedChartConfig("Acceptance Test Dashboard with Time Shift", "",
+		testAccChartQuery("a", "line", ""),
+		testAccChartQuery("b", "line", `time_shift = "168h"`),
+	)
+
+	resourceName := "lightstep_dashboard.test"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testGetMetricDashboardDestroy,
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: baseConfig,
+				Config:      invalidTimeShiftConfig,
+				ExpectError: regexp.MustCompile("time_shift to be a duration"),
+			},
+			{
+				Config: timeShiftConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test legacy implicit groups"),
-					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.time_shift", ""),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.1.time_shift", "168h"),
 				),
 			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
 		},
 	})
 }
 
-func TestGroupChartsAreComputed(t *testing.T) {
+func TestAccDashboardQueryAlias(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	baseConfig := `
-resource "lightstep_dashboard" "test_dash" {
-  project_name   = "` + testProject + `"
-  dashboard_name = "test dash"
+	aliasConfig := testAccGroupedChartConfig("Acceptance Test Dashboard with Query Alias", "",
+		testAccChartQuery("a", "line", `alias = "p99 latency"`),
+	)
 
-  label {
-    key   = "type"
-    value = "service"
-  }
+	resourceName := "lightstep_dashboard.test"
 
-  group {
-    rank            = 0
-    title           = ""
-    visibility_type = "implicit"
-    chart {
-      name   = "chart 1"
-      type   = "timeseries"
-      rank   = 1
-      x_pos  = 0
-      y_pos  = 0
-      width  = 16
-      height = 10
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: aliasConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.alias", "p99 latency"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
 
-      query {
-        query_name   = "a"
-        display      = "area"
-        hidden       = false
-        query_string = "metric foo | rate | group_by [], sum"
-      }
-    }
-	chart {
-      name   = "chart 2"
-      type   = "timeseries"
-      rank   = 3
-      x_pos  = 0
-      y_pos  = 0
-      width  = 0
-      height = 0
-      query {
-        query_name     = "(b-a)"
-        display        = "line"
-        hidden         = false
-        hidden_queries = { a = "true", b = "true" }
-        query_string   = "with\n  a = metric cpu.utilization | filter (kube_app == \"iOS\") | latest | group_by [], mean;\n  b = metric cpu.utilization | filter (kube_app == \"android\") | latest | group_by [], mean;\njoin ((b - a)), a=0, b=0"
-      }
-    }
-  }
+func TestAccDashboardBaseline(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	baselineConfig := testAccGroupedChartConfig("Acceptance Test Dashboard with Baseline", "",
+		testAccChartQuery("a", "line", `baseline {
+          lookback    = "168h"
+          sensitivity = "medium"
+        }`),
+	)
+
+	resourceName := "lightstep_dashboard.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: baselineConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.baseline.0.lookback", "168h"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.baseline.0.sensitivity", "medium"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
 }
-`
 
-	updatedConfig := `
-resource "lightstep_dashboard" "test_dash" {
+func TestAccDashboardDefaultGroupBy(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	// The second chart groups its query itself, which takes precedence over the dashboard default
+	defaultGroupByConfig := `
+resource "lightstep_dashboard" "test" {
   project_name   = "` + testProject + `"
-  dashboard_name = "test dash"
+  dashboard_name = "Acceptance Test Dashboard with Default Group-By"
 
-  label {
-    key   = "type"
-    value = "service"
+  default_group_by {
+    keys               = ["service"]
+    aggregation_method = "sum"
   }
 
   group {
     rank            = 0
-    title           = ""
-    visibility_type = "implicit"
+    title           = "Requests"
+    visibility_type = "explicit"
+
     chart {
-      name   = "chart 1!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!"
-      type   = "timeseries"
-      rank   = 1
-      x_pos  = 0
-      y_pos  = 0
-      width  = 16
-      height = 10
+      name = "Grouped by default"
+      rank = 1
+      type = "timeseries"
 
       query {
-        query_name   = "a"
-        display      = "area"
         hidden       = false
-        query_string = "metric foo | rate | group_by [], sum"
+        query_name   = "a"
+        display      = "line"
+        query_string = "metric m | rate"
       }
     }
-	chart {
-      name   = "chart 2"
-      type   = "timeseries"
-      rank   = 3
-      x_pos  = 0
-      y_pos  = 0
-      width  = 0
-      height = 0
+
+    chart {
+      name = "Grouped by region"
+      rank = 2
+      type = "timeseries"
+
       query {
-        query_name     = "(b-a)"
-        display        = "line"
-        hidden         = false
-        hidden_queries = { a = "true", b = "true" }
-        query_string   = "with\n  a = metric cpu.utilization | filter (kube_app == \"iOS\") | latest | group_by [], mean;\n  b = metric cpu.utilization | filter (kube_app == \"android\") | latest | group_by [], mean;\njoin ((b - a)), a=0, b=0"
+        hidden       = false
+        query_name   = "a"
+        display      = "line"
+        query_string = "metric m | rate | group_by [\"region\"], max"
       }
     }
   }
 }
 `
 
-	resourceName := "lightstep_dashboard.test_dash"
+	resourceName := "lightstep_dashboard.test"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testGetMetricDashboardDestroy,
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: baseConfig,
+				Config: defaultGroupByConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test dash"),
-					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "2"),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.0.chart.*",
-						map[string]string{
-							"name": "chart 1",
-						},
-					),
+					resource.TestCheckResourceAttr(resourceName, "default_group_by.0.keys.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "default_group_by.0.keys.0", "service"),
+					resource.TestCheckResourceAttr(resourceName, "default_group_by.0.aggregation_method", "sum"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.1.query.0.query_string", "metric m | rate | group_by [\"region\"], max"),
 				),
 			},
 			{
-				Config: updatedConfig,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test dash"),
-					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "2"),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.0.chart.*",
-						map[string]string{
-							"name": "chart 1!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!",
-						},
-					),
-				),
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
 			},
 		},
 	})
 }
 
-func makeDisplayTypeConfig(displayType, displayTypeOptions string) string {
-	return fmt.Sprintf(`
-resource "lightstep_dashboard" "test_display_type_options" {
-project_name   = "`+testProject+`"
-dashboard_name = "test display_type_options"
+func TestAccDashboardProtected(t *testing.T) {
+	var dashboard client.UnifiedDashboard
 
-group {
-rank            = 0
-title           = ""
-visibility_type = "implicit"
+	protectedConfig := func(protected bool) string {
+		return fmt.Sprintf(`
+resource "lightstep_dashboard" "test" {
+  project_name   = "%s"
+  dashboard_name = "Acceptance Test Protected Dashboard"
+  protected      = %t
+}
+`, testProject, protected)
+	}
 
-chart {
-	name   = "Chart #123"
-	type   = "timeseries"
-	rank   = 0
-	x_pos  = 4
-	y_pos  = 0
-	width  = 4
-	height = 4
+	resourceName := "lightstep_dashboard.test"
 
-	query {
-	  query_name   = "a"
-	  display      = "%v"
-	  %v
-	  hidden       = false
-	  query_string = "metric cpu.utilization | delta | group_by[], sum"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: protectedConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "protected", "true"),
+				),
+			},
+			{
+				// destroying a protected dashboard fails and leaves it in place
+				Config:      protectedConfig(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("is protected and cannot be deleted"),
+			},
+			{
+				// unprotect it so that it can be cleaned up
+				Config: protectedConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "protected", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboardDefaultTimeRange(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	config := `
+provider "lightstep" {
+  default_dashboard_time_range = "4h"
+}
+
+resource "lightstep_dashboard" "defaulted" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Default Time Range"
+}
+
+resource "lightstep_dashboard" "explicit" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Time Range"
+  time_range     = "30m"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists("lightstep_dashboard.defaulted", &dashboard),
+					resource.TestCheckResourceAttr("lightstep_dashboard.defaulted", "time_range", "4h"),
+					resource.TestCheckResourceAttr("lightstep_dashboard.explicit", "time_range", "30m"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboardIsDefault(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	config := func(isDefault bool) string {
+		return fmt.Sprintf(`
+resource "lightstep_dashboard" "test" {
+  project_name   = "%s"
+  dashboard_name = "Acceptance Test Home Dashboard"
+  is_default     = %t
+}
+`, testProject, isDefault)
+	}
+
+	resourceName := "lightstep_dashboard.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "is_default", "true"),
+					func(*terraform.State) error {
+						if !dashboard.Attributes.IsDefault {
+							return fmt.Errorf("dashboard %v is not the default dashboard", dashboard.ID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+			{
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "is_default", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboardIgnoreServerChanges(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	config := `
+resource "lightstep_dashboard" "test" {
+  project_name          = "` + testProject + `"
+  dashboard_name        = "Acceptance Test Dashboard Ignoring Ranks"
+  ignore_server_changes = ["chart_rank"]
+
+  group {
+    rank            = 0
+    title           = "Requests"
+    visibility_type = "explicit"
+
+    chart {
+      name = "Requests"
+      rank = 1
+      type = "timeseries"
+
+      query {
+        hidden       = false
+        query_name   = "a"
+        display      = "line"
+        query_string = "metric requests | rate"
+      }
+    }
+  }
+}
+`
+
+	resourceName := "lightstep_dashboard.test"
+
+	// reassignChartRanks changes the rank of every chart on the server, as the backend does
+	// when it reorders charts
+	reassignChartRanks := func(*terraform.State) error {
+		c := testAccProvider.Meta().(*providerMeta).client
+		current, err := c.GetUnifiedDashboard(context.Background(), testProject, dashboard.ID)
+		if err != nil {
+			return err
+		}
+		for i := range current.Attributes.Groups {
+			for j := range current.Attributes.Groups[i].Charts {
+				current.Attributes.Groups[i].Charts[j].Rank += 10
+			}
+		}
+		_, err = c.UpdateUnifiedDashboard(context.Background(), testProject, dashboard.ID, current.Attributes, "")
+		return err
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "ignore_server_changes.#", "1"),
+					reassignChartRanks,
+				),
+			},
+			{
+				// the rank changed on the server, but that isn't drift
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestPreserveIgnoredPanelFields(t *testing.T) {
+	prior := []interface{}{
+		map[string]interface{}{"id": "c1", "rank": 1, "x_pos": 0, "y_pos": 0, "width": 16, "height": 8},
+	}
+	read := func() []interface{} {
+		return []interface{}{
+			map[string]interface{}{"id": "c1", "rank": 11, "x_pos": 16, "y_pos": 8, "width": 32, "height": 4},
+			map[string]interface{}{"id": "new", "rank": 2, "x_pos": 0, "y_pos": 0, "width": 16, "height": 8},
+		}
+	}
+
+	panels := read()
+	preserveIgnoredPanelFields(prior, panels, map[string]bool{})
+	assert.Equal(t, read(), panels, "nothing is ignored")
+
+	panels = read()
+	preserveIgnoredPanelFields(prior, panels, map[string]bool{"chart_rank": true})
+	assert.Equal(t, 1, panels[0].(map[string]interface{})["rank"])
+	assert.Equal(t, 16, panels[0].(map[string]interface{})["x_pos"])
+	assert.Equal(t, read()[1], panels[1], "panels without prior state are kept as read")
+
+	panels = read()
+	preserveIgnoredPanelFields(prior, panels, map[string]bool{"chart_position": true})
+	assert.Equal(t, map[string]interface{}{"id": "c1", "rank": 11, "x_pos": 0, "y_pos": 0, "width": 16, "height": 8}, panels[0])
+}
+
+func TestSetDefaultDashboard(t *testing.T) {
+	var setDefault bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/public/v0.2/blars/projects/tacoman/metric_dashboards":
+			_, err := w.Write([]byte(`{"data": [
+				{"id": "home", "attributes": {"name": "Home", "is-default": true}},
+				{"id": "other", "attributes": {"name": "Other"}}
+			]}`))
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut && r.URL.Path == "/public/v0.2/blars/projects/tacoman/metric_dashboards/home/default":
+			setDefault = true
+			_, err := w.Write([]byte(`{}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := client.NewClient("api", "blars", "staging")
+
+	err := setDefaultDashboard(context.Background(), c, "tacoman", "other")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `dashboard "Home" (home) is already the default dashboard of project tacoman`)
+
+	// setting the current default dashboard again is fine
+	require.NoError(t, setDefaultDashboard(context.Background(), c, "tacoman", "home"))
+	assert.True(t, setDefault)
+}
+
+func testGetMetricDashboardDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*providerMeta).client
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "metric_alert" {
+			continue
+		}
+
+		s, err := conn.GetUnifiedDashboard(context.Background(), testProject, r.Primary.ID)
+		if err == nil {
+			if s.ID == r.Primary.ID {
+				return fmt.Errorf("metric dashboard with ID (%v) still exists.", r.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func testAccCheckMetricDashboardExists(resourceName string, dashboard *client.UnifiedDashboard) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		tfDashboard, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if tfDashboard.Primary.ID == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		c := testAccProvider.Meta().(*providerMeta).client
+		dash, err := c.GetUnifiedDashboard(context.Background(), testProject, tfDashboard.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		dashboard = dash
+		return nil
 	}
+}
+
+func TestLegacyImplicitGroup(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	baseConfig := `
+resource "lightstep_dashboard" "test_implicit_group" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "test legacy implicit groups"
+
+  # we declare this implicit group explicitly; it is thus _not_ a legacy implicit group
+  group {
+    rank            = 0
+    title           = ""
+    visibility_type = "implicit"
+
+    chart {
+      name   = "Kool Khart"
+      type   = "timeseries"
+      rank   = 0
+      x_pos  = 0
+      y_pos  = 0
+      width  = 0
+      height = 0
+
+      query {
+        query_name   = "a"
+        display      = "line"
+        hidden       = false
+        query_string = "metric cpu.utilization | delta | group_by[], sum"
+      }
+    }
   }
 }
+`
+
+	resourceName := "lightstep_dashboard.test_implicit_group"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test legacy implicit groups"),
+					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+				),
+			},
+		},
+	})
 }
-`, displayType, displayTypeOptions)
+
+func TestGroupChartsAreComputed(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	baseConfig := `
+resource "lightstep_dashboard" "test_dash" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "test dash"
+
+  label {
+    key   = "type"
+    value = "service"
+  }
+
+  group {
+    rank            = 0
+    title           = ""
+    visibility_type = "implicit"
+    chart {
+      name   = "chart 1"
+      type   = "timeseries"
+      rank   = 1
+      x_pos  = 0
+      y_pos  = 0
+      width  = 16
+      height = 10
+
+      query {
+        query_name   = "a"
+        display      = "area"
+        hidden       = false
+        query_string = "metric foo | rate | group_by [], sum"
+      }
+    }
+	chart {
+      name   = "chart 2"
+      type   = "timeseries"
+      rank   = 3
+      x_pos  = 0
+      y_pos  = 0
+      width  = 0
+      height = 0
+      query {
+        query_name     = "(b-a)"
+        display        = "line"
+        hidden         = false
+        hidden_queries = { a = "true", b = "true" }
+        query_string   = "with\n  a = metric cpu.utilization | filter (kube_app == \"iOS\") | latest | group_by [], mean;\n  b = metric cpu.utilization | filter (kube_app == \"android\") | latest | group_by [], mean;\njoin ((b - a)), a=0, b=0"
+      }
+    }
+  }
+}
+`
+
+	updatedConfig := `
+resource "lightstep_dashboard" "test_dash" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "test dash"
+
+  label {
+    key   = "type"
+    value = "service"
+  }
+
+  group {
+    rank            = 0
+    title           = ""
+    visibility_type = "implicit"
+    chart {
+      name   = "chart 1!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!"
+      type   = "timeseries"
+      rank   = 1
+      x_pos  = 0
+      y_pos  = 0
+      width  = 16
+      height = 10
+
+      query {
+        query_name   = "a"
+        display      = "area"
+        hidden       = false
+        query_string = "metric foo | rate | group_by [], sum"
+      }
+    }
+	chart {
+      name   = "chart 2"
+      type   = "timeseries"
+      rank   = 3
+      x_pos  = 0
+      y_pos  = 0
+      width  = 0
+      height = 0
+      query {
+        query_name     = "(b-a)"
+        display        = "line"
+        hidden         = false
+        hidden_queries = { a = "true", b = "true" }
+        query_string   = "with\n  a = metric cpu.utilization | filter (kube_app == \"iOS\") | latest | group_by [], mean;\n  b = metric cpu.utilization | filter (kube_app == \"android\") | latest | group_by [], mean;\njoin ((b - a)), a=0, b=0"
+      }
+    }
+  }
+}
+`
+
+	resourceName := "lightstep_dashboard.test_dash"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test dash"),
+					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.0.chart.*",
+						map[string]string{
+							"name": "chart 1",
+						},
+					),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test dash"),
+					resource.TestCheckResourceAttr(resourceName, "chart.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.0.chart.*",
+						map[string]string{
+							"name": "chart 1!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!",
+						},
+					),
+				),
+			},
+		},
+	})
+}
+
+func makeDisplayTypeConfig(displayType, displayTypeOptions string) string {
+	return fmt.Sprintf(`
+resource "lightstep_dashboard" "test_display_type_options" {
+project_name   = "`+testProject+`"
+dashboard_name = "test display_type_options"
+
+group {
+rank            = 0
+title           = ""
+visibility_type = "implicit"
+
+chart {
+	name   = "Chart #123"
+	type   = "timeseries"
+	rank   = 0
+	x_pos  = 4
+	y_pos  = 0
+	width  = 4
+	height = 4
+
+	query {
+	  query_name   = "a"
+	  display      = "%v"
+	  %v
+	  hidden       = false
+	  query_string = "metric cpu.utilization | delta | group_by[], sum"
+	}
+  }
+}
+}
+`, displayType, displayTypeOptions)
+}
+
+func TestDisplayTypeOptionsError(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_display_type_options"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
+	display_type_options {
+			positive_data_only = true
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+				),
+				ExpectError: regexp.MustCompile("Unsupported argument"),
+			},
+		},
+	})
+}
+
+func TestDisplayTypeOptions(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_display_type_options"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: makeDisplayTypeConfig("line", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
+				),
+			},
+			{
+				Config: makeDisplayTypeConfig("ordered_list", strings.TrimSpace(`
+	display_type_options {
+			sort_direction = "asc"
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "ordered_list"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_direction", "asc"),
+				),
+			},
+			{
+				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
+	display_type_options {
+			sort_direction = "desc"
+			sort_by = "value"
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_direction", "desc"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_by", "value"),
+				),
+			},
+			{
+				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
+	display_type_options {
+			y_axis_scale = "log"
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_scale", "log"),
+				),
+			},
+			{
+				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
+	display_type_options {
+			y_axis_scale = "log"
+			y_axis_log_base = 2
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_scale", "log"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_log_base", "2"),
+				),
+			},
+			{
+				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
+	display_type_options {
+			y_axis_min = 0
+			y_axis_max = 100
+	}
+`)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_min", "0"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_max", "100"),
+				),
+			},
+		},
+	})
+}
+
+func TestTextPanels(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_text_panels"
+
+	chartDescriptor := `
+	chart {
+		name   = "cpu"
+		type   = "timeseries"
+		rank   = 0
+		x_pos  = 4
+		y_pos  = 0
+		width  = 4
+		height = 4
+	
+		query {
+		  query_name   = "a"
+		  display      = "line"
+		  hidden       = false
+		  query_string = "metric cpu.utilization | delta | group_by[], sum"
+		}
+	}`
+
+	longText := strings.Repeat("abc ", 1024)
+
+	makeTextPanelTestConfig := func(extra1, extra2 string) string {
+		return fmt.Sprintf(`
+resource "lightstep_dashboard" "test_text_panels" {
+	project_name   = "`+testProject+`"
+	dashboard_name   = "test_text_panels"
+	
+	group {
+		rank            = 0
+		title           = ""
+		visibility_type = "implicit"
+
+		%v
+
+		text_panel {
+			name = "Don't panic 😅"
+			text = "# Hello **world**...?"
+		}
+		text_panel {
+			text = "## Hello world"
+			%v
+		}
+	}
+}
+	`, extra1, extra2) //, resourceName, extra)
+	}
+
+	makeTextPanelTestConfig2 := func(body string) string {
+		return fmt.Sprintf(`
+resource "lightstep_dashboard" "test_text_panels" {
+	project_name   = "`+testProject+`"
+	dashboard_name   = "test_text_panels"
+	
+	%v
+}
+	`, body) //, resourceName, extra)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Check an invalid text_panel property
+				Config: makeTextPanelTestConfig("", "nonsense = \"true\""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+				),
+				ExpectError: regexp.MustCompile("Unsupported argument"),
+			},
+			{
+				// Rank is not a property of text_panel (explicit position is used instead)
+				Config: makeTextPanelTestConfig("", "rank = 0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+				),
+				ExpectError: regexp.MustCompile("Unsupported argument"),
+			},
+			{
+				// Check the base config
+				Config: makeTextPanelTestConfig("", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.name", "Don't panic 😅"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
+				),
+			},
+			{
+				// Update with a chart
+				Config: makeTextPanelTestConfig(chartDescriptor, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
+				),
+			},
+			{
+				// Update with explicit positioning
+				Config: makeTextPanelTestConfig(chartDescriptor, `
+		x_pos  = 4
+		y_pos  = 4
+		width  = 4
+		height = 4
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.x_pos", "4"),
+				),
+			},
+			{
+				// Update with a long text string
+				Config: strings.Replace(makeTextPanelTestConfig("", ""), "## Hello world", longText, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", longText),
+				),
+			},
+			{
+				// Single group, single text panel
+				Config: makeTextPanelTestConfig2(`
+	group {
+		rank            = 0
+		title           = ""
+		visibility_type = "implicit"
+
+		text_panel {
+			text = "single"
+		}
+	}
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.#", "1"),
+				),
+			},
+			{
+				// Multiple groups, single text panel
+				Config: makeTextPanelTestConfig2(`
+	group {
+		rank            = 0
+		title           = ""
+		visibility_type = "implicit"
+
+		text_panel {
+			text = "single0"
+		}
+	}
+	group {
+		rank            = 1
+		title           = ""
+		visibility_type = "implicit"
+
+		text_panel {
+			text = "single1"
+		}
+	}
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
+						"text_panel.#":      "1",
+						"text_panel.0.text": "single0",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
+						"text_panel.#":      "1",
+						"text_panel.0.text": "single1",
+					}),
+				),
+			},
+			{
+				// Multiple groups, multiple text panels
+				Config: makeTextPanelTestConfig2(`
+	group {
+		rank            = 0
+		title           = ""
+		visibility_type = "implicit"
+
+		text_panel {
+			text = "single0.0"
+		}
+		text_panel {
+			text = "single0.1"
+		}
+	}
+	group {
+		rank            = 1
+		title           = ""
+		visibility_type = "implicit"
+
+		text_panel {
+			text = "single1.0"
+		}
+		text_panel {
+			text = "single1.1"
+		}
+		text_panel {
+			text = "single1.2"
+		}
+	}
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
+						"text_panel.#":      "2",
+						"text_panel.0.text": "single0.0",
+						"text_panel.1.text": "single0.1",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
+						"text_panel.#":      "3",
+						"text_panel.0.text": "single1.0",
+						"text_panel.1.text": "single1.1",
+						"text_panel.2.text": "single1.2",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestTextPanelsOutsideGroups(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_text_panels"
+
+	makeTextPanelTestConfig := func() string {
+		return `
+resource "lightstep_dashboard" "test_text_panels" {
+	project_name   = "` + testProject + `"
+	dashboard_name   = "test_text_panels"
+	
+	text_panel {
+		name = "Do panic 😅"
+		text = "# Hello **world**...?"
+	}
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Check an invalid text_panel property
+				Config: makeTextPanelTestConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+				),
+				ExpectError: regexp.MustCompile("Unsupported block type"),
+			},
+		},
+	})
+}
+
+func TestSubtitle(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_subtitle"
+
+	configTemplate := `
+resource "lightstep_dashboard" "test_subtitle" {
+project_name   = "` + testProject + `"
+dashboard_name = "test display_type_options"
+
+group {
+	rank            = 0
+	title           = ""
+	visibility_type = "implicit"
+	
+	chart {
+		name   = "overall cpu utilization"
+		type   = "timeseries"
+		rank   = 0
+		x_pos  = 4
+		y_pos  = 0
+		width  = 4
+		height = 4
+		%s
+	
+		query {
+		  query_name   = "a"
+		  display      = "big_number"
+		  hidden       = false
+		  query_string = "metric cpu.utilization | delta | group_by[], sum"
+		}
+	  }
+	}
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				// empty subtitle
+				Config: fmt.Sprintf(configTemplate, `subtitle = ""`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.subtitle", ""),
+				),
+			},
+			{
+				// subtitle too long, this should be a chart-level description or something
+				Config: fmt.Sprintf(configTemplate, `subtitle = "this number represents the percentage of CPU cycles available to us that we have actually used"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
+				),
+				ExpectError: regexp.MustCompile("expected length of group.0.chart.0.subtitle to be in the range \\(0 - 37\\).*"),
+			},
+			{
+				// normal subtitle
+				Config: fmt.Sprintf(configTemplate, `subtitle = "percent"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.subtitle", "percent"),
+				),
+			},
+		},
+	})
 }
 
-func TestDisplayTypeOptionsError(t *testing.T) {
+func TestAccDashboardChartDescription(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	resourceName := "lightstep_dashboard.test_display_type_options"
+	resourceName := "lightstep_dashboard.test"
+
+	config := func(description string) string {
+		return testAccGroupedChartConfig("Acceptance Test Dashboard with Chart Description", description,
+			testAccChartQuery("a", "line", ""),
+		)
+	}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -944,24 +1923,40 @@ func TestDisplayTypeOptionsError(t *testing.T) {
 		CheckDestroy: testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
-	display_type_options {
-			positive_data_only = true
-	}
-`)),
+				Config: config(`description = "p99 latency of the checkout service, the SLO is 500ms"`),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.description", "p99 latency of the checkout service, the SLO is 500ms"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+			{
+				// removing the description clears it
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.description", ""),
 				),
-				ExpectError: regexp.MustCompile("Unsupported argument"),
 			},
 		},
 	})
 }
 
-func TestDisplayTypeOptions(t *testing.T) {
+func TestAccDashboardPrecision(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	resourceName := "lightstep_dashboard.test_display_type_options"
+	resourceName := "lightstep_dashboard.test"
+
+	config := func(display string) string {
+		return testAccGroupedChartConfig("Acceptance Test Dashboard with Precision", "precision = 2",
+			testAccChartQuery("a", display, ""),
+		)
+	}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -969,148 +1964,79 @@ func TestDisplayTypeOptions(t *testing.T) {
 		CheckDestroy: testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: makeDisplayTypeConfig("line", ""),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
-				),
+				Config:      config("line"),
+				ExpectError: regexp.MustCompile(`chart "requests": precision is only supported by charts with a big_number or big_number_v2 query`),
 			},
 			{
-				Config: makeDisplayTypeConfig("ordered_list", strings.TrimSpace(`
-	display_type_options {
-			sort_direction = "asc"
-	}
-`)),
+				Config: config("big_number"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "ordered_list"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_direction", "asc"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.precision", "2"),
 				),
 			},
 			{
-				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
-	display_type_options {
-			sort_direction = "desc"
-			sort_by = "value"
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
+
+func TestAccDashboardSparkLine(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test"
+
+	config := func(showSparkLine bool, display string) string {
+		return testAccGroupedChartConfig("Acceptance Test Dashboard with Spark Line", fmt.Sprintf("show_spark_line = %t", showSparkLine),
+			testAccChartQuery("a", display, ""),
+		)
 	}
-`)),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_direction", "desc"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.sort_by", "value"),
-				),
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(true, "line"),
+				ExpectError: regexp.MustCompile(`chart "requests": show_spark_line is only supported by charts with a big_number or big_number_v2 query`),
 			},
 			{
-				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
-	display_type_options {
-			y_axis_scale = "log"
-	}
-`)),
+				Config: config(true, "big_number"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_scale", "log"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.show_spark_line", "true"),
 				),
 			},
 			{
-				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
-	display_type_options {
-			y_axis_scale = "log"
-			y_axis_log_base = 2
-	}
-`)),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_scale", "log"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_log_base", "2"),
-				),
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
 			},
 			{
-				Config: makeDisplayTypeConfig("table", strings.TrimSpace(`
-	display_type_options {
-			y_axis_min = 0
-			y_axis_max = 100
-	}
-`)),
+				Config: config(false, "big_number"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test display_type_options"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "table"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_min", "0"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display_type_options.0.y_axis_max", "100"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.show_spark_line", "false"),
 				),
 			},
 		},
 	})
 }
 
-func TestTextPanels(t *testing.T) {
+func TestAccDashboardDisplayUnit(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	resourceName := "lightstep_dashboard.test_text_panels"
-
-	chartDescriptor := `
-	chart {
-		name   = "cpu"
-		type   = "timeseries"
-		rank   = 0
-		x_pos  = 4
-		y_pos  = 0
-		width  = 4
-		height = 4
-	
-		query {
-		  query_name   = "a"
-		  display      = "line"
-		  hidden       = false
-		  query_string = "metric cpu.utilization | delta | group_by[], sum"
-		}
-	}`
-
-	longText := strings.Repeat("abc ", 1024)
-
-	makeTextPanelTestConfig := func(extra1, extra2 string) string {
-		return fmt.Sprintf(`
-resource "lightstep_dashboard" "test_text_panels" {
-	project_name   = "`+testProject+`"
-	dashboard_name   = "test_text_panels"
-	
-	group {
-		rank            = 0
-		title           = ""
-		visibility_type = "implicit"
-
-		%v
-
-		text_panel {
-			name = "Don't panic 😅"
-			text = "# Hello **world**...?"
-		}
-		text_panel {
-			text = "## Hello world"
-			%v
-		}
-	}
-}
-	`, extra1, extra2) //, resourceName, extra)
-	}
+	resourceName := "lightstep_dashboard.test"
 
-	makeTextPanelTestConfig2 := func(body string) string {
-		return fmt.Sprintf(`
-resource "lightstep_dashboard" "test_text_panels" {
-	project_name   = "`+testProject+`"
-	dashboard_name   = "test_text_panels"
-	
-	%v
-}
-	`, body) //, resourceName, extra)
+	config := func(chartAttributes string) string {
+		return testAccGroupedChartConfig("Acceptance Test Dashboard with Display Unit", chartAttributes,
+			testAccChartQuery("a", "line", ""),
+		)
 	}
 
 	resource.Test(t, resource.TestCase{
@@ -1119,194 +2045,211 @@ resource "lightstep_dashboard" "test_text_panels" {
 		CheckDestroy: testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				// Check an invalid text_panel property
-				Config: makeTextPanelTestConfig("", "nonsense = \"true\""),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-				),
-				ExpectError: regexp.MustCompile("Unsupported argument"),
-			},
-			{
-				// Rank is not a property of text_panel (explicit position is used instead)
-				Config: makeTextPanelTestConfig("", "rank = 0"),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-				),
-				ExpectError: regexp.MustCompile("Unsupported argument"),
-			},
-			{
-				// Check the base config
-				Config: makeTextPanelTestConfig("", ""),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.name", "Don't panic 😅"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
-				),
+				Config:      config(`display_scale = -1`),
+				ExpectError: regexp.MustCompile("expected group.0.chart.0.display_scale to be positive"),
 			},
 			{
-				// Update with a chart
-				Config: makeTextPanelTestConfig(chartDescriptor, ""),
+				// bytes displayed as MB
+				Config: config(`display_unit = "MB"
+      display_scale = 0.000001`),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.display_unit", "MB"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.display_scale", "1e-06"),
 				),
 			},
 			{
-				// Update with explicit positioning
-				Config: makeTextPanelTestConfig(chartDescriptor, `
-		x_pos  = 4
-		y_pos  = 4
-		width  = 4
-		height = 4
-`),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", "## Hello world"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.query.0.display", "line"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.x_pos", "4"),
-				),
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
 			},
+		},
+	})
+}
+
+func TestAccDashboardTemplateVariablePresets(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_presets"
+
+	configTemplate := `
+resource "lightstep_dashboard" "test_presets" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Presets"
+
+  template_variable {
+    name                     = "service"
+    default_values           = []
+    suggestion_attribute_key = "service.name"
+  }
+
+  template_variable {
+    name                     = "region"
+    default_values           = []
+    suggestion_attribute_key = "cloud.region"
+  }
+
+  preset {
+    name = "Checkout in us-east-1"
+
+    variable {
+      name   = "service"
+      values = ["checkout"]
+    }
+
+    variable {
+      name   = "%s"
+      values = ["us-east-1"]
+    }
+  }
+
+  preset {
+    name = "Frontends"
+
+    variable {
+      name   = "service"
+      values = ["web", "ios"]
+    }
+  }
+
+  chart {
+    name = "Requests"
+    rank = 0
+    type = "timeseries"
+
+    query {
+      query_name   = "a"
+      display      = "line"
+      hidden       = false
+      query_string = "metric requests | filter service == $service | rate | group_by[], sum"
+    }
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
 			{
-				// Update with a long text string
-				Config: strings.Replace(makeTextPanelTestConfig("", ""), "## Hello world", longText, 1),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.0.text", "# Hello **world**...?"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.1.text", longText),
-				),
+				Config:      fmt.Sprintf(configTemplate, "zone"),
+				ExpectError: regexp.MustCompile(`preset "Checkout in us-east-1": "zone" is not a template variable of the dashboard`),
 			},
 			{
-				// Single group, single text panel
-				Config: makeTextPanelTestConfig2(`
-	group {
-		rank            = 0
-		title           = ""
-		visibility_type = "implicit"
-
-		text_panel {
-			text = "single"
-		}
-	}
-				`),
+				Config: fmt.Sprintf(configTemplate, "region"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.text_panel.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "preset.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "preset.0.name", "Checkout in us-east-1"),
+					resource.TestCheckResourceAttr(resourceName, "preset.0.variable.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "preset.0.variable.1.name", "region"),
+					resource.TestCheckResourceAttr(resourceName, "preset.0.variable.1.values.0", "us-east-1"),
+					resource.TestCheckResourceAttr(resourceName, "preset.1.name", "Frontends"),
+					resource.TestCheckResourceAttr(resourceName, "preset.1.variable.0.values.#", "2"),
 				),
 			},
 			{
-				// Multiple groups, single text panel
-				Config: makeTextPanelTestConfig2(`
-	group {
-		rank            = 0
-		title           = ""
-		visibility_type = "implicit"
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
 
-		text_panel {
-			text = "single0"
-		}
-	}
-	group {
-		rank            = 1
-		title           = ""
-		visibility_type = "implicit"
+func TestAccDashboardEventOverlays(t *testing.T) {
+	var dashboard client.UnifiedDashboard
 
-		text_panel {
-			text = "single1"
-		}
-	}
-				`),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
-						"text_panel.#":      "1",
-						"text_panel.0.text": "single0",
-					}),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
-						"text_panel.#":      "1",
-						"text_panel.0.text": "single1",
-					}),
-				),
-			},
-			{
-				// Multiple groups, multiple text panels
-				Config: makeTextPanelTestConfig2(`
-	group {
-		rank            = 0
-		title           = ""
-		visibility_type = "implicit"
+	resourceName := "lightstep_dashboard.test_event_overlays"
 
-		text_panel {
-			text = "single0.0"
-		}
-		text_panel {
-			text = "single0.1"
-		}
-	}
-	group {
-		rank            = 1
-		title           = ""
-		visibility_type = "implicit"
+	configTemplate := `
+resource "lightstep_dashboard" "test_event_overlays" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Event Overlays"
 
-		text_panel {
-			text = "single1.0"
-		}
-		text_panel {
-			text = "single1.1"
-		}
-		text_panel {
-			text = "single1.2"
-		}
-	}
-				`),
+  event_overlay {
+    name   = "Checkout deploys"
+    source = "%s"
+    query  = "service = \"checkout\""
+  }
+
+  chart {
+    name = "Requests"
+    rank = 0
+    type = "timeseries"
+
+    query {
+      query_name   = "a"
+      display      = "line"
+      hidden       = false
+      query_string = "metric requests | rate | group_by[], sum"
+    }
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(configTemplate, "releases"),
+				ExpectError: regexp.MustCompile(`expected event_overlay.0.source to be one of \["deploys" "events"\]`),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "deploys"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "dashboard_name", "test_text_panels"),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
-						"text_panel.#":      "2",
-						"text_panel.0.text": "single0.0",
-						"text_panel.1.text": "single0.1",
-					}),
-					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{
-						"text_panel.#":      "3",
-						"text_panel.0.text": "single1.0",
-						"text_panel.1.text": "single1.1",
-						"text_panel.2.text": "single1.2",
-					}),
+					resource.TestCheckResourceAttr(resourceName, "event_overlay.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "event_overlay.0.name", "Checkout deploys"),
+					resource.TestCheckResourceAttr(resourceName, "event_overlay.0.source", "deploys"),
+					resource.TestCheckResourceAttr(resourceName, "event_overlay.0.query", `service = "checkout"`),
 				),
 			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
 		},
 	})
 }
 
-func TestTextPanelsOutsideGroups(t *testing.T) {
+func TestAccDashboardAbsoluteTimeRange(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	resourceName := "lightstep_dashboard.test_text_panels"
+	resourceName := "lightstep_dashboard.test_absolute_time_range"
 
-	makeTextPanelTestConfig := func() string {
-		return `
-resource "lightstep_dashboard" "test_text_panels" {
-	project_name   = "` + testProject + `"
-	dashboard_name   = "test_text_panels"
-	
-	text_panel {
-		name = "Do panic 😅"
-		text = "# Hello **world**...?"
-	}
+	configTemplate := `
+resource "lightstep_dashboard" "test_absolute_time_range" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Absolute Time Range"
+  %s
+
+  absolute_time_range {
+    start = "%s"
+    end   = "%s"
+  }
+
+  chart {
+    name = "Requests"
+    rank = 0
+    type = "timeseries"
+
+    query {
+      query_name   = "a"
+      display      = "line"
+      hidden       = false
+      query_string = "metric requests | rate | group_by[], sum"
+    }
+  }
 }
 `
-	}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -1314,50 +2257,53 @@ resource "lightstep_dashboard" "test_text_panels" {
 		CheckDestroy: testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				// Check an invalid text_panel property
-				Config: makeTextPanelTestConfig(),
+				Config:      fmt.Sprintf(configTemplate, `time_range = "1h"`, "2023-06-01T00:00:00Z", "2023-06-02T00:00:00Z"),
+				ExpectError: regexp.MustCompile(`"time_range": conflicts with absolute_time_range`),
+			},
+			{
+				Config:      fmt.Sprintf(configTemplate, "", "2023-06-01", "2023-06-02T00:00:00Z"),
+				ExpectError: regexp.MustCompile(`expected "absolute_time_range.0.start" to be a valid RFC3339 date`),
+			},
+			{
+				Config:      fmt.Sprintf(configTemplate, "", "2023-06-02T00:00:00Z", "2023-06-01T00:00:00Z"),
+				ExpectError: regexp.MustCompile(`absolute_time_range: end 2023-06-01T00:00:00Z must be after start 2023-06-02T00:00:00Z`),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "", "2023-06-01T02:00:00+02:00", "2023-06-02T00:00:00Z"),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "absolute_time_range.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "absolute_time_range.0.end", "2023-06-02T00:00:00Z"),
 				),
-				ExpectError: regexp.MustCompile("Unsupported block type"),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
 			},
 		},
 	})
 }
 
-func TestSubtitle(t *testing.T) {
+func TestAccDashboardLinkedDashboards(t *testing.T) {
 	var dashboard client.UnifiedDashboard
 
-	resourceName := "lightstep_dashboard.test_subtitle"
+	resourceName := "lightstep_dashboard.test_links"
 
 	configTemplate := `
-resource "lightstep_dashboard" "test_subtitle" {
-project_name   = "` + testProject + `"
-dashboard_name = "test display_type_options"
+resource "lightstep_dashboard" "test_linked" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Linked Dashboard"
+}
 
-group {
-	rank            = 0
-	title           = ""
-	visibility_type = "implicit"
-	
-	chart {
-		name   = "overall cpu utilization"
-		type   = "timeseries"
-		rank   = 0
-		x_pos  = 4
-		y_pos  = 0
-		width  = 4
-		height = 4
-		%s
-	
-		query {
-		  query_name   = "a"
-		  display      = "big_number"
-		  hidden       = false
-		  query_string = "metric cpu.utilization | delta | group_by[], sum"
-		}
-	  }
-	}
+resource "lightstep_dashboard" "test_links" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard with Links"
+
+  linked_dashboard {
+    %s
+  }
 }
 `
 
@@ -1367,33 +2313,93 @@ group {
 		CheckDestroy: testGetMetricDashboardDestroy,
 		Steps: []resource.TestStep{
 			{
-				// empty subtitle
-				Config: fmt.Sprintf(configTemplate, `subtitle = ""`),
+				Config:      fmt.Sprintf(configTemplate, `dashboard_id = "does-not-exist"`),
+				ExpectError: regexp.MustCompile(`dashboard "does-not-exist" does not exist in project`),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, `dashboard_id = lightstep_dashboard.test_linked.id`),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.subtitle", ""),
+					resource.TestCheckResourceAttr(resourceName, "linked_dashboard.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "linked_dashboard.0.dashboard_id", "lightstep_dashboard.test_linked", "id"),
 				),
 			},
 			{
-				// subtitle too long, this should be a chart-level description or something
-				Config: fmt.Sprintf(configTemplate, `subtitle = "this number represents the percentage of CPU cycles available to us that we have actually used"`),
+				Config: fmt.Sprintf(configTemplate, `dashboard_name = lightstep_dashboard.test_linked.dashboard_name`),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "linked_dashboard.0.dashboard_name", "Acceptance Test Linked Dashboard"),
+					resource.TestCheckResourceAttr(resourceName, "linked_dashboard.0.dashboard_id", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboardChartCount(t *testing.T) {
+	var dashboard client.UnifiedDashboard
+
+	resourceName := "lightstep_dashboard.test_chart_count"
+
+	chart := func(name string, rank int) string {
+		return fmt.Sprintf(`
+    chart {
+      name = "%v"
+      rank = %d
+      type = "timeseries"
+
+      query {
+        query_name   = "a"
+        display      = "line"
+        hidden       = false
+        query_string = "metric cpu.utilization | delta | group_by[], sum"
+      }
+    }
+`, name, rank)
+	}
+
+	chartsConfig := `
+resource "lightstep_dashboard" "test_chart_count" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard Chart Count"
+` + chart("CPU", 0) + chart("CPU again", 1) + `
+}
+`
+
+	groupConfig := `
+resource "lightstep_dashboard" "test_chart_count" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Dashboard Chart Count"
+
+  group {
+    rank            = 0
+    title           = "Resources"
+    visibility_type = "explicit"
+` + chart("CPU", 0) + `
+    text_panel {
+      text = "Text panels aren't counted"
+    }
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testGetMetricDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: chartsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "chart_count", "2"),
 				),
-				ExpectError: regexp.MustCompile("expected length of group.0.chart.0.subtitle to be in the range \\(0 - 37\\).*"),
 			},
 			{
-				// normal subtitle
-				Config: fmt.Sprintf(configTemplate, `subtitle = "percent"`),
+				Config: groupConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMetricDashboardExists(resourceName, &dashboard),
-					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "group.0.chart.0.subtitle", "percent"),
+					resource.TestCheckResourceAttr(resourceName, "chart_count", "1"),
 				),
 			},
 		},
@@ -11,6 +11,8 @@ import (
 	"github.com/lightstep/terraform-provider-lightstep/client"
 )
 
+// errorIsNotFound reports whether err is a 404 response of the API. Deletes treat it as success
+// since the resource is already gone, e.g. deleted in the UI.
 func errorIsNotFound(err error) bool {
 	if err == nil {
 		return false
@@ -28,7 +30,7 @@ func errorIsNotFound(err error) bool {
 
 // these are common across all types of destinations
 func resourceDestinationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	dest, err := c.GetDestination(ctx, d.Get("project_name").(string), d.Id())
 	if err != nil {
 		if errorIsNotFound(err) {
@@ -44,11 +46,12 @@ func resourceDestinationRead(ctx context.Context, d *schema.ResourceData, m inte
 func resourceDestinationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := m.(*client.Client)
-	if err := client.DeleteDestination(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	client := m.(*providerMeta).client
+	if err := client.DeleteDestination(ctx, d.Get("project_name").(string), d.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete destination: %v", err))
 	}
 
+	d.SetId("")
 	return diags
 }
 
@@ -88,7 +88,7 @@ func resourceInferredServiceRuleCreate(
 	resourceData *schema.ResourceData,
 	m interface{},
 ) diag.Diagnostics {
-	apiClient := m.(*client.Client)
+	apiClient := m.(*providerMeta).client
 	requestAttributes, err := getInferredServiceRuleAttributesFromResource(resourceData)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get inferred service rule request attributes: %v", err))
@@ -114,7 +114,7 @@ func resourceInferredServiceRuleRead(
 ) diag.Diagnostics {
 	var diagnostics diag.Diagnostics
 
-	apiClient := m.(*client.Client)
+	apiClient := m.(*providerMeta).client
 
 	projectName := getProjectNameFromResource(resourceData)
 	inferredServiceRuleResponse, err := apiClient.GetInferredServiceRule(ctx, projectName, resourceData.Id())
@@ -139,7 +139,7 @@ func resourceInferredServiceRuleUpdate(
 	resourceData *schema.ResourceData,
 	m interface{},
 ) diag.Diagnostics {
-	apiClient := m.(*client.Client)
+	apiClient := m.(*providerMeta).client
 	requestAttributes, err := getInferredServiceRuleAttributesFromResource(resourceData)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get inferred service rule attributes from resource : %v", err))
@@ -160,8 +160,8 @@ func resourceInferredServiceRuleDelete(
 ) diag.Diagnostics {
 	var diagnostics diag.Diagnostics
 
-	apiClient := m.(*client.Client)
-	if err := apiClient.DeleteInferredServiceRule(ctx, getProjectNameFromResource(resourceData), resourceData.Id()); err != nil {
+	apiClient := m.(*providerMeta).client
+	if err := apiClient.DeleteInferredServiceRule(ctx, getProjectNameFromResource(resourceData), resourceData.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete inferred service rule: %v", err))
 	}
 
@@ -179,7 +179,7 @@ func resourceInferredServiceRuleImport(
 	resourceData *schema.ResourceData,
 	m interface{},
 ) ([]*schema.ResourceData, error) {
-	apiClient := m.(*client.Client)
+	apiClient := m.(*providerMeta).client
 
 	ids := strings.Split(resourceData.Id(), ".")
 	if len(ids) != 2 {
@@ -6,8 +6,6 @@ import (
 	"math/rand"
 	"testing"
 
-	"github.com/lightstep/terraform-provider-lightstep/client"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -184,7 +182,7 @@ func testAccCheckInferredServiceRuleExists(resourceName string) resource.TestChe
 			return fmt.Errorf("id is not set")
 		}
 
-		apiClient := testAccProvider.Meta().(*client.Client)
+		apiClient := testAccProvider.Meta().(*providerMeta).client
 		_, err := apiClient.GetInferredServiceRule(context.Background(), testProject, tfResource.Primary.ID)
 		if err != nil {
 			return err
@@ -195,7 +193,7 @@ func testAccCheckInferredServiceRuleExists(resourceName string) resource.TestChe
 }
 
 func testAccInferredServiceRuleDestroy(tfState *terraform.State) error {
-	apiClient := testAccProvider.Meta().(*client.Client)
+	apiClient := testAccProvider.Meta().(*providerMeta).client
 	for _, tfResource := range tfState.RootModule().Resources {
 		if tfResource.Type != "lightstep_inferred_service_rule" {
 			continue
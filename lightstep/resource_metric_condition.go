@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-cty/cty"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
@@ -88,7 +89,39 @@ func resourceUnifiedCondition(conditionSchemaType ConditionSchemaType) *schema.R
 	}
 
 	if conditionSchemaType == UnifiedConditionSchema {
+		resource.CustomizeDiff = customdiff.All(
+			warnQueryComplexity("query", "composite_alert"),
+			validateRenotifyUpdateInterval,
+			validateNoData,
+		)
 		resource.Schema["expression"] = getUnifiedAlertExpressionSchema()
+		resource.Schema["evaluation_window"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validatePositiveDuration,
+			DiffSuppressFunc: suppressEquivalentDuration,
+			Description:      "Optional window the alert's query is evaluated over as a duration, e.g. `5m` to alert on the metric averaged over five minutes.",
+		}
+		resource.Schema["severity"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"critical", "warning", "info"}, false),
+			Description:  "Optional severity of the alert, used to prioritize its notifications. One of `critical`, `warning` or `info`.",
+		}
+		resource.Schema["no_data"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"alert", "no_alert", "keep_previous"}, false),
+			Description:  "Optional behavior of the alert when its queries return no data: `alert` to trigger it, `no_alert` to resolve it or `keep_previous` to keep its current state. Must be `alert` if the expression sets `is_no_data`.",
+		}
+		resource.Schema["renotify"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateFunc:     validateRenotify,
+			DiffSuppressFunc: suppressEquivalentRenotify,
+			Description:      "Optional interval at which notifications are re-sent while the alert stays triggered, as a duration such as `1h` or `1d`, or `never`. Left unset, the interval set outside of Terraform is kept. Conflicts with the `update_interval` of the alerting rules, which re-send the notifications of a single destination.",
+		}
 		resource.Schema["query"] = &schema.Schema{
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -257,7 +290,7 @@ func getMetricQuerySchemaMap() map[string]*schema.Schema {
 		"filters": {
 			Type:        schema.TypeList,
 			Elem:        &schema.Schema{Type: schema.TypeMap},
-			Description: "Non-equality filters (operand: contains, regexp)",
+			Description: "Non-equality filters (operand: contains, regexp, exists, not_exists). The exists and not_exists filters match on whether the label is set, they have no value.",
 			Optional:    true,
 			Computed:    true,
 		},
@@ -423,11 +456,12 @@ func getCompositeSubAlertExpressionResource() *schema.Resource {
 				Default:     false,
 				Description: "If true, a notification is sent when the alert query returns no data. If false, notifications aren't sent in this scenario.",
 			},
+			// checked against the operators supported by the API when applying, see
+			// validateAlertCapabilities
 			"operand": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice([]string{"", "above", "below"}, false),
-				Description:  "Required when at least one threshold (Critical, Warning) is defined. Indicates whether the alert triggers when the value is above the threshold or below the threshold.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Required when at least one threshold (Critical, Warning) is defined. Indicates whether the alert triggers when the value is above the threshold or below the threshold.",
 			},
 			"thresholds": {
 				Type:     schema.TypeList,
@@ -479,12 +513,19 @@ type resourceUnifiedConditionImp struct {
 }
 
 func (p *resourceUnifiedConditionImp) resourceUnifiedConditionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
+	if err := validateAlertCapabilities(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
 	attributes, err := getUnifiedConditionAttributesFromResource(d, p.conditionSchemaType)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get metric condition attributes from resource : %v", err))
 	}
 
+	if err := validateAlertingRuleDestinations(ctx, c, d.Get("project_name").(string), attributes.AlertingRules); err != nil {
+		return diag.FromErr(err)
+	}
+
 	condition := client.UnifiedCondition{
 		Type:       "metric_alert",
 		Attributes: *attributes,
@@ -519,7 +560,7 @@ func (p *resourceUnifiedConditionImp) resourceUnifiedConditionCreate(ctx context
 func (p *resourceUnifiedConditionImp) resourceUnifiedConditionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	prevAttrs, err := getUnifiedConditionAttributesFromResource(d, p.conditionSchemaType)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to translate resource attributes: %v", err))
@@ -557,12 +598,19 @@ func (p *resourceUnifiedConditionImp) resourceUnifiedConditionRead(ctx context.C
 }
 
 func (p *resourceUnifiedConditionImp) resourceUnifiedConditionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
+	if err := validateAlertCapabilities(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
 	attrs, err := getUnifiedConditionAttributesFromResource(d, p.conditionSchemaType)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get metric condition attributes from resource : %v", err))
 	}
 
+	if err := validateAlertingRuleDestinations(ctx, c, d.Get("project_name").(string), attrs.AlertingRules); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if _, err := c.UpdateUnifiedCondition(ctx, d.Get("project_name").(string), d.Id(), *attrs); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to update metric condition: %v", err))
 	}
@@ -573,8 +621,8 @@ func (p *resourceUnifiedConditionImp) resourceUnifiedConditionUpdate(ctx context
 func (p *resourceUnifiedConditionImp) resourceUnifiedConditionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
-	if err := c.DeleteUnifiedCondition(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	c := m.(*providerMeta).client
+	if err := c.DeleteUnifiedCondition(ctx, d.Get("project_name").(string), d.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete metrics condition: %v", err))
 	}
 
@@ -585,7 +633,7 @@ func (p *resourceUnifiedConditionImp) resourceUnifiedConditionDelete(ctx context
 }
 
 func (p *resourceUnifiedConditionImp) resourceUnifiedConditionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	clnt := m.(*client.Client)
+	clnt := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
@@ -648,7 +696,7 @@ func getUnifiedConditionAttributesFromResource(d *schema.ResourceData, schemaTyp
 		return nil, err
 	}
 
-	return &client.UnifiedConditionAttributes{
+	attributes := &client.UnifiedConditionAttributes{
 		Type:           "metrics",
 		Name:           d.Get("name").(string),
 		Description:    d.Get("description").(string),
@@ -658,7 +706,22 @@ func getUnifiedConditionAttributesFromResource(d *schema.ResourceData, schemaTyp
 		AlertingRules:  alertingRules,
 		Queries:        queries,
 		CompositeAlert: compositeAlert,
-	}, nil
+	}
+	if schemaType == UnifiedConditionSchema {
+		attributes.EvaluationWindow = d.Get("evaluation_window").(string)
+		attributes.Severity = d.Get("severity").(string)
+		attributes.NoDataBehavior = d.Get("no_data").(string)
+
+		// 0 is sent explicitly for "never" so that disabling renotification clears it
+		if renotify := d.Get("renotify").(string); renotify != "" {
+			ms, err := renotifyMillis(renotify)
+			if err != nil {
+				return nil, fmt.Errorf("invalid renotify: %v", err)
+			}
+			attributes.RenotifyIntervalMS = &ms
+		}
+	}
+	return attributes, nil
 }
 
 func buildExpression(singleExpression map[string]interface{}) (*client.Expression, error) {
@@ -705,7 +768,7 @@ func buildAlertingRules(alertingRulesIn *schema.Set) ([]client.AlertingRule, err
 			MessageDestinationID: rule["id"].(string),
 		}
 
-		updateIntervalMilli, ok := validUpdateInterval[rule["update_interval"].(string)]
+		updateIntervalMilli, ok := updateIntervalMillis(rule["update_interval"].(string))
 		if ok {
 			newRule.UpdateInterval = updateIntervalMilli
 		}
@@ -728,6 +791,24 @@ func buildAlertingRules(alertingRulesIn *schema.Set) ([]client.AlertingRule, err
 	return newRules, nil
 }
 
+// validateAlertingRuleDestinations checks that every destination referenced by an alerting rule
+// exists, so a stale or mistyped destination ID is reported clearly before the alert is saved.
+func validateAlertingRuleDestinations(ctx context.Context, c *client.Client, projectName string, rules []client.AlertingRule) error {
+	for _, rule := range rules {
+		_, err := c.GetDestination(ctx, projectName, rule.MessageDestinationID)
+		if err == nil {
+			continue
+		}
+
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if ok && apiErr.GetStatusCode() == http.StatusNotFound {
+			return fmt.Errorf("alerting_rule references destination %v which does not exist in project %v", rule.MessageDestinationID, projectName)
+		}
+		return fmt.Errorf("failed to get destination %v referenced by alerting_rule: %v", rule.MessageDestinationID, err)
+	}
+	return nil
+}
+
 func buildSpansGroupByKeys(keysIn []interface{}) []string {
 	var keys []string
 	for _, k := range keysIn {
@@ -786,6 +867,10 @@ func buildQueries(queriesIn []interface{}) ([]client.MetricQueryWithAttributes,
 
 	hasSpanSingle := false
 	for _, query := range queries {
+		// "time_shift", "alias" and "baseline" are only part of the dashboard query schema
+		timeShift, _ := query["time_shift"].(string)
+		alias, _ := query["alias"].(string)
+		baseline := buildBaseline(query["baseline"])
 
 		// When checking if this chart uses a query string, check deprecated TQL field as well
 		queryString, ok := query["query_string"].(string)
@@ -801,6 +886,9 @@ func buildQueries(queriesIn []interface{}) ([]client.MetricQueryWithAttributes,
 				Display:              query["display"].(string),
 				TQLQuery:             queryString,
 				DependencyMapOptions: buildDependencyMapOptions(query["dependency_map_options"]),
+				TimeShift:            timeShift,
+				DisplayLabel:         alias,
+				Baseline:             baseline,
 			}
 
 			// Check for the optional JSON block of display options
@@ -852,11 +940,14 @@ func buildQueries(queriesIn []interface{}) ([]client.MetricQueryWithAttributes,
 			}
 			display := query["display"].(string)
 			newQuery := client.MetricQueryWithAttributes{
-				Name:       query["query_name"].(string),
-				Type:       "spans_single",
-				Hidden:     query["hidden"].(bool),
-				Display:    display,
-				SpansQuery: buildSpansQuery(spansQuery, display, buildFinalWindowOperation(query["final_window_operation"])),
+				Name:         query["query_name"].(string),
+				Type:         "spans_single",
+				Hidden:       query["hidden"].(bool),
+				Display:      display,
+				SpansQuery:   buildSpansQuery(spansQuery, display, buildFinalWindowOperation(query["final_window_operation"])),
+				TimeShift:    timeShift,
+				DisplayLabel: alias,
+				Baseline:     baseline,
 			}
 			newQueries = append(newQueries, newQuery)
 			continue
@@ -877,6 +968,9 @@ func buildQueries(queriesIn []interface{}) ([]client.MetricQueryWithAttributes,
 				TimeseriesOperator: query["timeseries_operator"].(string),
 				Metric:             metric,
 			},
+			TimeShift:    timeShift,
+			DisplayLabel: alias,
+			Baseline:     baseline,
 		}
 
 		timeseriesOperatorInputWindowMs := query["timeseries_operator_input_window_ms"]
@@ -962,6 +1056,18 @@ func buildDependencyMapOptions(in interface{}) *client.DependencyMapOptions {
 	}
 }
 
+func buildBaseline(in interface{}) *client.Baseline {
+	if in == nil || len(in.([]interface{})) == 0 {
+		return nil
+	}
+
+	baseline := in.([]interface{})[0].(map[string]interface{})
+	return &client.Baseline{
+		Lookback:    baseline["lookback"].(string),
+		Sensitivity: baseline["sensitivity"].(string),
+	}
+}
+
 func buildFinalWindowOperation(in interface{}) *client.FinalWindowOperation {
 	if in == nil || len(in.([]interface{})) == 0 {
 		return nil
@@ -1049,12 +1155,13 @@ func buildLabelFilters(includes []interface{}, excludes []interface{}, all []int
 	if len(all) > 0 {
 		for _, allFilter := range all {
 			key := allFilter.(map[string]interface{})["key"]
-			value := allFilter.(map[string]interface{})["value"]
+			// exists and not_exists filters have no value
+			value, _ := allFilter.(map[string]interface{})["value"].(string)
 			operand := allFilter.(map[string]interface{})["operand"]
 			filters = append(filters, client.LabelFilter{
 				Operand: operand.(string),
 				Key:     key.(string),
-				Value:   value.(string),
+				Value:   value,
 			})
 		}
 	}
@@ -1126,8 +1233,11 @@ func validateSpansQuery(spansQuery interface{}) error {
 		return fmt.Errorf("missing required field operator on spans")
 	}
 
-	switch query.(type) {
+	switch q := query.(type) {
 	case string:
+		if err := validateStreamQuery(q); err != nil {
+			return fmt.Errorf("invalid spans query: %v", err)
+		}
 	default:
 		return fmt.Errorf("value must be a string. got: %v", query)
 	}
@@ -1147,10 +1257,7 @@ func validateFilters(filters []interface{}, hasOperand bool) error {
 			return fmt.Errorf("'key' is a required field")
 		}
 
-		value, ok := filter.(map[string]interface{})["value"]
-		if !ok {
-			return fmt.Errorf("'value' is a required field")
-		}
+		value, hasValue := filter.(map[string]interface{})["value"]
 
 		if hasOperand {
 			op, ok := filter.(map[string]interface{})["operand"]
@@ -1166,6 +1273,17 @@ func validateFilters(filters []interface{}, hasOperand bool) error {
 			if op.(string) == "eq" || op.(string) == "neq" {
 				return fmt.Errorf("filters object does not support operand %s: use include_filters or exclude_filters instead", op)
 			}
+			if op.(string) == client.LabelFilterExists || op.(string) == client.LabelFilterNotExists {
+				if hasValue && value != "" {
+					return fmt.Errorf("filters with operand %s match on whether %v is set and can't have a value", op, key)
+				}
+				// the value isn't checked below
+				value, hasValue = "", true
+			}
+		}
+
+		if !hasValue {
+			return fmt.Errorf("'value' is a required field")
 		}
 
 		switch key.(type) {
@@ -1261,6 +1379,28 @@ func setResourceDataFromUnifiedCondition(project string, c client.UnifiedConditi
 			return fmt.Errorf("unable to set query resource field: %v", err)
 		}
 
+		if err := d.Set("evaluation_window", c.Attributes.EvaluationWindow); err != nil {
+			return fmt.Errorf("unable to set evaluation_window resource field: %v", err)
+		}
+
+		if err := d.Set("severity", c.Attributes.Severity); err != nil {
+			return fmt.Errorf("unable to set severity resource field: %v", err)
+		}
+
+		if err := d.Set("no_data", c.Attributes.NoDataBehavior); err != nil {
+			return fmt.Errorf("unable to set no_data resource field: %v", err)
+		}
+
+		if ms := c.Attributes.RenotifyIntervalMS; ms != nil {
+			renotify := renotifyNever
+			if *ms > 0 {
+				renotify = formatMillis(*ms)
+			}
+			if err := d.Set("renotify", renotify); err != nil {
+				return fmt.Errorf("unable to set renotify resource field: %v", err)
+			}
+		}
+
 		if c.Attributes.CompositeAlert != nil {
 			compositeAlert, err := getCompositeAlertFromUnifiedConditionResourceData(c.Attributes.CompositeAlert)
 			if err != nil {
@@ -1338,6 +1478,12 @@ func getIncludeExcludeFilters(filters []client.LabelFilter) ([]interface{}, []in
 				"key":   f.Key,
 				"value": f.Value,
 			})
+		} else if f.Operand == client.LabelFilterExists || f.Operand == client.LabelFilterNotExists {
+			// the filter has no value in the configuration either
+			allFilters = append(allFilters, map[string]interface{}{
+				"key":     f.Key,
+				"operand": f.Operand,
+			})
 		} else {
 			allFilters = append(allFilters, map[string]interface{}{
 				"key":     f.Key,
@@ -1424,3 +1570,68 @@ func getFinalWindowOperationFromResourceData(finalWindowOperation *client.FinalW
 		},
 	}
 }
+
+// renotifyNever disables the re-sending of notifications while an alert stays triggered
+const renotifyNever = "never"
+
+// renotifyMillis converts a renotify attribute to the milliseconds sent to the API, 0 for never
+func renotifyMillis(renotify string) (int, error) {
+	if renotify == renotifyNever {
+		return 0, nil
+	}
+	ms, err := durationMillis(renotify)
+	if err != nil || ms <= 0 {
+		return 0, fmt.Errorf("expected \"never\" or a positive duration such as \"1h\", got %q", renotify)
+	}
+	return ms, nil
+}
+
+func validateRenotify(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := renotifyMillis(v); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %v", k, err)}
+	}
+	return nil, nil
+}
+
+// validateNoData is a CustomizeDiff function that checks that no_data doesn't contradict the
+// is_no_data of the expression, which triggers the alert when there's no data
+func validateNoData(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	noData, _ := d.Get("no_data").(string)
+	isNoData, _ := d.Get("expression.0.is_no_data").(bool)
+	if isNoData && noData != "" && noData != "alert" {
+		return fmt.Errorf("no_data = %q conflicts with expression.is_no_data, which alerts when there's no data, set no_data to \"alert\" or leave it unset", noData)
+	}
+	return nil
+}
+
+// validateRenotifyUpdateInterval is a CustomizeDiff function that checks that renotify and the
+// update_interval of the alerting rules aren't both set, since both re-send the notifications
+// of a triggered alert
+func validateRenotifyUpdateInterval(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	renotify, _ := d.Get("renotify").(string)
+	if renotify == "" || renotify == renotifyNever {
+		return nil
+	}
+	rules, _ := d.Get("alerting_rule").(*schema.Set)
+	if rules == nil {
+		return nil
+	}
+	for _, r := range rules.List() {
+		if interval, _ := r.(map[string]interface{})["update_interval"].(string); interval != "" {
+			return fmt.Errorf("renotify conflicts with the update_interval of alerting_rule %v, set only one of them", r.(map[string]interface{})["id"])
+		}
+	}
+	return nil
+}
+
+// suppressEquivalentRenotify ignores differences between equal renotify intervals written
+// differently, e.g. 60m and 1h
+func suppressEquivalentRenotify(_, old, new string, _ *schema.ResourceData) bool {
+	oldMillis, oldErr := renotifyMillis(old)
+	newMillis, newErr := renotifyMillis(new)
+	return oldErr == nil && newErr == nil && oldMillis == newMillis
+}
@@ -369,6 +369,70 @@ resource "lightstep_metric_condition" "test" {
 	})
 }
 
+func TestAccMetricConditionExistsFilter(t *testing.T) {
+	var condition client.UnifiedCondition
+
+	conditionConfig := func(filter string) string {
+		return `
+resource "lightstep_metric_condition" "test_exists" {
+  project_name = "` + testProject + `"
+  name = "Requests of canaries"
+
+  expression {
+    is_multi = false
+    operand  = "above"
+    thresholds {
+      critical = 10
+    }
+  }
+
+  metric_query {
+    metric              = "requests"
+    query_name          = "a"
+    timeseries_operator = "rate"
+    hidden              = false
+    display             = "line"
+    filters = [` + filter + `]
+
+    group_by {
+      aggregation_method = "sum"
+      keys = []
+    }
+  }
+}
+`
+	}
+
+	resourceName := "lightstep_metric_condition.test_exists"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetricConditionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      conditionConfig(`{ key = "canary", operand = "exists", value = "true" }`),
+				ExpectError: regexp.MustCompile("filters with operand exists match on whether canary is set and can't have a value"),
+			},
+			{
+				Config: conditionConfig(`{ key = "canary", operand = "exists" }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMetricConditionExists(resourceName, &condition),
+					resource.TestCheckResourceAttr(resourceName, "metric_query.0.filters.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metric_query.0.filters.0.key", "canary"),
+					resource.TestCheckResourceAttr(resourceName, "metric_query.0.filters.0.operand", "exists"),
+					resource.TestCheckNoResourceAttr(resourceName, "metric_query.0.filters.0.value"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
+
 func TestAccSpanLatencyCondition(t *testing.T) {
 	var condition client.UnifiedCondition
 
@@ -932,7 +996,7 @@ func testAccCheckMetricConditionExists(resourceName string, condition *client.Un
 			return fmt.Errorf("id is not set")
 		}
 
-		providerClient := testAccProvider.Meta().(*client.Client)
+		providerClient := testAccProvider.Meta().(*providerMeta).client
 		cond, err := providerClient.GetUnifiedCondition(context.Background(), testProject, tfCondition.Primary.ID)
 		if err != nil {
 			return err
@@ -944,7 +1008,7 @@ func testAccCheckMetricConditionExists(resourceName string, condition *client.Un
 }
 
 func testAccMetricConditionDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "metric_alert" {
 			continue
@@ -1028,6 +1092,20 @@ func TestBuildLabelFilters(t *testing.T) {
 				allFilter,
 			},
 		},
+		// exists filters have no value
+		{
+			all: []interface{}{
+				map[string]interface{}{
+					"key":     k,
+					"operand": "exists",
+				},
+			},
+			includes: []interface{}{},
+			excludes: []interface{}{},
+			expected: []client.LabelFilter{
+				{Key: k, Operand: "exists"},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -1185,6 +1263,45 @@ func TestValidateFilters(t *testing.T) {
 			expectErr:  true,
 			hasOperand: true,
 		},
+		// exists and not_exists filters don't need a value
+		{
+			filters: []interface{}{
+				map[string]interface{}{
+					"key":     "some-key",
+					"operand": "exists",
+				},
+				map[string]interface{}{
+					"key":     "some-key",
+					"value":   "",
+					"operand": "not_exists",
+				},
+			},
+			expectErr:  false,
+			hasOperand: true,
+		},
+		// exists filters can't have a value
+		{
+			filters: []interface{}{
+				map[string]interface{}{
+					"key":     "some-key",
+					"value":   "some-val",
+					"operand": "exists",
+				},
+			},
+			expectErr:  true,
+			hasOperand: true,
+		},
+		// other operands need a value
+		{
+			filters: []interface{}{
+				map[string]interface{}{
+					"key":     "some-key",
+					"operand": "contains",
+				},
+			},
+			expectErr:  true,
+			hasOperand: true,
+		},
 		// operand value is eq
 		{
 			filters: []interface{}{
@@ -1310,6 +1427,24 @@ func TestValidateGroupBy(t *testing.T) {
 	}
 }
 
+func TestValidateSpansQuery(t *testing.T) {
+	spans := func(query string) interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"query":    query,
+				"operator": "latency",
+			},
+		}
+	}
+
+	require.NoError(t, validateSpansQuery(spans(`"service" IN ("checkout")`)))
+	for _, query := range []string{"", `"service" IN ("checkout"`, `"service" IN ("checkout)`} {
+		err := validateSpansQuery(spans(query))
+		require.Error(t, err, query)
+		require.Contains(t, err.Error(), "invalid spans query")
+	}
+}
+
 func Test_buildLatencyPercentiles(t *testing.T) {
 	type args struct {
 		lats    []interface{}
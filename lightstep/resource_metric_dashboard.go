@@ -2,13 +2,18 @@ package lightstep
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -30,6 +35,21 @@ const (
 func resourceUnifiedDashboard(chartSchemaType ChartSchemaType) *schema.Resource {
 	p := resourceUnifiedDashboardImp{chartSchemaType: chartSchemaType}
 
+	customizeDiff := []schema.CustomizeDiffFunc{
+		applyDefaultDashboardTimeRange,
+		validateAbsoluteTimeRange,
+		validateBigNumberChartOptions,
+		validateTemplateVariablePresets,
+		validateLinkedDashboards,
+		checkDashboardChartLimit,
+		validateDashboardReferences,
+		planChartCount,
+	}
+	// Only the unified dashboard has query strings whose complexity can be estimated
+	if chartSchemaType == UnifiedChartSchema {
+		customizeDiff = append(customizeDiff, warnQueryComplexity("chart", "group"))
+	}
+
 	return &schema.Resource{
 		CreateContext: p.resourceUnifiedDashboardCreate,
 		ReadContext:   p.resourceUnifiedDashboardRead,
@@ -38,6 +58,7 @@ func resourceUnifiedDashboard(chartSchemaType ChartSchemaType) *schema.Resource
 		Importer: &schema.ResourceImporter{
 			StateContext: p.resourceUnifiedDashboardImport,
 		},
+		CustomizeDiff: customdiff.All(customizeDiff...),
 		Schema: map[string]*schema.Schema{
 			"project_name": {
 				Type:     schema.TypeString,
@@ -51,10 +72,82 @@ func resourceUnifiedDashboard(chartSchemaType ChartSchemaType) *schema.Resource
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"time_range": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validatePositiveDuration,
+				DiffSuppressFunc: suppressEquivalentDuration,
+				ConflictsWith:    []string{"absolute_time_range"},
+				Description:      "Default time range of the dashboard as a duration, e.g. 1h. Defaults to the provider's default_dashboard_time_range when it is set.",
+			},
+			"absolute_time_range": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"time_range"},
+				Description:   "Fixed time window of the dashboard, instead of the relative `time_range`",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validation.IsRFC3339Time,
+							DiffSuppressFunc: suppressEquivalentTimestamp,
+							Description:      "Start of the window as an RFC3339 timestamp, e.g. 2023-06-01T00:00:00Z",
+						},
+						"end": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validation.IsRFC3339Time,
+							DiffSuppressFunc: suppressEquivalentTimestamp,
+							Description:      "End of the window as an RFC3339 timestamp, which must be after the start",
+						},
+					},
+				},
+			},
+			"ignore_server_changes": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ignorableServerChanges, false),
+				},
+				Description: "Server-managed fields whose changes on the server are ignored when the dashboard is read, so they don't show up as drift. " +
+					"Supported values: `dashboard_description`, `label`, `template_variable`, `group_rank`, `chart_rank` and `chart_position` (`x_pos`, `y_pos`, `width` and `height` of charts).",
+			},
+			"is_default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "When true, the dashboard is the default (home) dashboard of the project. Only one dashboard per project can be the default. When not set, the dashboard stays the default if it was made so outside of Terraform.",
+			},
+			"protected": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "When true, the dashboard is locked by Lightstep and cannot be deleted, including by Terraform, until it is unprotected. When not set, the dashboard keeps the protection it was given outside of Terraform.",
+			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the dashboard even if it is protected, unlocking it first. By default deleting a protected dashboard fails.",
+			},
 			"type": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the dashboard when it was last read. Updates are rejected if the dashboard has since been modified outside of Terraform.",
+			},
+			"chart_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of charts of the dashboard, including the charts of its groups but not text panels. Read-only, it's known after apply when the charts change.",
+			},
 			"chart": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -94,6 +187,66 @@ func resourceUnifiedDashboard(chartSchemaType ChartSchemaType) *schema.Resource
 				},
 				Description: "Variable to be used in dashboard queries for dynamically filtering telemetry data",
 			},
+			"preset": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getTemplateVariablePresetSchema(),
+				},
+				Description: "Named combination of template variable values that users can switch between in the Lightstep UI",
+			},
+			"event_overlay": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getEventOverlaySchema(),
+				},
+				Description: "Events, e.g. deploys, marked on the timelines of the dashboard's charts",
+			},
+			"linked_dashboard": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dashboard_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the linked dashboard. Exactly one of `dashboard_id` and `dashboard_name` must be set.",
+						},
+						"dashboard_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the linked dashboard, which must be the only dashboard of the project with that name",
+						},
+					},
+				},
+				Description: "Related dashboard of the project linked to for navigation. The linked dashboard must exist when the dashboard is created or updated.",
+			},
+			"default_group_by": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Grouping applied to every chart in the dashboard. Charts whose queries specify their own grouping keep it.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "Attribute keys to group by",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"aggregation_method": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "How the grouped series are aggregated, must be one of: sum, avg, max, min, count, count_non_zero.",
+							ValidateFunc: validation.StringInSlice([]string{"sum", "avg", "max", "min", "count", "count_non_zero"}, true),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -172,9 +325,10 @@ func getPanelSchema(isNameRequired bool) map[string]*schema.Schema {
 		// Alias for what we refer to as title elsewhere
 		"name": nameSchema(),
 		"description": {
-			Type:     schema.TypeString,
-			Optional: true,
-			Default:  "",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Longer description shown with the panel, in addition to its name",
 		},
 		"x_pos": {
 			Type:         schema.TypeInt,
@@ -233,14 +387,48 @@ func getChartSchema(chartSchemaType ChartSchemaType) map[string]*schema.Schema {
 	} else {
 		querySchema = getMetricQuerySchemaMap()
 	}
+	querySchema["time_shift"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "Shifts the query's data back in time by the given duration, e.g. `168h` to show the data from one week earlier.",
+		ValidateFunc: validatePositiveDuration,
+	}
+	querySchema["alias"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Name shown in the chart legend instead of the query_name, e.g. `p99 latency`.",
+	}
+	querySchema["baseline"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Overlays the query with its expected range, computed from the query's own history, to highlight anomalies.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"lookback": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "How much history the baseline is computed from, e.g. `168h` for one week.",
+					ValidateFunc: validatePositiveDuration,
+				},
+				"sensitivity": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "How far outside the baseline a value must be to be considered anomalous, must be one of: low, medium, high.",
+					ValidateFunc: validation.StringInSlice([]string{"low", "medium", "high"}, false),
+				},
+			},
+		},
+	}
 
 	return mergeSchemas(
 		getPanelSchema(true),
 		map[string]*schema.Schema{
+			// checked against the chart types supported by the API when applying, see
+			// validateDashboardCapabilities
 			"type": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice([]string{"timeseries"}, true),
+				Type:     schema.TypeString,
+				Required: true,
 			},
 			"rank": {
 				Type:         schema.TypeInt,
@@ -278,6 +466,29 @@ func getChartSchema(chartSchemaType ChartSchemaType) map[string]*schema.Schema {
 				Optional:     true,
 				ValidateFunc: validation.StringLenBetween(0, 37),
 			},
+			"precision": {
+				Type:         schema.TypeInt,
+				Description:  "Number of decimal places shown by big number charts, chosen by Lightstep when unset. Only valid for charts with a big_number or big_number_v2 query.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"show_spark_line": {
+				Type:        schema.TypeBool,
+				Description: "Show the trend of the value as a spark line under the big number. Only valid for charts with a big_number or big_number_v2 query.",
+				Optional:    true,
+				Default:     false,
+			},
+			"display_unit": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Unit the chart's values are displayed in after applying display_scale, e.g. `MB`",
+			},
+			"display_scale": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "Factor the chart's values are multiplied by for display, e.g. `0.000001` to show a bytes metric as MB",
+				ValidateFunc: validatePositiveFloat,
+			},
 		},
 	)
 }
@@ -305,16 +516,79 @@ func getTemplateVariableSchema() map[string]*schema.Schema {
 	}
 }
 
+func getTemplateVariablePresetSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Name of the preset, shown in the Lightstep UI",
+		},
+		"variable": {
+			Type:     schema.TypeList,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Name of a template variable of the dashboard",
+					},
+					"values": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+						Description: "Values the template variable is set to by the preset",
+					},
+				},
+			},
+			Description: "Template variable set by the preset and the values it is set to",
+		},
+	}
+}
+
+func getEventOverlaySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Name of the overlay, shown in the Lightstep UI",
+		},
+		"source": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"deploys", "events"}, false),
+			Description:  "Where the overlaid events come from, one of `deploys` or `events` (custom events)",
+		},
+		"query": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Query narrowing down the events of the source, e.g. `service = \"checkout\"`. Every event of the source is overlaid if unset.",
+		},
+	}
+}
+
 type resourceUnifiedDashboardImp struct {
 	chartSchemaType ChartSchemaType
 }
 
 func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
+	if err := validateDashboardCapabilities(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
 	attrs, hasLegacyChartsIn, err := getUnifiedDashboardAttributesFromResource(d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get dashboard attributes: %v", err))
 	}
+	attrs.LinkedDashboards, err = resolveLinkedDashboards(ctx, c, d.Get("project_name").(string), d.Get("linked_dashboard").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	dashboard := client.UnifiedDashboard{
 		Type:       "dashboard",
@@ -322,12 +596,21 @@ func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardCreate(ctx context
 	}
 
 	created, err := c.CreateUnifiedDashboard(ctx, d.Get("project_name").(string), dashboard)
+	if diags := requestValidationDiags(err); diags != nil {
+		return diags
+	}
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to create dashboard: %v", err))
 	}
 
 	d.SetId(created.ID)
 
+	if d.Get("is_default").(bool) {
+		if err := setDefaultDashboard(ctx, c, d.Get("project_name").(string), created.ID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	// Support for deprecated legacy queries: if we created a new legacy query and the creation
 	// succeeded, return the ResourceData "as-is" from what was passed in. This avoids meaningless
 	// diffs in the plan.
@@ -345,18 +628,19 @@ func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardCreate(ctx context
 				}
 			}
 		}
+		dashboard.Attributes.IsDefault = d.Get("is_default").(bool)
 		if err := p.setResourceDataFromUnifiedDashboard(projectName, dashboard, d, hasLegacyChartsIn); err != nil {
 			return diag.FromErr(fmt.Errorf("failed to set dashboard from API response to terraform state: %v", err))
 		}
-		return nil
+		return dashboardChartLimitWarning(d, m)
 	}
 
-	return p.resourceUnifiedDashboardRead(ctx, d, m)
+	return append(p.resourceUnifiedDashboardRead(ctx, d, m), dashboardChartLimitWarning(d, m)...)
 }
 
 func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	prevAttrs, hasLegacyChartsIn, err := getUnifiedDashboardAttributesFromResource(d)
 	if err != nil {
@@ -431,7 +715,7 @@ func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardRead(ctx context.C
 func getUnifiedDashboardAttributesFromResource(d *schema.ResourceData) (*client.UnifiedDashboardAttributes, bool, error) {
 	chartSet := d.Get("chart").(*schema.Set)
 	groupSet := d.Get("group").(*schema.Set)
-	groups, hasLegacyChartsIn, err := buildGroups(groupSet.List(), chartSet.List())
+	groups, hasLegacyChartsIn, err := buildGroups(groupSet.List(), chartSet.List(), configuredPrecisions(d.GetRawConfig()))
 	if err != nil {
 		return nil, hasLegacyChartsIn, err
 	}
@@ -445,18 +729,29 @@ func getUnifiedDashboardAttributesFromResource(d *schema.ResourceData) (*client.
 	templateVariableSet := d.Get("template_variable").(*schema.Set)
 	templateVariables := buildTemplateVariables(templateVariableSet.List())
 
+	absoluteTimeRange, err := buildAbsoluteTimeRange(d.Get("absolute_time_range").([]interface{}))
+	if err != nil {
+		return nil, hasLegacyChartsIn, err
+	}
+
 	attributes := &client.UnifiedDashboardAttributes{
-		Name:              d.Get("dashboard_name").(string),
-		Description:       d.Get("dashboard_description").(string),
-		Groups:            groups,
-		Labels:            labels,
-		TemplateVariables: templateVariables,
+		Name:                    d.Get("dashboard_name").(string),
+		Description:             d.Get("dashboard_description").(string),
+		Groups:                  groups,
+		Labels:                  labels,
+		TemplateVariables:       templateVariables,
+		TemplateVariablePresets: buildTemplateVariablePresets(d.Get("preset").([]interface{})),
+		EventOverlays:           buildEventOverlays(d.Get("event_overlay").([]interface{})),
+		DefaultGroupBy:          buildDefaultGroupBy(d.Get("default_group_by").([]interface{})),
+		Locked:                  d.Get("protected").(bool),
+		TimeRange:               d.Get("time_range").(string),
+		AbsoluteTimeRange:       absoluteTimeRange,
 	}
 
 	return attributes, hasLegacyChartsIn, nil
 }
 
-func buildGroups(groupsIn []interface{}, legacyChartsIn []interface{}) ([]client.UnifiedGroup, bool, error) {
+func buildGroups(groupsIn []interface{}, legacyChartsIn []interface{}, precisions map[string]int) ([]client.UnifiedGroup, bool, error) {
 	var (
 		newGroups         []client.UnifiedGroup
 		hasLegacyChartsIn bool
@@ -464,7 +759,7 @@ func buildGroups(groupsIn []interface{}, legacyChartsIn []interface{}) ([]client
 
 	if len(legacyChartsIn) != 0 {
 		hasLegacyChartsIn = true
-		c, err := buildCharts(legacyChartsIn)
+		c, err := buildCharts(legacyChartsIn, precisions)
 		if err != nil {
 			return nil, hasLegacyChartsIn, err
 		}
@@ -479,7 +774,7 @@ func buildGroups(groupsIn []interface{}, legacyChartsIn []interface{}) ([]client
 	for i := range groupsIn {
 		group := groupsIn[i].(map[string]interface{})
 
-		chartPanels, err := buildCharts(group["chart"].(*schema.Set).List())
+		chartPanels, err := buildCharts(group["chart"].(*schema.Set).List(), precisions)
 		if err != nil {
 			return nil, hasLegacyChartsIn, err
 		}
@@ -526,7 +821,7 @@ func buildTextPanels(textPanelsIn []interface{}) ([]client.UnifiedChart, error)
 	return newCharts, nil
 }
 
-func buildCharts(chartsIn []interface{}) ([]client.UnifiedChart, error) {
+func buildCharts(chartsIn []interface{}, precisions map[string]int) ([]client.UnifiedChart, error) {
 	var (
 		charts    []map[string]interface{}
 		newCharts []client.UnifiedChart
@@ -565,6 +860,20 @@ func buildCharts(chartsIn []interface{}) ([]client.UnifiedChart, error) {
 			c.Subtitle = &subtitleStr
 		}
 
+		if precision, ok := precisions[chart["name"].(string)]; ok {
+			c.Precision = &precision
+		}
+		c.ShowSparkLine, _ = chart["show_spark_line"].(bool)
+
+		displayUnit, _ := chart["display_unit"].(string)
+		displayScale, _ := chart["display_scale"].(float64)
+		if displayUnit != "" || displayScale != 0 {
+			c.DisplaySettings = &client.ChartDisplaySettings{
+				Unit:  displayUnit,
+				Scale: displayScale,
+			}
+		}
+
 		newCharts = append(newCharts, c)
 	}
 	return newCharts, nil
@@ -611,6 +920,243 @@ func buildTemplateVariables(templateVariablesIn []interface{}) []client.Template
 	return newTemplateVariables
 }
 
+func buildTemplateVariablePresets(presetsIn []interface{}) []client.TemplateVariablePreset {
+	var presets []client.TemplateVariablePreset
+	for _, p := range presetsIn {
+		presetMap := p.(map[string]interface{})
+		preset := client.TemplateVariablePreset{Name: presetMap["name"].(string)}
+		for _, v := range presetMap["variable"].([]interface{}) {
+			variable := v.(map[string]interface{})
+			preset.Variables = append(preset.Variables, client.TemplateVariablePresetValue{
+				Name:   variable["name"].(string),
+				Values: buildDefaultValues(variable["values"].([]interface{})),
+			})
+		}
+		presets = append(presets, preset)
+	}
+	return presets
+}
+
+func buildEventOverlays(overlaysIn []interface{}) []client.EventOverlay {
+	var overlays []client.EventOverlay
+	for _, o := range overlaysIn {
+		overlay := o.(map[string]interface{})
+		overlays = append(overlays, client.EventOverlay{
+			Name:   overlay["name"].(string),
+			Source: overlay["source"].(string),
+			Query:  overlay["query"].(string),
+		})
+	}
+	return overlays
+}
+
+// resolveLinkedDashboards checks that the linked dashboards exist in the project and resolves
+// the ones linked by name to their ID
+func resolveLinkedDashboards(ctx context.Context, c *client.Client, projectName string, linksIn []interface{}) ([]client.DashboardLink, error) {
+	if len(linksIn) == 0 {
+		return nil, nil
+	}
+
+	dashboards, err := c.ListUnifiedDashboards(ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dashboards to check the linked dashboards: %v", err)
+	}
+	ids := map[string]bool{}
+	idsByName := map[string][]string{}
+	for _, dashboard := range dashboards {
+		ids[dashboard.ID] = true
+		idsByName[dashboard.Attributes.Name] = append(idsByName[dashboard.Attributes.Name], dashboard.ID)
+	}
+
+	var links []client.DashboardLink
+	for i, l := range linksIn {
+		link, _ := l.(map[string]interface{})
+		id, _ := link["dashboard_id"].(string)
+		name, _ := link["dashboard_name"].(string)
+		switch {
+		case (id == "") == (name == ""):
+			return nil, fmt.Errorf("linked_dashboard %d: exactly one of dashboard_id and dashboard_name must be set", i)
+		case id != "" && !ids[id]:
+			return nil, fmt.Errorf("linked_dashboard %d: dashboard %q does not exist in project %q", i, id, projectName)
+		case name != "" && len(idsByName[name]) == 0:
+			return nil, fmt.Errorf("linked_dashboard %d: no dashboard is named %q in project %q", i, name, projectName)
+		case name != "" && len(idsByName[name]) > 1:
+			return nil, fmt.Errorf("linked_dashboard %d: %d dashboards are named %q in project %q, link to one by dashboard_id", i, len(idsByName[name]), name, projectName)
+		case name != "":
+			id = idsByName[name][0]
+		}
+		links = append(links, client.DashboardLink{ID: id})
+	}
+	return links, nil
+}
+
+// extractLinkedDashboards transforms the links from the API call into TF resource links. The
+// links configured by name are kept as they are in the prior state, since the API only
+// returns IDs.
+func extractLinkedDashboards(prior []interface{}, apiLinks []client.DashboardLink) []interface{} {
+	var links []interface{}
+	for i, l := range apiLinks {
+		if i < len(prior) {
+			if p, _ := prior[i].(map[string]interface{}); p != nil && p["dashboard_id"] == "" && p["dashboard_name"] != "" {
+				links = append(links, map[string]interface{}{"dashboard_name": p["dashboard_name"]})
+				continue
+			}
+		}
+		links = append(links, map[string]interface{}{"dashboard_id": l.ID})
+	}
+	return links
+}
+
+// validateTemplateVariablePresets is a CustomizeDiff function that checks that presets only
+// set the dashboard's template variables, and each of them at most once
+func validateTemplateVariablePresets(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	templateVariables := map[string]bool{}
+	if set, ok := d.Get("template_variable").(*schema.Set); ok {
+		for _, tv := range set.List() {
+			name, _ := tv.(map[string]interface{})["name"].(string)
+			if name == "" {
+				// the names aren't known until apply
+				return nil
+			}
+			templateVariables[name] = true
+		}
+	}
+
+	presets, _ := d.Get("preset").([]interface{})
+	for _, p := range presets {
+		preset, _ := p.(map[string]interface{})
+		variables, _ := preset["variable"].([]interface{})
+		seen := map[string]bool{}
+		for _, v := range variables {
+			name, _ := v.(map[string]interface{})["name"].(string)
+			if name == "" {
+				continue
+			}
+			if !templateVariables[name] {
+				return fmt.Errorf("preset %q: %q is not a template variable of the dashboard", preset["name"], name)
+			}
+			if seen[name] {
+				return fmt.Errorf("preset %q: template variable %q is set more than once", preset["name"], name)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+// planChartCount marks chart_count as unknown when the charts or groups change, so the plan
+// doesn't keep the count of the charts being replaced.
+func planChartCount(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.HasChange("chart") || d.HasChange("group") {
+		return d.SetNewComputed("chart_count")
+	}
+	return nil
+}
+
+// applyDefaultDashboardTimeRange plans the provider's default_dashboard_time_range for
+// dashboards whose configuration doesn't set time_range.
+func applyDefaultDashboardTimeRange(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta, ok := m.(*providerMeta)
+	if !ok || meta == nil {
+		return nil
+	}
+	defaultTimeRange := meta.defaultDashboardTimeRange
+	if defaultTimeRange == "" {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.GetAttr("time_range").IsNull() {
+		return nil
+	}
+	// dashboards pinned to an absolute window have no relative time range
+	if absolute := config.GetAttr("absolute_time_range"); !absolute.IsKnown() || (!absolute.IsNull() && absolute.LengthInt() > 0) {
+		return nil
+	}
+	if d.Get("time_range").(string) == defaultTimeRange {
+		return nil
+	}
+	return d.SetNew("time_range", defaultTimeRange)
+}
+
+// validateAbsoluteTimeRange is a CustomizeDiff function that checks that the absolute time
+// range of the dashboard ends after it starts
+func validateAbsoluteTimeRange(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	start, _ := d.Get("absolute_time_range.0.start").(string)
+	end, _ := d.Get("absolute_time_range.0.end").(string)
+	if start == "" || end == "" {
+		// no absolute time range, or its timestamps aren't known until apply
+		return nil
+	}
+
+	timeRange, err := buildAbsoluteTimeRange([]interface{}{map[string]interface{}{"start": start, "end": end}})
+	if err != nil {
+		return err
+	}
+	if !timeRange.End.After(timeRange.Start) {
+		return fmt.Errorf("absolute_time_range: end %v must be after start %v", end, start)
+	}
+	return nil
+}
+
+// validateLinkedDashboards is a CustomizeDiff function that checks that every linked dashboard
+// sets exactly one of dashboard_id and dashboard_name. Whether the dashboard exists is only
+// checked when applying, see resolveLinkedDashboards.
+func validateLinkedDashboards(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	links, _ := d.Get("linked_dashboard").([]interface{})
+	for i := range links {
+		idKey := fmt.Sprintf("linked_dashboard.%d.dashboard_id", i)
+		nameKey := fmt.Sprintf("linked_dashboard.%d.dashboard_name", i)
+		if !d.NewValueKnown(idKey) || !d.NewValueKnown(nameKey) {
+			// interpolated from resources that don't exist yet
+			continue
+		}
+		id, _ := d.Get(idKey).(string)
+		name, _ := d.Get(nameKey).(string)
+		if (id == "") == (name == "") {
+			return fmt.Errorf("linked_dashboard %d: exactly one of dashboard_id and dashboard_name must be set", i)
+		}
+	}
+	return nil
+}
+
+func buildAbsoluteTimeRange(timeRangeIn []interface{}) (*client.AbsoluteTimeRange, error) {
+	if len(timeRangeIn) == 0 || timeRangeIn[0] == nil {
+		return nil, nil
+	}
+	timeRange := timeRangeIn[0].(map[string]interface{})
+
+	start, err := time.Parse(time.RFC3339, timeRange["start"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid absolute_time_range start: %v", err)
+	}
+	end, err := time.Parse(time.RFC3339, timeRange["end"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid absolute_time_range end: %v", err)
+	}
+	return &client.AbsoluteTimeRange{Start: start, End: end}, nil
+}
+
+// suppressEquivalentTimestamp ignores differences between equal timestamps written
+// differently, e.g. with a +02:00 offset in the configuration and in UTC by the API
+func suppressEquivalentTimestamp(_, old, new string, _ *schema.ResourceData) bool {
+	oldTime, oldErr := time.Parse(time.RFC3339, old)
+	newTime, newErr := time.Parse(time.RFC3339, new)
+	return oldErr == nil && newErr == nil && oldTime.Equal(newTime)
+}
+
+func buildDefaultGroupBy(groupByIn []interface{}) *client.GroupBy {
+	if len(groupByIn) == 0 || groupByIn[0] == nil {
+		return nil
+	}
+
+	groupBy := groupByIn[0].(map[string]interface{})
+	return &client.GroupBy{
+		LabelKeys:   buildKeys(groupBy["keys"].([]interface{})),
+		Aggregation: groupBy["aggregation_method"].(string),
+	}
+}
+
 func buildDefaultValues(valuesIn []interface{}) []string {
 	defaultValues := make([]string, 0, len(valuesIn))
 	for _, v := range valuesIn {
@@ -619,7 +1165,67 @@ func buildDefaultValues(valuesIn []interface{}) []string {
 	return defaultValues
 }
 
+// ignorableServerChanges are the values supported by ignore_server_changes
+var ignorableServerChanges = []string{
+	"dashboard_description",
+	"label",
+	"template_variable",
+	"group_rank",
+	"chart_rank",
+	"chart_position",
+}
+
+// preserveIgnoredPanelFields copies the fields listed in ignored from the panels in the prior
+// state to the panels read from the API, matching them by ID
+func preserveIgnoredPanelFields(prior []interface{}, panels []interface{}, ignored map[string]bool) {
+	var fields []string
+	if ignored["chart_rank"] {
+		fields = append(fields, "rank")
+	}
+	if ignored["chart_position"] {
+		fields = append(fields, "x_pos", "y_pos", "width", "height")
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	priorByID := map[string]map[string]interface{}{}
+	for _, p := range prior {
+		panel := p.(map[string]interface{})
+		if id, _ := panel["id"].(string); id != "" {
+			priorByID[id] = panel
+		}
+	}
+	for _, p := range panels {
+		panel := p.(map[string]interface{})
+		priorPanel, ok := priorByID[panel["id"].(string)]
+		if !ok {
+			continue
+		}
+		for _, f := range fields {
+			if v, ok := priorPanel[f]; ok {
+				panel[f] = v
+			}
+		}
+	}
+}
+
+// setList returns the elements of the set at key, or nil if it isn't set
+func setList(m map[string]interface{}, key string) []interface{} {
+	if set, ok := m[key].(*schema.Set); ok {
+		return set.List()
+	}
+	return nil
+}
+
 func (p *resourceUnifiedDashboardImp) setResourceDataFromUnifiedDashboard(project string, dash client.UnifiedDashboard, d *schema.ResourceData, hasLegacyChartsIn bool) error {
+	ignored := map[string]bool{}
+	if ignoreSet, ok := d.Get("ignore_server_changes").(*schema.Set); ok {
+		for _, f := range ignoreSet.List() {
+			ignored[f.(string)] = true
+		}
+	}
+
 	if err := d.Set("project_name", project); err != nil {
 		return fmt.Errorf("unable to set project_name resource field: %v", err)
 	}
@@ -628,26 +1234,72 @@ func (p *resourceUnifiedDashboardImp) setResourceDataFromUnifiedDashboard(projec
 		return fmt.Errorf("unable to set dashboard_name resource field: %v", err)
 	}
 
-	if err := d.Set("dashboard_description", dash.Attributes.Description); err != nil {
-		return fmt.Errorf("unable to set dashboard_description resource field: %v", err)
+	if !ignored["dashboard_description"] {
+		if err := d.Set("dashboard_description", dash.Attributes.Description); err != nil {
+			return fmt.Errorf("unable to set dashboard_description resource field: %v", err)
+		}
+	}
+
+	if err := d.Set("is_default", dash.Attributes.IsDefault); err != nil {
+		return fmt.Errorf("unable to set is_default resource field: %v", err)
+	}
+
+	if err := d.Set("protected", dash.Attributes.Locked); err != nil {
+		return fmt.Errorf("unable to set protected resource field: %v", err)
+	}
+
+	if err := d.Set("time_range", dash.Attributes.TimeRange); err != nil {
+		return fmt.Errorf("unable to set time_range resource field: %v", err)
+	}
+
+	var absoluteTimeRange []interface{}
+	if tr := dash.Attributes.AbsoluteTimeRange; tr != nil {
+		absoluteTimeRange = []interface{}{
+			map[string]interface{}{
+				"start": tr.Start.Format(time.RFC3339Nano),
+				"end":   tr.End.Format(time.RFC3339Nano),
+			},
+		}
+	}
+	if err := d.Set("absolute_time_range", absoluteTimeRange); err != nil {
+		return fmt.Errorf("unable to set absolute_time_range resource field: %v", err)
 	}
 
 	if err := d.Set("type", dash.Type); err != nil {
 		return fmt.Errorf("unable to set type resource field: %v", err)
 	}
 
+	if err := d.Set("version", dash.Version); err != nil {
+		return fmt.Errorf("unable to set version resource field: %v", err)
+	}
+
+	chartCount := 0
 	if isLegacyImplicitGroup(dash.Attributes.Groups, hasLegacyChartsIn) {
 		charts, textPanels, err := assembleDashboardPanels(dash.ID, p.chartSchemaType, dash.Attributes.Groups[0].Charts)
 		if err != nil {
 			return err
 		}
+		chartCount = len(charts)
 		if len(textPanels) > 0 {
 			return fmt.Errorf("text panels are only supported within groups")
 		}
+		if priorCharts, ok := d.Get("chart").(*schema.Set); ok {
+			preserveIgnoredPanelFields(priorCharts.List(), charts, ignored)
+		}
 		if err := d.Set("chart", charts); err != nil {
 			return err
 		}
 	} else {
+		priorGroups := map[string]map[string]interface{}{}
+		if groupSet, ok := d.Get("group").(*schema.Set); ok {
+			for _, g := range groupSet.List() {
+				group := g.(map[string]interface{})
+				if id, _ := group["id"].(string); id != "" {
+					priorGroups[id] = group
+				}
+			}
+		}
+
 		var groups []interface{}
 		for _, g := range dash.Attributes.Groups {
 			group := map[string]interface{}{}
@@ -660,6 +1312,13 @@ func (p *resourceUnifiedDashboardImp) setResourceDataFromUnifiedDashboard(projec
 			if err != nil {
 				return err
 			}
+			chartCount += len(groupCharts)
+			if priorGroup, ok := priorGroups[g.ID]; ok {
+				preserveIgnoredPanelFields(setList(priorGroup, "chart"), groupCharts, ignored)
+				if ignored["group_rank"] {
+					group["rank"] = priorGroup["rank"]
+				}
+			}
 			group["chart"] = groupCharts
 			group["text_panel"] = groupTextPanels
 
@@ -669,10 +1328,15 @@ func (p *resourceUnifiedDashboardImp) setResourceDataFromUnifiedDashboard(projec
 			return fmt.Errorf("unable to set group resource field: %v", err)
 		}
 	}
+	if err := d.Set("chart_count", chartCount); err != nil {
+		return fmt.Errorf("unable to set chart_count resource field: %v", err)
+	}
 
-	labels := extractLabels(dash.Attributes.Labels)
-	if err := d.Set("label", labels); err != nil {
-		return fmt.Errorf("unable to set labels resource field: %v", err)
+	if !ignored["label"] {
+		labels := extractLabels(dash.Attributes.Labels)
+		if err := d.Set("label", labels); err != nil {
+			return fmt.Errorf("unable to set labels resource field: %v", err)
+		}
 	}
 
 	var templateVariables []interface{}
@@ -684,8 +1348,57 @@ func (p *resourceUnifiedDashboardImp) setResourceDataFromUnifiedDashboard(projec
 
 		templateVariables = append(templateVariables, templateVariable)
 	}
-	if err := d.Set("template_variable", templateVariables); err != nil {
-		return fmt.Errorf("unable to set template variables resource field: %v", err)
+	if !ignored["template_variable"] {
+		if err := d.Set("template_variable", templateVariables); err != nil {
+			return fmt.Errorf("unable to set template variables resource field: %v", err)
+		}
+	}
+
+	var presets []interface{}
+	for _, p := range dash.Attributes.TemplateVariablePresets {
+		var variables []interface{}
+		for _, v := range p.Variables {
+			variables = append(variables, map[string]interface{}{
+				"name":   v.Name,
+				"values": v.Values,
+			})
+		}
+		presets = append(presets, map[string]interface{}{
+			"name":     p.Name,
+			"variable": variables,
+		})
+	}
+	if err := d.Set("preset", presets); err != nil {
+		return fmt.Errorf("unable to set preset resource field: %v", err)
+	}
+
+	var overlays []interface{}
+	for _, o := range dash.Attributes.EventOverlays {
+		overlays = append(overlays, map[string]interface{}{
+			"name":   o.Name,
+			"source": o.Source,
+			"query":  o.Query,
+		})
+	}
+	if err := d.Set("event_overlay", overlays); err != nil {
+		return fmt.Errorf("unable to set event_overlay resource field: %v", err)
+	}
+
+	if err := d.Set("linked_dashboard", extractLinkedDashboards(d.Get("linked_dashboard").([]interface{}), dash.Attributes.LinkedDashboards)); err != nil {
+		return fmt.Errorf("unable to set linked_dashboard resource field: %v", err)
+	}
+
+	var defaultGroupBy []interface{}
+	if dash.Attributes.DefaultGroupBy != nil {
+		defaultGroupBy = []interface{}{
+			map[string]interface{}{
+				"keys":               dash.Attributes.DefaultGroupBy.LabelKeys,
+				"aggregation_method": dash.Attributes.DefaultGroupBy.Aggregation,
+			},
+		}
+	}
+	if err := d.Set("default_group_by", defaultGroupBy); err != nil {
+		return fmt.Errorf("unable to set default_group_by resource field: %v", err)
 	}
 
 	return nil
@@ -796,6 +1509,16 @@ func assembleCharts(
 			resource["subtitle"] = *c.Subtitle
 		}
 
+		if c.Precision != nil {
+			resource["precision"] = *c.Precision
+		}
+		resource["show_spark_line"] = c.ShowSparkLine
+
+		if c.DisplaySettings != nil {
+			resource["display_unit"] = c.DisplaySettings.Unit
+			resource["display_scale"] = c.DisplaySettings.Scale
+		}
+
 		if chartSchemaType == MetricChartSchema {
 			resource["query"] = getQueriesFromMetricConditionData(c.MetricQueries)
 		} else {
@@ -810,11 +1533,133 @@ func assembleCharts(
 			resource["query"] = queries
 		}
 
+		// time_shift, alias and baseline only exist on dashboard queries, so they're set here
+		// rather than in the query conversion helpers shared with the condition resources
+		for i, q := range resource["query"].([]interface{}) {
+			q.(map[string]interface{})["time_shift"] = c.MetricQueries[i].TimeShift
+			q.(map[string]interface{})["alias"] = c.MetricQueries[i].DisplayLabel
+			q.(map[string]interface{})["baseline"] = getBaselineFromResourceData(c.MetricQueries[i].Baseline)
+		}
+
 		chartResources = append(chartResources, resource)
 	}
 	return chartResources, nil
 }
 
+// configuredPrecisions returns the precision of the charts whose configuration sets one, keyed
+// by chart name. Charts without precision let Lightstep choose the number of decimal places,
+// which state can't tell apart from a precision of 0.
+func configuredPrecisions(config cty.Value) map[string]int {
+	precisions := map[string]int{}
+	if config.IsNull() || !config.IsKnown() {
+		return precisions
+	}
+
+	collect := func(charts cty.Value) {
+		if charts.IsNull() || !charts.IsKnown() {
+			return
+		}
+		for it := charts.ElementIterator(); it.Next(); {
+			_, chart := it.Element()
+			if chart.IsNull() || !chart.IsKnown() {
+				continue
+			}
+			name, precision := chart.GetAttr("name"), chart.GetAttr("precision")
+			if !name.IsKnown() || name.IsNull() || !precision.IsKnown() || precision.IsNull() {
+				continue
+			}
+			p, _ := precision.AsBigFloat().Int64()
+			precisions[name.AsString()] = int(p)
+		}
+	}
+
+	collect(config.GetAttr("chart"))
+	groups := config.GetAttr("group")
+	if groups.IsNull() || !groups.IsKnown() {
+		return precisions
+	}
+	for it := groups.ElementIterator(); it.Next(); {
+		_, group := it.Element()
+		if !group.IsNull() && group.IsKnown() {
+			collect(group.GetAttr("chart"))
+		}
+	}
+	return precisions
+}
+
+// validateBigNumberChartOptions is a CustomizeDiff function that checks that precision and
+// show_spark_line are only set on charts showing a big number
+func validateBigNumberChartOptions(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	precisions := configuredPrecisions(d.GetRawConfig())
+	check := func(charts *schema.Set) error {
+		for _, c := range charts.List() {
+			chart := c.(map[string]interface{})
+			var option string
+			if _, ok := precisions[chart["name"].(string)]; ok {
+				option = "precision"
+			} else if showSparkLine, _ := chart["show_spark_line"].(bool); showSparkLine {
+				option = "show_spark_line"
+			} else {
+				continue
+			}
+
+			queries, _ := chart["query"].([]interface{})
+			var bigNumber bool
+			for _, q := range queries {
+				display, _ := q.(map[string]interface{})["display"].(string)
+				bigNumber = bigNumber || display == "big_number" || display == "big_number_v2"
+			}
+			if !bigNumber {
+				return fmt.Errorf("chart %q: %v is only supported by charts with a big_number or big_number_v2 query", chart["name"], option)
+			}
+		}
+		return nil
+	}
+
+	if charts, ok := d.Get("chart").(*schema.Set); ok {
+		if err := check(charts); err != nil {
+			return err
+		}
+	}
+	groups, _ := d.Get("group").(*schema.Set)
+	if groups == nil {
+		return nil
+	}
+	for _, g := range groups.List() {
+		if charts, ok := g.(map[string]interface{})["chart"].(*schema.Set); ok {
+			if err := check(charts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validatePositiveFloat checks that a chart's display scale is a positive number
+func validatePositiveFloat(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(float64)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be float", k)}
+	}
+	if v <= 0 {
+		return nil, []error{fmt.Errorf("expected %s to be positive, got %v", k, v)}
+	}
+	return nil, nil
+}
+
+func getBaselineFromResourceData(baseline *client.Baseline) []interface{} {
+	if baseline == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"lookback":    baseline.Lookback,
+			"sensitivity": baseline.Sensitivity,
+		},
+	}
+}
+
 // isLegacyImplicitGroup defines the logic for determining if the charts in this dashboard need to be unwrapped to
 // maintain backwards compatibility with the pre group definition
 func isLegacyImplicitGroup(groups []client.UnifiedGroup, hasLegacyChartsIn bool) bool {
@@ -837,24 +1682,94 @@ func isLegacyImplicitGroup(groups []client.UnifiedGroup, hasLegacyChartsIn bool)
 }
 
 func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
+	if err := validateDashboardCapabilities(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
 	attrs, _, err := getUnifiedDashboardAttributesFromResource(d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to get dashboard attributes from resource : %v", err))
 	}
+	attrs.LinkedDashboards, err = resolveLinkedDashboards(ctx, c, d.Get("project_name").(string), d.Get("linked_dashboard").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	if _, err := c.UpdateUnifiedDashboard(ctx, d.Get("project_name").(string), d.Id(), *attrs); err != nil {
+	if _, err := c.UpdateUnifiedDashboard(ctx, d.Get("project_name").(string), d.Id(), *attrs, d.Get("version").(string)); err != nil {
+		if diags := requestValidationDiags(err); diags != nil {
+			return diags
+		}
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if ok && apiErr.GetStatusCode() == http.StatusPreconditionFailed {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Dashboard was modified outside of Terraform",
+				Detail: fmt.Sprintf("Dashboard %v has been changed since it was last read, so the update was rejected to avoid overwriting those changes. "+
+					"Run `terraform apply -refresh-only` (or `terraform refresh`) to pick up the latest version, review the plan and apply again.", d.Id()),
+			}}
+		}
 		return diag.FromErr(fmt.Errorf("failed to update dashboard: %v", err))
 	}
 
-	return p.resourceUnifiedDashboardRead(ctx, d, m)
+	// is_default is computed, so it only changes when the configuration sets it
+	if d.HasChange("is_default") {
+		projectName := d.Get("project_name").(string)
+		if d.Get("is_default").(bool) {
+			err = setDefaultDashboard(ctx, c, projectName, d.Id())
+		} else if err = c.UnsetDefaultDashboard(ctx, projectName, d.Id()); err != nil {
+			err = fmt.Errorf("failed to unset default dashboard: %v", err)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return append(p.resourceUnifiedDashboardRead(ctx, d, m), dashboardChartLimitWarning(d, m)...)
+}
+
+// setDefaultDashboard makes the dashboard the default dashboard of the project. To avoid
+// silently taking over from another dashboard, it fails if the project already has a default.
+func setDefaultDashboard(ctx context.Context, c *client.Client, projectName string, dashboardID string) error {
+	dashboards, err := c.ListUnifiedDashboards(ctx, projectName)
+	if err != nil {
+		return fmt.Errorf("failed to list dashboards: %v", err)
+	}
+	for _, other := range dashboards {
+		if other.ID != dashboardID && other.Attributes.IsDefault {
+			return fmt.Errorf("dashboard %q (%v) is already the default dashboard of project %v, "+
+				"only one dashboard can be the default: set is_default = false on it first", other.Attributes.Name, other.ID, projectName)
+		}
+	}
+
+	if err := c.SetDefaultDashboard(ctx, projectName, dashboardID); err != nil {
+		return fmt.Errorf("failed to set default dashboard: %v", err)
+	}
+	return nil
 }
 
 func (*resourceUnifiedDashboardImp) resourceUnifiedDashboardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
-	if err := c.DeleteUnifiedDashboard(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	protectedErr := fmt.Errorf("dashboard %v is protected and cannot be deleted, set protected = false and apply or set force_destroy before destroying it", d.Id())
+	forceDestroy := d.Get("force_destroy").(bool)
+	if d.Get("protected").(bool) && !forceDestroy {
+		return diag.FromErr(protectedErr)
+	}
+
+	c := m.(*providerMeta).client
+	projectName := d.Get("project_name").(string)
+	err := c.DeleteUnifiedDashboard(ctx, projectName, d.Id())
+	// the dashboard may have been locked outside of Terraform since it was last read
+	if apiErr, ok := err.(client.APIResponseCarrier); ok && apiErr.GetStatusCode() == http.StatusLocked {
+		if !forceDestroy {
+			return diag.FromErr(protectedErr)
+		}
+		if err := unlockUnifiedDashboard(ctx, c, projectName, d.Id()); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to unprotect dashboard %v before deleting it: %v", d.Id(), err))
+		}
+		err = c.DeleteUnifiedDashboard(ctx, projectName, d.Id())
+	}
+	if err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete dashboard: %v", err))
 	}
 
@@ -864,19 +1779,72 @@ func (*resourceUnifiedDashboardImp) resourceUnifiedDashboardDelete(ctx context.C
 	return diags
 }
 
-func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+// unlockUnifiedDashboard unprotects the dashboard so it can be deleted, keeping the rest of
+// it as is
+func unlockUnifiedDashboard(ctx context.Context, c *client.Client, projectName string, id string) error {
+	dash, err := c.GetUnifiedDashboard(ctx, projectName, id)
+	if err != nil {
+		return err
+	}
+	attributes := dash.Attributes
+	attributes.Locked = false
+	_, err = c.UpdateUnifiedDashboard(ctx, projectName, id, attributes, "")
+	return err
+}
 
-	ids := strings.Split(d.Id(), ".")
-	if len(ids) != 2 {
-		resourceName := "lighstep_dashboard"
-		if p.chartSchemaType == MetricChartSchema {
-			resourceName = "lightstep_metric_dashboard"
+// parseDashboardURL returns the project of a Lightstep dashboard URL copied from the browser,
+// e.g. https://app.lightstep.com/<project>/dashboard/<slug>, and the path segments after
+// "dashboard", which identify the dashboard; see GetUnifiedDashboardIDBySlug. The query and the
+// fragment are ignored.
+func parseDashboardURL(rawURL string) (project string, segments []string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid dashboard URL %q: %v", rawURL, err)
+	}
+
+	var path []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			path = append(path, s)
 		}
-		return []*schema.ResourceData{}, fmt.Errorf("error importing %v. Expecting an  ID formed as '<lightstep_project>.<%v_ID>'", resourceName, resourceName)
+	}
+	// the first segment is always the project, which may itself be named "dashboard"
+	if len(path) >= 3 && path[1] == "dashboard" {
+		return path[0], path[2:], nil
+	}
+	return "", nil, fmt.Errorf("dashboard URL %q doesn't have the form https://app.lightstep.com/<project>/dashboard/<slug>", rawURL)
+}
+
+func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*providerMeta).client
+
+	var project, id string
+	if strings.Contains(d.Id(), "://") {
+		var (
+			segments []string
+			err      error
+		)
+		project, segments, err = parseDashboardURL(d.Id())
+		if err != nil {
+			return []*schema.ResourceData{}, err
+		}
+		// the URL may have a slug of the dashboard name rather than its ID
+		id, err = c.GetUnifiedDashboardIDBySlug(ctx, project, segments)
+		if err != nil {
+			return []*schema.ResourceData{}, fmt.Errorf("failed to resolve dashboard URL %q: %v", d.Id(), err)
+		}
+	} else {
+		ids := strings.Split(d.Id(), ".")
+		if len(ids) != 2 {
+			resourceName := "lighstep_dashboard"
+			if p.chartSchemaType == MetricChartSchema {
+				resourceName = "lightstep_metric_dashboard"
+			}
+			return []*schema.ResourceData{}, fmt.Errorf("error importing %v. Expecting an  ID formed as '<lightstep_project>.<%v_ID>' or a dashboard URL", resourceName, resourceName)
+		}
+		project, id = ids[0], ids[1]
 	}
 
-	project, id := ids[0], ids[1]
 	dash, err := c.GetUnifiedDashboard(ctx, project, id)
 	if err != nil {
 		return []*schema.ResourceData{}, fmt.Errorf("failed to get dashboard. err: %v", err)
@@ -885,6 +1853,7 @@ func (p *resourceUnifiedDashboardImp) resourceUnifiedDashboardImport(ctx context
 	if err := p.setResourceDataFromUnifiedDashboard(project, *dash, d, false); err != nil {
 		return nil, fmt.Errorf("failed to set dashboard from API response to terraform state: %v", err)
 	}
+	d.Set("force_destroy", false)
 
 	return []*schema.ResourceData{d}, nil
 
@@ -942,3 +1911,27 @@ func extractLabels(apiLabels []client.Label) []interface{} {
 	}
 	return labels
 }
+
+// requestValidationDiags reports each field of a dashboard request rejected by the client-side
+// validation (see validate_requests) as its own diagnostic. It returns nil for other errors.
+func requestValidationDiags(err error) diag.Diagnostics {
+	var validationErr *client.RequestValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	for _, v := range validationErr.Violations {
+		diagnostic := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid dashboard request",
+			Detail:   fmt.Sprintf("%v %v", v.Path, v.Message),
+		}
+		// charts and groups are sets, so only the top-level fields map to an attribute path
+		if v.Path == "attributes.name" {
+			diagnostic.AttributePath = cty.GetAttrPath("dashboard_name")
+		}
+		diags = append(diags, diagnostic)
+	}
+	return diags
+}
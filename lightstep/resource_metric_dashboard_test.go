@@ -1,13 +1,24 @@
 package lightstep
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/lightstep/terraform-provider-lightstep/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccDashboardLegacyFormat(t *testing.T) {
@@ -524,3 +535,330 @@ func Test_extractLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestUnifiedDashboardUpdateVersionConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public/v0.2/blars/capabilities" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	p := resourceUnifiedDashboardImp{chartSchemaType: UnifiedChartSchema}
+	d := resourceUnifiedDashboard(UnifiedChartSchema).TestResourceData()
+	d.SetId("hi")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+	require.NoError(t, d.Set("dashboard_name", "dash"))
+	require.NoError(t, d.Set("version", `"v1"`))
+
+	diags := p.resourceUnifiedDashboardUpdate(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Dashboard was modified outside of Terraform", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "terraform apply -refresh-only")
+}
+
+func TestUnifiedDashboardDeleteForceDestroy(t *testing.T) {
+	var (
+		locked  = true
+		deletes int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards/d1", r.URL.Path)
+		switch r.Method {
+		case http.MethodDelete:
+			if locked {
+				w.WriteHeader(http.StatusLocked)
+				return
+			}
+			deletes++
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			_, err := w.Write([]byte(`{"data": {"id": "d1", "attributes": {"name": "Checkout", "locked": true}}}`))
+			assert.NoError(t, err)
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(body), `"locked"`, "the dashboard is unlocked")
+			assert.Contains(t, string(body), `"name":"Checkout"`, "the rest of the dashboard is kept")
+			locked = false
+			_, err = w.Write([]byte(`{"data": {"id": "d1", "attributes": {"name": "Checkout"}}}`))
+			assert.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	p := resourceUnifiedDashboardImp{chartSchemaType: UnifiedChartSchema}
+	d := resourceUnifiedDashboard(UnifiedChartSchema).TestResourceData()
+	d.SetId("d1")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+	require.NoError(t, d.Set("protected", true))
+
+	diags := p.resourceUnifiedDashboardDelete(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "dashboard d1 is protected")
+	assert.Equal(t, 0, deletes)
+
+	require.NoError(t, d.Set("force_destroy", true))
+	diags = p.resourceUnifiedDashboardDelete(context.Background(), d, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, 1, deletes)
+	assert.Empty(t, d.Id())
+}
+
+func TestUnifiedDashboardKeepsUnmanagedSettings(t *testing.T) {
+	// dashboards can be locked or made the default in the UI
+	for _, attribute := range []string{"protected", "is_default"} {
+		t.Run(attribute, func(t *testing.T) {
+			r := resourceUnifiedDashboard(UnifiedChartSchema)
+			state := &terraform.InstanceState{
+				ID: "d1",
+				Attributes: map[string]string{
+					"project_name":   "tacoman",
+					"dashboard_name": "Checkout",
+					attribute:        "true",
+				},
+			}
+
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"project_name":   "tacoman",
+				"dashboard_name": "Checkout",
+			}), nil)
+			require.NoError(t, err)
+			if diff != nil {
+				assert.NotContains(t, diff.Attributes, attribute, "kept when not configured")
+			}
+
+			diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"project_name":   "tacoman",
+				"dashboard_name": "Checkout",
+				attribute:        false,
+			}), nil)
+			require.NoError(t, err)
+			require.NotNil(t, diff)
+			assert.Equal(t, "false", diff.Attributes[attribute].New)
+		})
+	}
+}
+
+func TestUnifiedDashboardChartCount(t *testing.T) {
+	chart := func(chartType string, rank int) client.UnifiedChart {
+		return client.UnifiedChart{Title: fmt.Sprintf("panel %d", rank), ChartType: chartType, Rank: rank}
+	}
+	dash := client.UnifiedDashboard{
+		ID: "d1",
+		Attributes: client.UnifiedDashboardAttributes{
+			Name: "Resources",
+			Groups: []client.UnifiedGroup{
+				{ID: "g1", Title: "CPU", VisibilityType: "explicit", Charts: []client.UnifiedChart{
+					chart("timeseries", 0), chart("text", 1), chart("timeseries", 2),
+				}},
+				{ID: "g2", Title: "Memory", VisibilityType: "explicit", Rank: 1, Charts: []client.UnifiedChart{
+					chart("timeseries", 0),
+				}},
+			},
+		},
+	}
+
+	p := resourceUnifiedDashboardImp{chartSchemaType: UnifiedChartSchema}
+	d := resourceUnifiedDashboard(UnifiedChartSchema).TestResourceData()
+	require.NoError(t, p.setResourceDataFromUnifiedDashboard("tacoman", dash, d, false))
+	assert.Equal(t, 3, d.Get("chart_count"), "the charts of every group are counted, text panels aren't")
+}
+
+func TestPlanChartCount(t *testing.T) {
+	r := resourceUnifiedDashboard(UnifiedChartSchema)
+	d := schema.TestResourceDataRaw(t, r.Schema, dashboardConfig(1, 1))
+	d.SetId("d1")
+	require.NoError(t, d.Set("chart_count", 2))
+	state := d.State()
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(dashboardConfig(1, 1)), &providerMeta{})
+	require.NoError(t, err)
+	if diff != nil {
+		assert.NotContains(t, diff.Attributes, "chart_count", "the count is kept while the charts don't change")
+	}
+
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(dashboardConfig(2, 1)), &providerMeta{})
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	require.Contains(t, diff.Attributes, "chart_count")
+	assert.True(t, diff.Attributes["chart_count"].NewComputed)
+}
+
+func TestChartPrecision(t *testing.T) {
+	chart := func(name string, precision cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name), "precision": precision})
+	}
+	config := cty.ObjectVal(map[string]cty.Value{
+		"chart": cty.SetVal([]cty.Value{chart("legacy", cty.NumberIntVal(2))}),
+		"group": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"chart": cty.SetVal([]cty.Value{
+				chart("zero", cty.NumberIntVal(0)),
+				chart("automatic", cty.NullVal(cty.Number)),
+			}),
+		})}),
+	})
+	assert.Equal(t, map[string]int{"legacy": 2, "zero": 0}, configuredPrecisions(config),
+		"an explicit precision of 0 is sent, charts without precision let Lightstep choose")
+
+	charts, err := assembleCharts("d1", UnifiedChartSchema, []client.UnifiedChart{{Title: "automatic", ChartType: "timeseries"}})
+	require.NoError(t, err)
+	assert.NotContains(t, charts[0], "precision", "charts without precision don't have one in state")
+}
+
+func Test_parseDashboardURL(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		project  string
+		segments []string
+	}{
+		{url: "https://app.lightstep.com/checkout/dashboard/AbC123", project: "checkout", segments: []string{"AbC123"}},
+		{url: "https://app.lightstep.com/checkout/dashboard/AbC123/", project: "checkout", segments: []string{"AbC123"}},
+		{url: "https://app.lightstep.com/checkout/dashboard/checkout-overview/AbC123?range=3600#chart", project: "checkout", segments: []string{"checkout-overview", "AbC123"}},
+		{url: "https://app-staging.lightstep.com/my-project/dashboard/d1", project: "my-project", segments: []string{"d1"}},
+		// the project is the first segment, even if it's named "dashboard"
+		{url: "https://app.lightstep.com/dashboard/dashboard/d1", project: "dashboard", segments: []string{"d1"}},
+	} {
+		project, segments, err := parseDashboardURL(tc.url)
+		require.NoError(t, err, tc.url)
+		assert.Equal(t, tc.project, project, tc.url)
+		assert.Equal(t, tc.segments, segments, tc.url)
+	}
+
+	for _, bad := range []string{
+		"https://app.lightstep.com/checkout/dashboard",
+		"https://app.lightstep.com/dashboard/AbC123",
+		"https://app.lightstep.com/checkout/streams/AbC123",
+		"https://app.lightstep.com/org/checkout/dashboard/AbC123",
+	} {
+		_, _, err := parseDashboardURL(bad)
+		assert.ErrorContains(t, err, "doesn't have the form", bad)
+	}
+}
+
+func TestUnifiedDashboardImportByURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"data": {"id": "AbC123", "attributes": {"name": "Checkout overview"}}}`
+		if r.URL.Path == "/public/v0.2/blars/projects/checkout/metric_dashboards" {
+			// URLs are resolved by listing the dashboards of the project
+			body = `{"data": [{"id": "AbC123", "attributes": {"name": "Checkout overview"}}]}`
+		} else {
+			assert.Equal(t, "/public/v0.2/blars/projects/checkout/metric_dashboards/AbC123", r.URL.Path)
+		}
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	p := resourceUnifiedDashboardImp{chartSchemaType: UnifiedChartSchema}
+	for _, importID := range []string{
+		"checkout.AbC123",
+		"https://app.lightstep.com/checkout/dashboard/AbC123",
+		"https://app.lightstep.com/checkout/dashboard/checkout-overview",
+	} {
+		d := resourceUnifiedDashboard(UnifiedChartSchema).TestResourceData()
+		d.SetId(importID)
+
+		imported, err := p.resourceUnifiedDashboardImport(context.Background(), d, meta)
+		require.NoError(t, err, importID)
+		require.Len(t, imported, 1)
+		assert.Equal(t, "AbC123", imported[0].Id(), importID)
+		assert.Equal(t, "checkout", imported[0].Get("project_name"), importID)
+		assert.Equal(t, "Checkout overview", imported[0].Get("dashboard_name"), importID)
+	}
+}
+
+func TestRequestValidationDiags(t *testing.T) {
+	assert.Nil(t, requestValidationDiags(nil))
+	assert.Nil(t, requestValidationDiags(fmt.Errorf("some API error")))
+
+	diags := requestValidationDiags(&client.RequestValidationError{Violations: []client.FieldViolation{
+		{Path: "attributes.name", Message: "is required"},
+		{Path: "attributes.groups[0].charts[0].chart-type", Message: "is required"},
+	}})
+	require.Len(t, diags, 2)
+	assert.Equal(t, "Invalid dashboard request", diags[0].Summary)
+	assert.Equal(t, cty.GetAttrPath("dashboard_name"), diags[0].AttributePath)
+	assert.Equal(t, "attributes.groups[0].charts[0].chart-type is required", diags[1].Detail)
+	assert.Nil(t, diags[1].AttributePath)
+}
+
+func TestValidateLinkedDashboards(t *testing.T) {
+	diff := func(link map[string]interface{}) error {
+		_, err := resourceUnifiedDashboard(UnifiedChartSchema).Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_name":     "tacoman",
+			"dashboard_name":   "Links",
+			"linked_dashboard": []interface{}{link},
+		}), &providerMeta{})
+		return err
+	}
+
+	assert.NoError(t, diff(map[string]interface{}{"dashboard_id": "d1"}))
+	assert.NoError(t, diff(map[string]interface{}{"dashboard_name": "Checkout"}))
+	assert.ErrorContains(t, diff(map[string]interface{}{}), "linked_dashboard 0: exactly one of dashboard_id and dashboard_name must be set")
+	assert.ErrorContains(t, diff(map[string]interface{}{"dashboard_id": "d1", "dashboard_name": "Checkout"}), "exactly one of dashboard_id and dashboard_name must be set")
+}
+
+func TestResolveLinkedDashboards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/metric_dashboards", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": [
+			{"id": "d1", "attributes": {"name": "Checkout"}},
+			{"id": "d2", "attributes": {"name": "Copy"}},
+			{"id": "d3", "attributes": {"name": "Copy"}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	c := client.NewClient("api", "blars", "staging")
+
+	link := func(id string, name string) interface{} {
+		return map[string]interface{}{"dashboard_id": id, "dashboard_name": name}
+	}
+
+	links, err := resolveLinkedDashboards(context.Background(), c, "tacoman", []interface{}{link("d2", ""), link("", "Checkout")})
+	require.NoError(t, err)
+	assert.Equal(t, []client.DashboardLink{{ID: "d2"}, {ID: "d1"}}, links)
+
+	for _, tc := range []struct {
+		link     interface{}
+		expected string
+	}{
+		{link("", ""), "exactly one of dashboard_id and dashboard_name must be set"},
+		{link("d1", "Checkout"), "exactly one of dashboard_id and dashboard_name must be set"},
+		{link("d4", ""), `dashboard "d4" does not exist in project "tacoman"`},
+		{link("", "Payments"), `no dashboard is named "Payments" in project "tacoman"`},
+		{link("", "Copy"), `2 dashboards are named "Copy" in project "tacoman"`},
+	} {
+		_, err := resolveLinkedDashboards(context.Background(), c, "tacoman", []interface{}{tc.link})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.expected)
+		}
+	}
+
+	// the links configured by name stay that way in the state
+	prior := []interface{}{link("d2", ""), link("", "Checkout")}
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"dashboard_id": "d2"},
+		map[string]interface{}{"dashboard_name": "Checkout"},
+		map[string]interface{}{"dashboard_id": "d3"},
+	}, extractLinkedDashboards(prior, []client.DashboardLink{{ID: "d2"}, {ID: "d1"}, {ID: "d3"}}))
+}
@@ -42,7 +42,7 @@ func resourcePagerdutyDestination() *schema.Resource {
 }
 
 func resourcePagerdutyDestinationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	destination, err := c.CreateDestination(ctx, d.Get("project_name").(string),
 		client.Destination{
 			Type: "destination",
@@ -61,7 +61,7 @@ func resourcePagerdutyDestinationCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourcePagerdutyDestinationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids, err := splitID(d.Id())
 	if err != nil {
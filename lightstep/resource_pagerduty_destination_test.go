@@ -91,7 +91,7 @@ func testAccCheckPagerdutyDestinationExists(resourceName string, destination *cl
 		}
 
 		// get destination from LS
-		c := testAccProvider.Meta().(*client.Client)
+		c := testAccProvider.Meta().(*providerMeta).client
 		d, err := c.GetDestination(context.Background(), testProject, tfDestination.Primary.ID)
 		if err != nil {
 			return err
@@ -103,7 +103,7 @@ func testAccCheckPagerdutyDestinationExists(resourceName string, destination *cl
 }
 
 func testAccPagerdutyDestinationDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 	for _, resource := range s.RootModule().Resources {
 		if resource.Type != "lightstep_pagerduty_destination" {
 			continue
@@ -0,0 +1,132 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func resourceProject() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a Lightstep project, which the other resources of the provider reference by name.",
+		CreateContext: resourceProjectCreate,
+		ReadContext:   resourceProjectRead,
+		UpdateContext: resourceProjectUpdate,
+		DeleteContext: resourceProjectDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProjectImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the project, which is also its ID. Changing it replaces the project.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Optional description of what the project is for.",
+			},
+		},
+	}
+}
+
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*providerMeta).client
+
+	project, err := c.CreateProject(ctx, d.Get("project_name").(string), getProjectAttributesFromResource(d))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create project: %v", err))
+	}
+
+	d.SetId(project.ID)
+	return resourceProjectRead(ctx, d, m)
+}
+
+func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*providerMeta).client
+	project, err := c.GetProject(ctx, d.Id())
+	if err != nil {
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if !ok {
+			return diag.FromErr(fmt.Errorf("failed to get project: %v", err))
+		}
+
+		if apiErr.GetStatusCode() == http.StatusNotFound {
+			d.SetId("")
+			return diags
+		}
+
+		return diag.FromErr(fmt.Errorf("failed to get project: %v", apiErr))
+	}
+
+	if err := setResourceDataFromProject(d, *project); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set project from API response to terraform state: %v", err))
+	}
+	return diags
+}
+
+func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*providerMeta).client
+
+	if _, err := c.UpdateProject(ctx, d.Id(), getProjectAttributesFromResource(d)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to update project: %v", err))
+	}
+	return resourceProjectRead(ctx, d, m)
+}
+
+func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*providerMeta).client
+	if err := c.DeleteProject(ctx, d.Id()); err != nil && !errorIsNotFound(err) {
+		return diag.FromErr(fmt.Errorf("failed to delete project: %v", err))
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+// resourceProjectImport imports a project by its name, which is also its ID
+func resourceProjectImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*providerMeta).client
+
+	project, err := c.GetProject(ctx, d.Id())
+	if err != nil {
+		return []*schema.ResourceData{}, fmt.Errorf("failed to get project: %v", err)
+	}
+
+	d.SetId(project.ID)
+	if err := setResourceDataFromProject(d, *project); err != nil {
+		return []*schema.ResourceData{}, fmt.Errorf("failed to set project from API response to terraform state: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func getProjectAttributesFromResource(d *schema.ResourceData) client.ProjectAttributes {
+	return client.ProjectAttributes{
+		Description: d.Get("description").(string),
+	}
+}
+
+func setResourceDataFromProject(d *schema.ResourceData, project client.Project) error {
+	if err := d.Set("project_name", project.ID); err != nil {
+		return fmt.Errorf("unable to set project_name resource field: %v", err)
+	}
+	if err := d.Set("description", project.Attributes.Description); err != nil {
+		return fmt.Errorf("unable to set description resource field: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestAccProject(t *testing.T) {
+	var project client.Project
+
+	projectConfig := `
+resource "lightstep_project" "test" {
+  project_name = "terraform-provider-tests-project"
+  description  = "Created by the acceptance tests"
+}
+`
+
+	updatedProjectConfig := `
+resource "lightstep_project" "test" {
+  project_name = "terraform-provider-tests-project"
+}
+`
+
+	resourceName := "lightstep_project.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: projectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectExists(resourceName, &project),
+					resource.TestCheckResourceAttr(resourceName, "project_name", "terraform-provider-tests-project"),
+					resource.TestCheckResourceAttr(resourceName, "description", "Created by the acceptance tests"),
+				),
+			},
+			{
+				Config: updatedProjectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectExists(resourceName, &project),
+					resource.TestCheckResourceAttr(resourceName, "description", ""),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestProjectImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/checkout", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": {"id": "checkout", "type": "project", "attributes": {"description": "Services of the checkout team"}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	d := resourceProject().TestResourceData()
+	d.SetId("checkout")
+
+	imported, err := resourceProjectImport(context.Background(), d, meta)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.Equal(t, "checkout", imported[0].Id())
+	assert.Equal(t, "checkout", imported[0].Get("project_name"))
+	assert.Equal(t, "Services of the checkout team", imported[0].Get("description"))
+}
+
+func TestProjectReadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	d := resourceProject().TestResourceData()
+	d.SetId("checkout")
+
+	diags := resourceProjectRead(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	assert.Equal(t, "", d.Id())
+}
+
+func testAccCheckProjectExists(resourceName string, project *client.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		tfProject, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+		if tfProject.Primary.ID == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		c := testAccProvider.Meta().(*providerMeta).client
+		p, err := c.GetProject(context.Background(), tfProject.Primary.ID)
+		if err != nil {
+			return err
+		}
+		*project = *p
+		return nil
+	}
+}
+
+// confirms that projects created during test run have been destroyed
+func testAccProjectDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*providerMeta).client
+
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "lightstep_project" {
+			continue
+		}
+
+		_, err := c.GetProject(context.Background(), r.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("project with ID (%v) still exists", r.Primary.ID)
+		}
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if !ok || apiErr.GetStatusCode() != http.StatusNotFound {
+			return fmt.Errorf("could not check whether project with ID (%v) was destroyed: %v", r.Primary.ID, err)
+		}
+	}
+	return nil
+}
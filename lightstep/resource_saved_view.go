@@ -0,0 +1,268 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func resourceSavedView() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a saved view of the Lightstep trace explorer: a span query, narrowed by filters, over a time window.",
+		CreateContext: resourceSavedViewCreate,
+		ReadContext:   resourceSavedViewRead,
+		UpdateContext: resourceSavedViewUpdate,
+		DeleteContext: resourceSavedViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSavedViewImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the project in which to save the view.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the view.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Optional description of what the view is for.",
+			},
+			"query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentStreamQuery,
+				Description:      "The span query of the view, in the same format as a stream query, e.g. `service IN (\"api\")`.",
+			},
+			"normalized_query": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The query as stored by Lightstep after normalization. Read-only, changes to it never cause a diff.",
+			},
+			"filter": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Optional filters narrowing the spans matched by the query.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "The span attribute to filter on.",
+						},
+						"operand": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "eq",
+							ValidateFunc: validation.StringInSlice([]string{"eq", "neq", "contains", "regexp"}, false),
+							Description:  "How the attribute is compared to the value, one of `eq`, `neq`, `contains` or `regexp`. Defaults to `eq`.",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The value compared to the attribute.",
+						},
+					},
+				},
+			},
+			"time_window": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validatePositiveDuration,
+				DiffSuppressFunc: suppressEquivalentDuration,
+				Description:      "Optional time window of the view as a duration, e.g. `1h` to explore the spans of the last hour.",
+			},
+		},
+	}
+}
+
+func resourceSavedViewCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*providerMeta).client
+
+	attributes, err := getSavedViewAttributesFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	projectName := d.Get("project_name").(string)
+	view, err := c.CreateSavedView(ctx, projectName, attributes)
+	if err != nil {
+		if savedViewsUnavailable(err) {
+			return savedViewsUnavailableDiags(c.OrgName(), projectName, err)
+		}
+		return diag.FromErr(fmt.Errorf("failed to create saved view: %v", err))
+	}
+
+	d.SetId(view.ID)
+	return resourceSavedViewRead(ctx, d, m)
+}
+
+func resourceSavedViewRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*providerMeta).client
+	view, err := c.GetSavedView(ctx, d.Get("project_name").(string), d.Id())
+	if err != nil {
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if !ok {
+			return diag.FromErr(fmt.Errorf("failed to get saved view: %v", err))
+		}
+
+		if apiErr.GetStatusCode() == http.StatusNotFound {
+			d.SetId("")
+			return diags
+		}
+
+		return diag.FromErr(fmt.Errorf("failed to get saved view: %v", apiErr))
+	}
+
+	if err := setResourceDataFromSavedView(d, *view); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set saved view from API response to terraform state: %v", err))
+	}
+	return diags
+}
+
+func resourceSavedViewUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*providerMeta).client
+
+	attributes, err := getSavedViewAttributesFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := c.UpdateSavedView(ctx, d.Get("project_name").(string), d.Id(), attributes); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to update saved view: %v", err))
+	}
+	return resourceSavedViewRead(ctx, d, m)
+}
+
+func resourceSavedViewDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*providerMeta).client
+	if err := c.DeleteSavedView(ctx, d.Get("project_name").(string), d.Id()); err != nil && !errorIsNotFound(err) {
+		return diag.FromErr(fmt.Errorf("failed to delete saved view: %v", err))
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func resourceSavedViewImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*providerMeta).client
+
+	ids := strings.Split(d.Id(), ".")
+	if len(ids) != 2 {
+		return []*schema.ResourceData{}, fmt.Errorf("error importing lightstep_saved_view. Expecting an  ID formed as '<lightstep_project>.<saved_view_id>'. Got: %v", d.Id())
+	}
+
+	project, id := ids[0], ids[1]
+	view, err := c.GetSavedView(ctx, project, id)
+	if err != nil {
+		return []*schema.ResourceData{}, fmt.Errorf("failed to get saved view: %v", err)
+	}
+
+	d.SetId(id)
+	if err := d.Set("project_name", project); err != nil {
+		return []*schema.ResourceData{}, fmt.Errorf("unable to set project_name resource field: %v", err)
+	}
+	if err := setResourceDataFromSavedView(d, *view); err != nil {
+		return []*schema.ResourceData{}, fmt.Errorf("failed to set saved view from API response to terraform state: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// getSavedViewAttributesFromResource builds the saved view to send to the API. The query may
+// be built from values that are unknown at plan time, so it's validated here once it has been
+// fully interpolated.
+func getSavedViewAttributesFromResource(d *schema.ResourceData) (client.SavedViewAttributes, error) {
+	query := d.Get("query").(string)
+	if err := validateStreamQuery(query); err != nil {
+		return client.SavedViewAttributes{}, fmt.Errorf("invalid saved view query: %v", err)
+	}
+
+	attributes := client.SavedViewAttributes{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Query:       query,
+		TimeWindow:  d.Get("time_window").(string),
+	}
+	for _, f := range d.Get("filter").([]interface{}) {
+		filter := f.(map[string]interface{})
+		attributes.Filters = append(attributes.Filters, client.SavedViewFilter{
+			Key:     filter["key"].(string),
+			Operand: filter["operand"].(string),
+			Value:   filter["value"].(string),
+		})
+	}
+	return attributes, nil
+}
+
+func setResourceDataFromSavedView(d *schema.ResourceData, view client.SavedView) error {
+	if err := d.Set("name", view.Attributes.Name); err != nil {
+		return fmt.Errorf("unable to set name resource field: %v", err)
+	}
+	if err := d.Set("description", view.Attributes.Description); err != nil {
+		return fmt.Errorf("unable to set description resource field: %v", err)
+	}
+	// the API may store the query normalized, the differences in whitespace are suppressed in
+	// the diff
+	if err := d.Set("query", view.Attributes.Query); err != nil {
+		return fmt.Errorf("unable to set query resource field: %v", err)
+	}
+	if err := d.Set("normalized_query", view.Attributes.Query); err != nil {
+		return fmt.Errorf("unable to set normalized_query resource field: %v", err)
+	}
+	if err := d.Set("time_window", view.Attributes.TimeWindow); err != nil {
+		return fmt.Errorf("unable to set time_window resource field: %v", err)
+	}
+
+	var filters []interface{}
+	for _, f := range view.Attributes.Filters {
+		filters = append(filters, map[string]interface{}{
+			"key":     f.Key,
+			"operand": f.Operand,
+			"value":   f.Value,
+		})
+	}
+	if err := d.Set("filter", filters); err != nil {
+		return fmt.Errorf("unable to set filter resource field: %v", err)
+	}
+	return nil
+}
+
+// savedViewsUnavailable reports whether creating a saved view failed because the API doesn't
+// serve saved views, which is the case for organizations the feature isn't enabled for
+func savedViewsUnavailable(err error) bool {
+	apiErr, ok := err.(client.APIResponseCarrier)
+	if !ok {
+		return false
+	}
+	status := apiErr.GetStatusCode()
+	return status == http.StatusNotFound || status == http.StatusForbidden
+}
+
+func savedViewsUnavailableDiags(orgName string, projectName string, err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Saved views are not available",
+		Detail: fmt.Sprintf("The API doesn't serve saved views for project %v of organization %v: %v. "+
+			"Check that the project exists and that saved views are enabled for the organization.", projectName, orgName, err),
+	}}
+}
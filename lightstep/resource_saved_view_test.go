@@ -0,0 +1,212 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestAccSavedView(t *testing.T) {
+	var view client.SavedView
+
+	badQuery := `
+resource "lightstep_saved_view" "test" {
+  project_name = "` + testProject + `"
+  name         = "Checkout errors"
+  query        = "service IN (\"checkout\""
+}
+`
+
+	viewConfig := `
+resource "lightstep_saved_view" "test" {
+  project_name = "` + testProject + `"
+  name         = "Checkout errors"
+  description  = "Errors of the checkout service"
+  query        = "service IN (\"checkout\")"
+  time_window  = "1h"
+
+  filter {
+    key   = "error"
+    value = "true"
+  }
+}
+`
+
+	updatedViewConfig := `
+resource "lightstep_saved_view" "test" {
+  project_name = "` + testProject + `"
+  name         = "Slow checkout errors"
+  query        = "service IN (\"checkout\")"
+  time_window  = "24h"
+
+  filter {
+    key   = "error"
+    value = "true"
+  }
+
+  filter {
+    key     = "http.url"
+    operand = "contains"
+    value   = "/cart"
+  }
+}
+`
+
+	resourceName := "lightstep_saved_view.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSavedViewDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      badQuery,
+				ExpectError: regexp.MustCompile("invalid saved view query"),
+			},
+			{
+				Config: viewConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSavedViewExists(resourceName, &view),
+					resource.TestCheckResourceAttr(resourceName, "name", "Checkout errors"),
+					resource.TestCheckResourceAttr(resourceName, "query", `service IN ("checkout")`),
+					resource.TestCheckResourceAttrSet(resourceName, "normalized_query"),
+					resource.TestCheckResourceAttr(resourceName, "time_window", "1h"),
+					resource.TestCheckResourceAttr(resourceName, "filter.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "filter.0.operand", "eq"),
+				),
+			},
+			{
+				Config: updatedViewConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSavedViewExists(resourceName, &view),
+					resource.TestCheckResourceAttr(resourceName, "name", "Slow checkout errors"),
+					resource.TestCheckResourceAttr(resourceName, "description", ""),
+					resource.TestCheckResourceAttr(resourceName, "time_window", "24h"),
+					resource.TestCheckResourceAttr(resourceName, "filter.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "filter.1.operand", "contains"),
+				),
+			},
+			{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateVerify:   true,
+				ImportStateIdPrefix: fmt.Sprintf("%s.", testProject),
+			},
+		},
+	})
+}
+
+func TestSavedViewReadNormalizedQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/saved_views/v1", r.URL.Path)
+		_, err := w.Write([]byte(`{"data": {"id": "v1", "attributes": {"name": "Checkout errors", "query": "service  IN ( \"checkout\" )"}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	r := resourceSavedView()
+	d := r.TestResourceData()
+	d.SetId("v1")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+	require.NoError(t, d.Set("query", `service IN ("checkout")`))
+
+	diags := resourceSavedViewRead(context.Background(), d, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, `service  IN ( "checkout" )`, d.Get("query"), "the query is read from the API")
+
+	// the configured query only differs in whitespace, so it has no diff
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_name": "tacoman",
+		"name":         "Checkout errors",
+		"query":        `service IN ("checkout")`,
+	}), meta)
+	require.NoError(t, err)
+	if diff != nil {
+		assert.NotContains(t, diff.Attributes, "query")
+	}
+
+	// other changes to the query are planned
+	diff, err = r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_name": "tacoman",
+		"name":         "Checkout errors",
+		"query":        `service IN ("cart")`,
+	}), meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.Contains(t, diff.Attributes, "query")
+}
+
+func TestSavedViewUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/saved_views", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	d := resourceSavedView().TestResourceData()
+	require.NoError(t, d.Set("project_name", "tacoman"))
+	require.NoError(t, d.Set("name", "Checkout errors"))
+	require.NoError(t, d.Set("query", `service IN ("checkout")`))
+
+	diags := resourceSavedViewCreate(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Saved views are not available", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "organization blars")
+	assert.Equal(t, "", d.Id())
+}
+
+func testAccCheckSavedViewExists(resourceName string, view *client.SavedView) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		tfView, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+		if tfView.Primary.ID == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		c := testAccProvider.Meta().(*providerMeta).client
+		v, err := c.GetSavedView(context.Background(), testProject, tfView.Primary.ID)
+		if err != nil {
+			return err
+		}
+		*view = *v
+		return nil
+	}
+}
+
+// confirms that saved views created during test run have been destroyed
+func testAccSavedViewDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*providerMeta).client
+
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "lightstep_saved_view" {
+			continue
+		}
+
+		_, err := c.GetSavedView(context.Background(), testProject, r.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("saved view with ID (%v) still exists", r.Primary.ID)
+		}
+		apiErr, ok := err.(client.APIResponseCarrier)
+		if !ok || apiErr.GetStatusCode() != http.StatusNotFound {
+			return fmt.Errorf("could not check whether saved view with ID (%v) was destroyed: %v", r.Primary.ID, err)
+		}
+	}
+	return nil
+}
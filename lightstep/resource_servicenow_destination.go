@@ -67,7 +67,7 @@ func resourceServiceNowDestination() *schema.Resource {
 }
 
 func resourceServiceNowDestinationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	attrs := client.ServiceNowAttributes{
 		Name:            d.Get("destination_name").(string),
 		DestinationType: "servicenow",
@@ -93,7 +93,7 @@ func resourceServiceNowDestinationCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceServiceNowDestinationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
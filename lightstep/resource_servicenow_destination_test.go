@@ -120,7 +120,7 @@ func testAccCheckServiceNowDestinationExists(resourceName string, destination *c
 		}
 
 		// get destination from LS
-		client := testAccProvider.Meta().(*client.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		d, err := client.GetDestination(context.Background(), testProject, tfDestination.Primary.ID)
 		if err != nil {
 			return err
@@ -132,7 +132,7 @@ func testAccCheckServiceNowDestinationExists(resourceName string, destination *c
 }
 
 func testAccServiceNowDestinationDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 	for _, resource := range s.RootModule().Resources {
 		if resource.Type != "lightstep_servicenow_destination" {
 			continue
@@ -39,7 +39,7 @@ func resourceSlackDestination() *schema.Resource {
 func resourceSlackDestinationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	dest, err := c.GetDestination(ctx, d.Get("project_name").(string), d.Id())
 	if err != nil {
 		apiErr, ok := err.(client.APIResponseCarrier)
@@ -63,7 +63,7 @@ func resourceSlackDestinationRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceSlackDestinationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	attrs := client.SlackAttributes{
 		Channel:         d.Get("channel").(string),
 		DestinationType: "slack",
@@ -83,7 +83,7 @@ func resourceSlackDestinationCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceSlackDestinationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids, err := splitID(d.Id())
 	if err != nil {
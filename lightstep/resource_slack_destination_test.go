@@ -73,7 +73,7 @@ func testAccCheckSlackDestinationExists(resourceName string, destination *client
 		}
 
 		// get destination from LS
-		client := testAccProvider.Meta().(*client.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		d, err := client.GetDestination(context.Background(), testProject, tfDestination.Primary.ID)
 		if err != nil {
 			return err
@@ -85,7 +85,7 @@ func testAccCheckSlackDestinationExists(resourceName string, destination *client
 }
 
 func testAccSlackDestinationDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 	for _, r := range s.RootModule().Resources {
 		if r.Type != "lightstep_slack_destination" {
 			continue
@@ -2,16 +2,26 @@ package lightstep
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceStream() *schema.Resource {
@@ -23,6 +33,7 @@ func resourceStream() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceStreamImport,
 		},
+		CustomizeDiff: customdiff.All(validateStreamQueryDiff, validateStreamTimeRange),
 		Schema: map[string]*schema.Schema{
 			"project_name": {
 				Type:     schema.TypeString,
@@ -33,17 +44,84 @@ func resourceStream() *schema.Resource {
 				Required: true,
 			},
 			"query": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentStreamQuery,
+				ValidateFunc:     validateStreamQueryField,
+				Description:      "The query of the stream, matching values with the IN form, e.g. `service IN (\"api\")`. It is checked for balanced quotes and parentheses, `=`, `==` and `!=` operators and `IN` without a list of values in parentheses when planning. Leading and trailing whitespace is trimmed and whitespace differences outside of quoted values never cause a diff.",
+			},
+			"normalized_query": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The query as stored by Lightstep after normalization (e.g. with reordered clauses). Read-only, changes to it never cause a diff.",
 			},
 			"custom_data": {
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:             schema.TypeList,
+				Optional:         true,
+				DiffSuppressFunc: suppressReorderedCustomData,
 				Elem: &schema.Schema{
-					Type: schema.TypeMap,
+					Type:             schema.TypeMap,
+					ValidateFunc:     validateCustomDataURL,
+					DiffSuppressFunc: suppressReorderedCustomData,
+				},
+			},
+			"retention": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateStreamRetention,
+				DiffSuppressFunc: suppressEquivalentRetention,
+				Description:      "How long the stream is kept before it expires, as a number of days such as 30d or a duration such as 720h, or never. When not set, the stream keeps the retention it was given outside of Terraform, new streams never expire.",
+			},
+			"color": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateStreamColor,
+				DiffSuppressFunc: suppressEquivalentStreamColor,
+				Description:      "Color grouping the stream with others in the UI, a hex color such as `#3c6fd8` or one of `" + strings.Join(streamColorNames, "`, `") + "`. Defaults to the color picked by Lightstep, which removing the attribute keeps.",
+			},
+			"adopt_existing": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{adoptOverwrite, adoptImport}, false),
+				Description: "What to do when the stream is created while a stream of the project already has its `stream_name`. " +
+					"`overwrite` adopts the existing stream and updates it to match the configuration. " +
+					"`import` adopts the existing stream as is, so the next plan shows how it differs from the configuration. " +
+					"Both require the existing stream to have the same query, since changing it replaces the stream. " +
+					"By default a new stream is created. Changing it after the stream was created has no effect.",
+			},
+			"validate_time_range": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Time range over which the query of the stream is run when it's created or updated, so a query that isn't valid over it fails the apply. A stream that fails to validate once created is tainted and replaced on the next apply.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validation.IsRFC3339Time,
+							DiffSuppressFunc: suppressEquivalentTimestamp,
+							Description:      "Start of the range as an RFC3339 timestamp, e.g. 2023-06-01T00:00:00Z",
+						},
+						"end": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validation.IsRFC3339Time,
+							DiffSuppressFunc: suppressEquivalentTimestamp,
+							Description:      "End of the range as an RFC3339 timestamp, which must be after the start",
+						},
+					},
 				},
 			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the stream even if stream dashboards still include it, leaving them with a reference to a missing stream. By default deleting such a stream fails with the dashboards using it.",
+			},
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(15 * time.Second),
@@ -51,18 +129,99 @@ func resourceStream() *schema.Resource {
 	}
 }
 
+// validateStreamQueryDiff validates the query when planning as soon as it is known, including
+// queries interpolated from the known attributes of other resources
+func validateStreamQueryDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("query") {
+		return nil
+	}
+	if err := validateStreamResourceQuery(d.Get("query").(string)); err != nil {
+		return fmt.Errorf("invalid stream query: %v", err)
+	}
+	return nil
+}
+
+// validateStreamTimeRange is a CustomizeDiff function that checks that the validate_time_range
+// of the stream ends after it starts
+func validateStreamTimeRange(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	start, _ := d.Get("validate_time_range.0.start").(string)
+	end, _ := d.Get("validate_time_range.0.end").(string)
+	if start == "" || end == "" {
+		// no time range, or its timestamps aren't known until apply
+		return nil
+	}
+
+	startTime, startErr := time.Parse(time.RFC3339, start)
+	endTime, endErr := time.Parse(time.RFC3339, end)
+	if startErr == nil && endErr == nil && !endTime.After(startTime) {
+		return fmt.Errorf("validate_time_range: end %v must be after start %v", end, start)
+	}
+	return nil
+}
+
+// streamTimeRangeResolution is the resolution of the timeseries a stream is validated with,
+// only whether its query can be run over the range matters
+const streamTimeRangeResolution = time.Hour
+
+// validateStreamQueryOverTimeRange runs the query of the stream over its validate_time_range,
+// if it has one, and reports the API rejecting it as an error diagnostic on the attribute
+func validateStreamQueryOverTimeRange(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	timeRange, _ := d.Get("validate_time_range").([]interface{})
+	if len(timeRange) == 0 || timeRange[0] == nil {
+		return nil
+	}
+	tr := timeRange[0].(map[string]interface{})
+	path := cty.GetAttrPath("validate_time_range").IndexInt(0)
+
+	start, err := time.Parse(time.RFC3339, tr["start"].(string))
+	if err != nil {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid validate_time_range start", Detail: err.Error(), AttributePath: path.GetAttr("start")}}
+	}
+	end, err := time.Parse(time.RFC3339, tr["end"].(string))
+	if err != nil {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid validate_time_range end", Detail: err.Error(), AttributePath: path.GetAttr("end")}}
+	}
+
+	c := m.(*providerMeta).client
+	if _, err := c.GetStreamTimeseries(ctx, d.Get("project_name").(string), d.Id(), start, end, streamTimeRangeResolution); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Stream query failed to validate over validate_time_range",
+			Detail:        fmt.Sprintf("query %q of stream %v can't be run between %v and %v: %v", d.Get("query").(string), d.Id(), tr["start"], tr["end"], err),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
 func resourceStreamCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	// The query may be built from values that are unknown at plan time (e.g. attributes
+	// of resources that aren't created yet), so it is validated again once it has been fully
+	// interpolated.
+	if err := validateStreamResourceQuery(d.Get("query").(string)); err != nil {
+		return diag.FromErr(fmt.Errorf("invalid stream query: %v", err))
+	}
+
+	c := m.(*providerMeta).client
+	if mode := d.Get("adopt_existing").(string); mode != "" {
+		adopted, diags := adoptExistingStream(ctx, d, m, mode)
+		if adopted || diags.HasError() {
+			return diags
+		}
+	}
+
 	if err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		origQuery := d.Get("query").(string)
 		stream, err := c.CreateStream(
 			ctx,
 			d.Get("project_name").(string),
 			d.Get("stream_name").(string),
-			d.Get("query").(string),
+			strings.TrimSpace(origQuery),
 			d.Get("custom_data").([]interface{}),
+			d.Get("retention").(string),
+			d.Get("color").(string),
 		)
 		if err != nil {
 			// Fix until lock error is resolved
@@ -88,13 +247,60 @@ func resourceStreamCreate(ctx context.Context, d *schema.ResourceData, m interfa
 		return diag.FromErr(fmt.Errorf("failed to create stream: %v", err))
 	}
 
-	return diags
+	return append(diags, validateStreamQueryOverTimeRange(ctx, d, m)...)
+}
+
+// The ways a stream can adopt the existing stream of the same name when it's created
+const (
+	adoptOverwrite = "overwrite"
+	adoptImport    = "import"
+)
+
+// adoptExistingStream looks up the stream of the project with the stream_name, like the
+// stream_name of the lightstep_stream data source, and adopts it as the stream. With
+// adoptOverwrite it's updated to match the configuration, with adoptImport it's read as is.
+// Either way its query must match the configured one: it can't be updated, so a different
+// query would make the next plan replace the adopted stream.
+// It returns false if there is no such stream, in which case a new one should be created.
+func adoptExistingStream(ctx context.Context, d *schema.ResourceData, m interface{}, mode string) (bool, diag.Diagnostics) {
+	c := m.(*providerMeta).client
+	projectName := d.Get("project_name").(string)
+
+	streams, err := c.ListStreams(ctx, projectName)
+	if err != nil {
+		return false, diag.FromErr(fmt.Errorf("failed to list streams to adopt an existing one: %v", err))
+	}
+	existing, err := findStream(streams, d.Get("stream_name").(string), "")
+	if errors.As(err, &noStreamError{}) {
+		return false, nil
+	}
+	if err != nil {
+		return false, diag.FromErr(fmt.Errorf("can't adopt an existing stream in project %v: %v", projectName, err))
+	}
+
+	query := d.Get("query").(string)
+	if normalizeQueryWhitespace(existing.Attributes.Query) != normalizeQueryWhitespace(query) {
+		return false, diag.FromErr(fmt.Errorf(
+			"can't adopt stream %v since its query %q differs from %q and can't be updated, import or delete it instead",
+			existing.ID, existing.Attributes.Query, query))
+	}
+
+	d.SetId(existing.ID)
+	if mode == adoptImport {
+		// like an import, the state holds the stream as it is so the next plan shows the drift.
+		// The read keeps the configured query, the stream's is in normalized_query.
+		if diags := resourceStreamRead(ctx, d, m); diags.HasError() {
+			return true, diags
+		}
+		return true, validateStreamQueryOverTimeRange(ctx, d, m)
+	}
+	return true, resourceStreamUpdate(ctx, d, m)
 }
 
 func resourceStreamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	s, err := c.GetStream(ctx, d.Get("project_name").(string), d.Id())
 	if err != nil {
 		apiErr, isApiErr := err.(client.APIResponseCarrier)
@@ -122,7 +328,7 @@ func resourceStreamRead(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceStreamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	s := client.Stream{
 		Type: "stream",
@@ -131,6 +337,18 @@ func resourceStreamUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	s.Attributes.Name = d.Get("stream_name").(string)
 	s.Attributes.CustomData = client.CustomDataConvert(d.Get("custom_data").([]interface{}))
+	// retention is computed, so it only changes when the configuration sets it. Otherwise it's
+	// left out of the request to keep the retention set outside of Terraform.
+	if d.HasChange("retention") {
+		s.Attributes.Retention = d.Get("retention").(string)
+	}
+	s.Attributes.Color = d.Get("color").(string)
+
+	// the query can't change in place, so it's validated against the existing stream before
+	// anything is sent: a failure then leaves the stream and the stored validate_time_range as they were
+	if diags := validateStreamQueryOverTimeRange(ctx, d, m); diags.HasError() {
+		return diags
+	}
 
 	if _, err := c.UpdateStream(ctx, d.Get("project_name").(string), d.Id(), s); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to update stream: %v", err))
@@ -142,8 +360,26 @@ func resourceStreamUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 func resourceStreamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
-	if err := c.DeleteStream(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	c := m.(*providerMeta).client
+	projectName := d.Get("project_name").(string)
+	if !d.Get("force_destroy").(bool) {
+		dashboards, err := c.DashboardsReferencingStream(ctx, projectName, d.Id())
+		// the dashboards can't be listed if the project is already gone, and the stream with it
+		if err != nil && !errorIsNotFound(err) {
+			return diag.FromErr(fmt.Errorf("failed to check the dashboards using stream %v: %v", d.Id(), err))
+		}
+		if len(dashboards) > 0 {
+			var names []string
+			for _, dashboard := range dashboards {
+				names = append(names, fmt.Sprintf("%q (%v)", dashboard.Attributes.Name, dashboard.ID))
+			}
+			return diag.FromErr(fmt.Errorf(
+				"stream %v is still used by dashboards %v: remove it from them first or set force_destroy to delete it anyway",
+				d.Id(), strings.Join(names, ", ")))
+		}
+	}
+
+	if err := c.DeleteStream(ctx, projectName, d.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete stream: %v", err))
 	}
 
@@ -154,7 +390,7 @@ func resourceStreamDelete(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceStreamImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
@@ -176,6 +412,7 @@ func resourceStreamImport(ctx context.Context, d *schema.ResourceData, m interfa
 		return []*schema.ResourceData{}, fmt.Errorf("failed to set stream from API response to terraform state: %v", err)
 	}
 	d.Set("query", stream.Attributes.Query)
+	d.Set("force_destroy", false)
 
 	return []*schema.ResourceData{d}, nil
 }
@@ -185,6 +422,34 @@ func setResourceDataFromStream(d *schema.ResourceData, s client.Stream) error {
 		return fmt.Errorf("unable to set stream_name resource field: %v", err)
 	}
 
+	if err := d.Set("custom_data", flattenStreamCustomData(s)); err != nil {
+		return fmt.Errorf("unable to set custom_data resource field: %v", err)
+	}
+
+	retention := s.Attributes.Retention
+	if retention == "" {
+		retention = neverExpires
+	}
+	if err := d.Set("retention", retention); err != nil {
+		return fmt.Errorf("unable to set retention resource field: %v", err)
+	}
+
+	if err := d.Set("color", s.Attributes.Color); err != nil {
+		return fmt.Errorf("unable to set color resource field: %v", err)
+	}
+
+	// don't set query here to avoid backend normalization issue, the normalized query is
+	// surfaced separately instead
+	if err := d.Set("normalized_query", s.Attributes.Query); err != nil {
+		return fmt.Errorf("unable to set normalized_query resource field: %v", err)
+	}
+
+	return nil
+}
+
+// flattenStreamCustomData converts the custom data of a stream to the list of maps of the
+// custom_data attribute
+func flattenStreamCustomData(s client.Stream) []map[string]string {
 	// Convert custom_data to list
 	customData := []map[string]string{}
 
@@ -222,11 +487,285 @@ func setResourceDataFromStream(d *schema.ResourceData, s client.Stream) error {
 		customData = append(customData, d)
 	}
 
-	if err := d.Set("custom_data", customData); err != nil {
-		return fmt.Errorf("unable to set custom_data resource field: %v", err)
+	// the API returns a map, sort the entries so the state doesn't change on every read
+	sort.Slice(customData, func(i, j int) bool {
+		return customData[i]["name"] < customData[j]["name"]
+	})
+	return customData
+}
+
+// suppressReorderedCustomData ignores differences in the order of the custom_data entries,
+// which the API doesn't keep since it stores them by name
+func suppressReorderedCustomData(_, _, _ string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("custom_data")
+	return reflect.DeepEqual(sortedCustomData(o), sortedCustomData(n))
+}
+
+// sortedCustomData returns the entries of a custom_data value sorted by name
+func sortedCustomData(v interface{}) []map[string]string {
+	entries, _ := v.([]interface{})
+	customData := make([]map[string]string, 0, len(entries))
+	for _, e := range entries {
+		entry := map[string]string{}
+		fields, _ := e.(map[string]interface{})
+		for k, v := range fields {
+			entry[k] = fmt.Sprint(v)
+		}
+		customData = append(customData, entry)
+	}
+	sort.SliceStable(customData, func(i, j int) bool {
+		return customData[i]["name"] < customData[j]["name"]
+	})
+	return customData
+}
+
+// neverExpires is the retention of streams that don't expire
+const neverExpires = "never"
+
+// parseRetention parses a stream retention: "never" (zero), a number of days such as "30d",
+// or a duration such as "720h".
+func parseRetention(retention string) (time.Duration, error) {
+	if retention == neverExpires {
+		return 0, nil
+	}
+	duration, err := parseDuration(retention)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("expected \"never\", a number of days such as \"30d\" or a positive duration such as \"720h\", got %q", retention)
+	}
+	return duration, nil
+}
+
+func validateStreamRetention(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseRetention(v); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %v", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentRetention ignores differences between equal retentions written
+// differently, e.g. 30d and 720h
+func suppressEquivalentRetention(_, old, new string, _ *schema.ResourceData) bool {
+	if old == "" {
+		old = neverExpires
+	}
+	oldRetention, oldErr := parseRetention(old)
+	newRetention, newErr := parseRetention(new)
+	return oldErr == nil && newErr == nil && oldRetention == newRetention
+}
+
+// streamColorNames are the named colors streams can be grouped by, besides hex colors
+var streamColorNames = []string{"red", "orange", "yellow", "green", "teal", "blue", "purple", "pink", "gray"}
+
+var hexColorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validateStreamColor(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if hexColorRegexp.MatchString(v) {
+		return nil, nil
+	}
+	for _, name := range streamColorNames {
+		if strings.EqualFold(v, name) {
+			return nil, nil
+		}
+	}
+	return nil, []error{fmt.Errorf("expected %s to be a hex color such as \"#3c6fd8\" or one of %q, got %q", k, streamColorNames, v)}
+}
+
+// suppressEquivalentStreamColor ignores case differences, the API may return hex colors and
+// color names in another case than they were sent in
+func suppressEquivalentStreamColor(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// validateStreamQuery checks that a stream query is well-formed: it must be non-empty
+// and have balanced double quotes and parentheses. Parentheses inside quoted values
+// are ignored. Detailed validation of the query language is left to the server.
+func validateStreamQuery(query string) error {
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("query must not be empty")
+	}
+
+	var (
+		depth    int
+		inQuotes bool
+		escaped  bool
+	)
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			// ignore everything inside a quoted value
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unexpected ')' in query %q", query)
+			}
+		}
+	}
+
+	if inQuotes {
+		return fmt.Errorf("unterminated quoted value in query %q", query)
+	}
+	if depth != 0 {
+		return fmt.Errorf("unbalanced parentheses in query %q", query)
 	}
+	return nil
+}
 
-	// don't set query here to avoid backend normalization issue
+var (
+	// streamQueryOperator matches the operators containing =, stream queries match values with IN
+	streamQueryOperator = regexp.MustCompile(`[<>!]?=+`)
+	// streamQueryIN matches the IN keywords of a query and what follows them
+	streamQueryIN = regexp.MustCompile(`(?i)(?:^|[\s)])IN\b\s*(.?)`)
+)
+
+// validateStreamResourceQuery checks the query of a lightstep_stream: on top of
+// validateStreamQuery, it rejects equality operators and IN without a list of values in
+// parentheses, the usual mistakes when writing the IN form, e.g. service IN ("api").
+// Operators inside quoted values are ignored.
+func validateStreamResourceQuery(query string) error {
+	if err := validateStreamQuery(query); err != nil {
+		return err
+	}
 
+	unquoted := unquotedQuery(query)
+	for _, op := range streamQueryOperator.FindAllString(unquoted, -1) {
+		switch op {
+		case "=", "==", "!=":
+			return fmt.Errorf("unexpected %q in query %q: match values with IN, e.g. service IN (\"api\")", op, query)
+		}
+	}
+	for _, m := range streamQueryIN.FindAllStringSubmatch(unquoted, -1) {
+		if m[1] != "(" {
+			return fmt.Errorf("expected a list of values in parentheses after IN in query %q, e.g. service IN (\"api\")", query)
+		}
+	}
 	return nil
 }
+
+// unquotedQuery returns the query with the contents of its quoted values left out
+func unquotedQuery(query string) string {
+	var (
+		b        strings.Builder
+		inQuotes bool
+		escaped  bool
+	)
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			b.WriteRune(r)
+		case !inQuotes:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validateStreamQueryField validates the query of a stream when planning; queries that
+// are only known when applying are validated on create
+func validateStreamQueryField(i interface{}, k string) ([]string, []error) {
+	query, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if err := validateStreamResourceQuery(query); err != nil {
+		return nil, []error{fmt.Errorf("%s: %v", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentStreamQuery ignores whitespace differences between queries that the
+// server normalizes away, e.g. `service IN ( "api" )` and `service IN ("api")`
+func suppressEquivalentStreamQuery(_, old, new string, _ *schema.ResourceData) bool {
+	return normalizeQueryWhitespace(old) == normalizeQueryWhitespace(new)
+}
+
+// normalizeQueryWhitespace trims a query, collapses the runs of whitespace between its tokens
+// into a single space and drops the whitespace next to parentheses and commas. Quoted values
+// are kept as is.
+func normalizeQueryWhitespace(query string) string {
+	var (
+		b        strings.Builder
+		inQuotes bool
+		escaped  bool
+		space    bool
+	)
+	for _, r := range strings.TrimSpace(query) {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			// whitespace is significant inside a quoted value
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		}
+
+		if space && r != ')' && r != ',' {
+			if last := lastRune(b.String()); last != '(' && last != ',' {
+				b.WriteRune(' ')
+			}
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// ValidateStreamQuery checks that a stream query is well-formed, for the tools generating
+// lightstep_stream configuration
+func ValidateStreamQuery(query string) error {
+	return validateStreamQuery(query)
+}
+
+// validateCustomDataURL checks that the "url" key of a custom_data entry, if present, is an
+// absolute http or https URL. The other keys are free-form and aren't validated.
+func validateCustomDataURL(i interface{}, k string) ([]string, []error) {
+	customData, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be map", k)}
+	}
+
+	raw, ok := customData["url"]
+	if !ok {
+		return nil, nil
+	}
+	v, ok := raw.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s.url to be string", k)}
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, []error{fmt.Errorf("expected %s.url to be an absolute http or https URL, got %q", k, v)}
+	}
+	return nil, nil
+}
@@ -21,6 +21,7 @@ func resourceStreamCondition() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceStreamConditionImport,
 		},
+		CustomizeDiff: validateStreamReferences("stream_id"),
 		Schema: map[string]*schema.Schema{
 			"project_name": {
 				Type:     schema.TypeString,
@@ -50,7 +51,7 @@ func resourceStreamCondition() *schema.Resource {
 func resourceStreamConditionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	condition, err := c.CreateStreamCondition(
 		ctx,
@@ -74,7 +75,7 @@ func resourceStreamConditionCreate(ctx context.Context, d *schema.ResourceData,
 func resourceStreamConditionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	condition, err := c.GetStreamCondition(ctx, d.Get("project_name").(string), d.Id())
 	if err != nil {
 		apiErr, ok := err.(client.APIResponseCarrier)
@@ -82,7 +83,7 @@ func resourceStreamConditionRead(ctx context.Context, d *schema.ResourceData, m
 			return diag.FromErr(fmt.Errorf("failed to get stream condition: %v", err))
 		}
 
-		if apiErr.GetHTTPResponse().StatusCode == http.StatusNotFound {
+		if apiErr.GetStatusCode() == http.StatusNotFound {
 			d.SetId("")
 			return diags
 		}
@@ -100,18 +101,19 @@ func resourceStreamConditionRead(ctx context.Context, d *schema.ResourceData, m
 func resourceStreamConditionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
-	if err := c.DeleteStreamCondition(ctx, d.Get("project_name").(string), d.Id()); err != nil {
+	c := m.(*providerMeta).client
+	if err := c.DeleteStreamCondition(ctx, d.Get("project_name").(string), d.Id()); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete stream condition: %v", err))
 	}
 
+	d.SetId("")
 	return diags
 }
 
 func resourceStreamConditionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 	attrs := client.StreamConditionAttributes{
 		Name:               d.Get("condition_name").(string),
 		EvaluationWindowMS: d.Get("evaluation_window_ms").(int),
@@ -131,7 +133,7 @@ func resourceStreamConditionUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceStreamConditionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
@@ -0,0 +1,35 @@
+package lightstep
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+)
+
+func TestStreamConditionReadTransportError(t *testing.T) {
+	// a closed server refuses connections, so the request fails without a response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "0")
+
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+	d := resourceStreamCondition().TestResourceData()
+	d.SetId("hi")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+
+	var diags diag.Diagnostics
+	require.NotPanics(t, func() {
+		diags = resourceStreamConditionRead(context.Background(), d, meta)
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "failed to get stream condition")
+	assert.Equal(t, "hi", d.Id(), "the condition is kept in the state")
+}
@@ -21,12 +21,17 @@ func resourceStreamDashboard() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceStreamDashboardImport,
 		},
+		CustomizeDiff:      validateStreamReferences("stream_ids"),
 		DeprecationMessage: "resource_stream_dashboard is no longer supported. Please migrate to resource_metric_dashboard with span queries.",
 		Schema: map[string]*schema.Schema{
 			"dashboard_name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"dashboard_description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"project_name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -43,13 +48,14 @@ func resourceStreamDashboard() *schema.Resource {
 }
 
 func resourceStreamDashboardCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 
 	projectName := d.Get("project_name").(string)
 	dashboardName := d.Get("dashboard_name").(string)
+	dashboardDescription := d.Get("dashboard_description").(string)
 	streams := streamIDsToStreams(d.Get("stream_ids").([]interface{}))
 
-	dashboard, err := client.CreateDashboard(ctx, projectName, dashboardName, streams)
+	dashboard, err := client.CreateDashboard(ctx, projectName, dashboardName, dashboardDescription, streams)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to create stream dashboard for [project: %v; dashboard: %v]: %v", projectName, dashboardName, err))
 	}
@@ -61,7 +67,7 @@ func resourceStreamDashboardCreate(ctx context.Context, d *schema.ResourceData,
 func resourceStreamDashboardRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	projectName := d.Get("project_name").(string)
 	resourceId := d.Id()
@@ -74,6 +80,14 @@ func resourceStreamDashboardRead(ctx context.Context, d *schema.ResourceData, m
 		}
 
 		if apiErr.GetStatusCode() == http.StatusNotFound {
+			if m.(*providerMeta).strictRead {
+				return diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  "Stream dashboard not found",
+					Detail: fmt.Sprintf("Stream dashboard %v was not found in project %v. It is kept in the state because strict_read is set, "+
+						"remove it with terraform state rm if it was deleted.", resourceId, projectName),
+				}}
+			}
 			d.SetId("")
 			return diags
 		}
@@ -91,13 +105,14 @@ func resourceStreamDashboardRead(ctx context.Context, d *schema.ResourceData, m
 func resourceStreamDashboardUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 	projectName := d.Get("project_name").(string)
 	dashboardName := d.Get("dashboard_name").(string)
+	dashboardDescription := d.Get("dashboard_description").(string)
 	resourceId := d.Id()
 	streams := streamIDsToStreams(d.Get("stream_ids").([]interface{}))
 
-	if _, err := client.UpdateDashboard(ctx, projectName, dashboardName, streams, resourceId); err != nil {
+	if _, err := client.UpdateDashboard(ctx, projectName, dashboardName, dashboardDescription, streams, resourceId); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to update stream condition for [project: %v; dashboard_name: %v, resource_id: %v]: %v", projectName, dashboardName, resourceId, err))
 	}
 
@@ -107,11 +122,11 @@ func resourceStreamDashboardUpdate(ctx context.Context, d *schema.ResourceData,
 func resourceStreamDashboardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 	projectName := d.Get("project_name").(string)
 	resourceId := d.Id()
 
-	if err := client.DeleteDashboard(ctx, projectName, resourceId); err != nil {
+	if err := client.DeleteDashboard(ctx, projectName, resourceId); err != nil && !errorIsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("failed to delete stream dashboard for [project: %v; resource_id: %v]: %v", projectName, resourceId, err))
 	}
 
@@ -120,7 +135,7 @@ func resourceStreamDashboardDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceStreamDashboardImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	client := m.(*client.Client)
+	client := m.(*providerMeta).client
 
 	resourceId := d.Id()
 	ids := strings.Split(resourceId, ".")
@@ -151,11 +166,20 @@ func setResourceDataFromStreamDashboard(d *schema.ResourceData, dashboard client
 		return fmt.Errorf("unable to set dashboard_name resource field: %v", err)
 	}
 
+	if err := d.Set("dashboard_description", dashboard.Attributes.Description); err != nil {
+		return fmt.Errorf("unable to set dashboard_description resource field: %v", err)
+	}
+
 	var streamIDs []string
 	for _, stream := range dashboard.Attributes.Streams {
 		streamIDs = append(streamIDs, stream.ID)
 	}
 
+	// duplicates are only sent once and the API may reorder the streams, so the configured IDs
+	// are kept as long as they are the same streams
+	if sameStreamIDs(uniqueStreamIDs(d.Get("stream_ids").([]interface{})), streamIDs) {
+		return nil
+	}
 	if err := d.Set("stream_ids", streamIDs); err != nil {
 		return fmt.Errorf("unable to set stream_ids resource field: %v", err)
 	}
@@ -163,11 +187,42 @@ func setResourceDataFromStreamDashboard(d *schema.ResourceData, dashboard client
 	return nil
 }
 
+// streamIDsToStreams converts the stream IDs to the streams of a dashboard, without duplicates
 func streamIDsToStreams(ids []interface{}) []client.Stream {
 	streams := []client.Stream{}
 
-	for _, id := range ids {
-		streams = append(streams, client.Stream{ID: id.(string)})
+	for _, id := range uniqueStreamIDs(ids) {
+		streams = append(streams, client.Stream{ID: id})
 	}
 	return streams
 }
+
+// uniqueStreamIDs returns the stream IDs without duplicates, in order
+func uniqueStreamIDs(ids []interface{}) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if !seen[id.(string)] {
+			seen[id.(string)] = true
+			unique = append(unique, id.(string))
+		}
+	}
+	return unique
+}
+
+// sameStreamIDs reports whether the lists of unique stream IDs have the same IDs, in any order
+func sameStreamIDs(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := map[string]bool{}
+	for _, id := range a {
+		ids[id] = true
+	}
+	for _, id := range b {
+		if !ids[id] {
+			return false
+		}
+	}
+	return true
+}
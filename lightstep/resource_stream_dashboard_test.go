@@ -0,0 +1,270 @@
+package lightstep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstep/terraform-provider-lightstep/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccStreamDashboardDescription(t *testing.T) {
+	var dashboard client.Dashboard
+
+	streamDashboardConfig := func(description string) string {
+		return `
+resource "lightstep_stream" "beemo" {
+  project_name = "` + testProject + `"
+  stream_name  = "Beemo Errors"
+  query        = "service IN (\"beemo\") AND \"error\" IN (\"true\")"
+}
+
+resource "lightstep_stream_dashboard" "test" {
+  project_name          = "` + testProject + `"
+  dashboard_name        = "Acceptance Test Stream Dashboard"
+  ` + description + `
+  stream_ids            = [lightstep_stream.beemo.id]
+}
+`
+	}
+
+	resourceName := "lightstep_stream_dashboard.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: streamDashboardConfig(`dashboard_description = "Errors for the beemo service"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_description", "Errors for the beemo service"),
+				),
+			},
+			{
+				Config: streamDashboardConfig(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamDashboardExists(resourceName, &dashboard),
+					resource.TestCheckResourceAttr(resourceName, "dashboard_description", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamDashboardDuplicateStreamIDs(t *testing.T) {
+	var dashboard client.Dashboard
+
+	config := `
+resource "lightstep_stream" "beemo" {
+  project_name = "` + testProject + `"
+  stream_name  = "Beemo Errors"
+  query        = "service IN (\"beemo\") AND \"error\" IN (\"true\")"
+}
+
+resource "lightstep_stream" "bmo" {
+  project_name = "` + testProject + `"
+  stream_name  = "BMO Errors"
+  query        = "service IN (\"bmo\") AND \"error\" IN (\"true\")"
+}
+
+resource "lightstep_stream_dashboard" "duplicates" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Stream Dashboard with Duplicates"
+  stream_ids     = [lightstep_stream.beemo.id, lightstep_stream.bmo.id, lightstep_stream.beemo.id]
+}
+`
+
+	resourceName := "lightstep_stream_dashboard.duplicates"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamDashboardExists(resourceName, &dashboard),
+					func(*terraform.State) error {
+						if len(dashboard.Attributes.Streams) != 2 {
+							return fmt.Errorf("expected the dashboard to have 2 streams, got %d", len(dashboard.Attributes.Streams))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the duplicate isn't a change
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestStreamIDsToStreams(t *testing.T) {
+	streams := streamIDsToStreams([]interface{}{"b", "a", "b", "c", "a"})
+	assert.Equal(t, []client.Stream{{ID: "b"}, {ID: "a"}, {ID: "c"}}, streams)
+
+	assert.True(t, sameStreamIDs([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(t, sameStreamIDs([]string{"a", "b"}, []string{"a"}))
+	assert.False(t, sameStreamIDs([]string{"a", "b"}, []string{"a", "c"}))
+}
+
+func TestAccStreamReferencesValidation(t *testing.T) {
+	var dashboard client.Dashboard
+
+	config := func(streamIDs string, conditionStreamID string) string {
+		return `
+provider "lightstep" {
+  validate_references = true
+}
+
+resource "lightstep_stream" "beemo" {
+  project_name = "` + testProject + `"
+  stream_name  = "Beemo Errors"
+  query        = "service IN (\"beemo\") AND \"error\" IN (\"true\")"
+}
+
+resource "lightstep_stream_dashboard" "test" {
+  project_name   = "` + testProject + `"
+  dashboard_name = "Acceptance Test Stream Dashboard with References"
+  stream_ids     = [` + streamIDs + `]
+}
+
+resource "lightstep_stream_condition" "test" {
+  project_name         = "` + testProject + `"
+  condition_name       = "Beemo errors"
+  expression           = "err > 0.4"
+  evaluation_window_ms = 300000
+  stream_id            = ` + conditionStreamID + `
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				// references to streams created in the same apply are checked once they exist
+				Config: config("lightstep_stream.beemo.id", "lightstep_stream.beemo.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamDashboardExists("lightstep_stream_dashboard.test", &dashboard),
+					resource.TestCheckResourceAttr("lightstep_stream_dashboard.test", "stream_ids.#", "1"),
+				),
+			},
+			{
+				Config:      config(`lightstep_stream.beemo.id, "missing-stream-1", "missing-stream-2"`, "lightstep_stream.beemo.id"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`stream_ids\[1\]: stream "missing-stream-1" does not exist in project ` + testProject + `; stream_ids\[2\]: stream "missing-stream-2" does not exist in project ` + testProject),
+			},
+			{
+				Config:      config("lightstep_stream.beemo.id", `"missing-stream-3"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`stream_id: stream "missing-stream-3" does not exist in project ` + testProject),
+			},
+		},
+	})
+}
+
+func testAccCheckStreamDashboardExists(resourceName string, dashboard *client.Dashboard) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		tfDashboard, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if tfDashboard.Primary.ID == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		c := testAccProvider.Meta().(*providerMeta).client
+		dash, err := c.GetDashboard(context.Background(), testProject, tfDashboard.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		*dashboard = *dash
+		return nil
+	}
+}
+
+func testAccStreamDashboardDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*providerMeta).client
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "lightstep_stream_dashboard" {
+			continue
+		}
+
+		d, err := conn.GetDashboard(context.Background(), testProject, r.Primary.ID)
+		if err == nil {
+			if d.ID == r.Primary.ID {
+				return fmt.Errorf("stream dashboard with ID (%v) still exists.", r.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func TestStreamDashboardReadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/dashboards/hi", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+
+	for _, strictRead := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict_read=%v", strictRead), func(t *testing.T) {
+			meta := &providerMeta{client: client.NewClient("api", "blars", "staging"), strictRead: strictRead}
+			d := resourceStreamDashboard().TestResourceData()
+			d.SetId("hi")
+			require.NoError(t, d.Set("project_name", "tacoman"))
+
+			diags := resourceStreamDashboardRead(context.Background(), d, meta)
+			if strictRead {
+				require.True(t, diags.HasError())
+				assert.Equal(t, "Stream dashboard not found", diags[0].Summary)
+				assert.Equal(t, "hi", d.Id(), "the dashboard is kept in the state")
+			} else {
+				assert.False(t, diags.HasError())
+				assert.Equal(t, "", d.Id(), "the dashboard is removed from the state")
+			}
+		})
+	}
+}
+
+func TestStreamDashboardReadTransportError(t *testing.T) {
+	// a closed server refuses connections, so the request fails without a response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LIGHTSTEP_API_RETRY_MAX", "0")
+
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+	d := resourceStreamDashboard().TestResourceData()
+	d.SetId("hi")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+
+	var diags diag.Diagnostics
+	require.NotPanics(t, func() {
+		diags = resourceStreamDashboardRead(context.Background(), d, meta)
+	})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "failed to get stream dashboard")
+	assert.Equal(t, "hi", d.Id(), "the dashboard is kept in the state")
+}
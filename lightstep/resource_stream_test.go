@@ -3,13 +3,23 @@ package lightstep
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/lightstep/terraform-provider-lightstep/client"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccStream(t *testing.T) {
@@ -64,7 +74,7 @@ resource "lightstep_stream" "aggie_errors" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckStreamExists("lightstep_stream.aggie_errors", &stream),
 				),
-				ExpectError: regexp.MustCompile("InvalidArgument"),
+				ExpectError: regexp.MustCompile("match values with IN"),
 			},
 			{
 				Config: streamConfig,
@@ -173,6 +183,794 @@ func TestAccStreamQueryNormalization(t *testing.T) {
 	})
 }
 
+func TestAccStreamNormalizedQuery(t *testing.T) {
+	var stream client.Stream
+
+	// the server sorts the clauses of the query, so "error" ends up after service
+	const query = `"error" IN ("true") AND service IN ("api")`
+	config := `
+resource "lightstep_stream" "normalized" {
+  project_name = "` + testProject + `"
+  stream_name  = "Normalized Query"
+  query        = "\"error\" IN (\"true\") AND service IN (\"api\")"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists("lightstep_stream.normalized", &stream),
+					resource.TestCheckResourceAttr("lightstep_stream.normalized", "query", query),
+					resource.TestCheckResourceAttrWith("lightstep_stream.normalized", "normalized_query", func(value string) error {
+						if value == query {
+							return fmt.Errorf("expected the normalized query to differ from %q", query)
+						}
+						if value != stream.Attributes.Query {
+							return fmt.Errorf("expected the normalized query to be the stored query %q, got %q", stream.Attributes.Query, value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// the normalized query is read-only and doesn't cause a diff
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStreamRetention(t *testing.T) {
+	var stream client.Stream
+
+	config := func(retention string) string {
+		return `
+resource "lightstep_stream" "expiring" {
+  project_name = "` + testProject + `"
+  stream_name  = "Expiring Stream"
+  query        = "service IN (\"api\")"
+  ` + retention + `
+}
+`
+	}
+
+	resourceName := "lightstep_stream.expiring"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`retention = "30d"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+					resource.TestCheckResourceAttr(resourceName, "retention", "30d"),
+				),
+			},
+			{
+				// the same retention written as a duration isn't a change
+				Config:   config(`retention = "720h"`),
+				PlanOnly: true,
+			},
+			{
+				// a retention that isn't configured is kept
+				Config:   config(""),
+				PlanOnly: true,
+			},
+			{
+				Config: config(`retention = "never"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+					resource.TestCheckResourceAttr(resourceName, "retention", "never"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStreamCustomDataOrder(t *testing.T) {
+	var stream client.Stream
+
+	streamConfig := func(first, second string) string {
+		return fmt.Sprintf(`
+resource "lightstep_stream" "custom_data_order" {
+  project_name = "%s"
+  stream_name  = "Custom data order"
+  query        = "service IN (\"api\")"
+  custom_data = [
+    {
+      "name" = "%s"
+      "url"  = "https://www.lightstep.com/%[2]s"
+    },
+    {
+      "name" = "%s"
+      "url"  = "https://www.lightstep.com/%[3]s"
+    },
+  ]
+}
+`, testProject, first, second)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: streamConfig("runbook", "dashboard"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists("lightstep_stream.custom_data_order", &stream),
+					resource.TestCheckResourceAttr("lightstep_stream.custom_data_order", "custom_data.#", "2"),
+					resource.TestCheckResourceAttr("lightstep_stream.custom_data_order", "custom_data.0.name", "dashboard"),
+					resource.TestCheckResourceAttr("lightstep_stream.custom_data_order", "custom_data.1.name", "runbook"),
+				),
+			},
+			{
+				// reordering the entries doesn't change the stream
+				Config:   streamConfig("dashboard", "runbook"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStreamAdoptExisting(t *testing.T) {
+	for _, mode := range []string{adoptOverwrite, adoptImport} {
+		t.Run(mode, func(t *testing.T) {
+			var stream, existing client.Stream
+
+			streamName := "Adopted Stream (" + mode + ")"
+			config := `
+resource "lightstep_stream" "adopted" {
+  project_name   = "` + testProject + `"
+  stream_name    = "` + streamName + `"
+  query          = "service IN (\"api\")"
+  retention      = "30d"
+  adopt_existing = "` + mode + `"
+}
+`
+
+			resourceName := "lightstep_stream.adopted"
+			resource.Test(t, resource.TestCase{
+				PreCheck:     func() { testAccPreCheck(t) },
+				Providers:    testAccProviders,
+				CheckDestroy: testAccStreamDestroy,
+				Steps: []resource.TestStep{
+					{
+						// the stream exists before it's in the configuration
+						PreConfig: func() {
+							c := client.NewClient(os.Getenv("LIGHTSTEP_API_KEY"), os.Getenv("LIGHTSTEP_ORG"), os.Getenv("LIGHTSTEP_ENV"))
+							var err error
+							existing, err = c.CreateStream(context.Background(), testProject, streamName, `service IN ("api")`, nil, "", "")
+							require.NoError(t, err)
+						},
+						Config: config,
+						Check: resource.ComposeTestCheckFunc(
+							testAccCheckStreamExists(resourceName, &stream),
+							func(*terraform.State) error {
+								if stream.ID != existing.ID {
+									return fmt.Errorf("expected the existing stream %v to be adopted, got %v", existing.ID, stream.ID)
+								}
+								return nil
+							},
+						),
+						// the existing stream never expires, which only an import leaves as is
+						ExpectNonEmptyPlan: mode == adoptImport,
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAdoptExistingStream(t *testing.T) {
+	var updates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/public/v0.2/blars/projects/tacoman/streams":
+			_, err := w.Write([]byte(`{"data": [
+				{"id": "s1", "attributes": {"name": "Checkout", "query": "service IN (\"checkout\")"}},
+				{"id": "s2", "attributes": {"name": "Copy", "query": "service IN (\"a\")"}},
+				{"id": "s3", "attributes": {"name": "Copy", "query": "service IN (\"b\")"}}
+			]}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/public/v0.2/blars/projects/tacoman/streams/s1":
+			if r.Method == http.MethodPatch {
+				updates++
+			}
+			_, err := w.Write([]byte(`{"data": {"id": "s1", "attributes": {"name": "Checkout", "query": "service IN (\"checkout\")"}}}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	stream := func(name string, query string) *schema.ResourceData {
+		d := resourceStream().TestResourceData()
+		require.NoError(t, d.Set("project_name", "tacoman"))
+		require.NoError(t, d.Set("stream_name", name))
+		require.NoError(t, d.Set("query", query))
+		require.NoError(t, d.Set("retention", "30d"))
+		return d
+	}
+
+	d := stream("Payments", `service IN ("payments")`)
+	adopted, diags := adoptExistingStream(context.Background(), d, meta, adoptOverwrite)
+	require.False(t, diags.HasError())
+	assert.False(t, adopted, "a stream is created if none has the name")
+
+	d = stream("Checkout", `service  IN ("checkout")`)
+	adopted, diags = adoptExistingStream(context.Background(), d, meta, adoptOverwrite)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.True(t, adopted)
+	assert.Equal(t, "s1", d.Id())
+	assert.Equal(t, 1, updates, "the existing stream is updated to match the configuration")
+
+	d = stream("Checkout", `service IN ( "checkout" )`)
+	adopted, diags = adoptExistingStream(context.Background(), d, meta, adoptImport)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.True(t, adopted)
+	assert.Equal(t, "s1", d.Id())
+	assert.Equal(t, 1, updates, "the existing stream is imported as is")
+	assert.Equal(t, "never", d.Get("retention"), "the next plan shows the drift")
+	assert.Equal(t, `service IN ( "checkout" )`, d.Get("query"), "the configured query is kept")
+	assert.Equal(t, `service IN ("checkout")`, d.Get("normalized_query"))
+
+	// a different query can't be updated, so the next plan would replace the adopted stream
+	for _, mode := range []string{adoptOverwrite, adoptImport} {
+		d = stream("Checkout", `service IN ("checkout") AND error = true`)
+		adopted, diags = adoptExistingStream(context.Background(), d, meta, mode)
+		require.True(t, diags.HasError(), mode)
+		assert.False(t, adopted, mode)
+		assert.Contains(t, diags[0].Summary, "can't adopt stream s1 since its query", mode)
+		assert.Equal(t, 1, updates, mode)
+	}
+
+	d = stream("Copy", `service IN ("a")`)
+	_, diags = adoptExistingStream(context.Background(), d, meta, adoptImport)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "2 streams named \"Copy\"")
+}
+
+func TestStreamDeleteReferencedByDashboard(t *testing.T) {
+	var deletes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/public/v0.2/blars/projects/tacoman/dashboards":
+			_, err := w.Write([]byte(`{"data": [
+				{"id": "d1", "attributes": {"name": "Checkout", "streams": [{"id": "s1"}]}},
+				{"id": "d2", "attributes": {"name": "Payments", "streams": [{"id": "s2"}]}}
+			]}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/public/v0.2/blars/projects/tacoman/streams/s1" && r.Method == http.MethodDelete:
+			deletes++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	d := resourceStream().TestResourceData()
+	d.SetId("s1")
+	require.NoError(t, d.Set("project_name", "tacoman"))
+
+	diags := resourceStreamDelete(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, `stream s1 is still used by dashboards "Checkout" (d1)`)
+	assert.Equal(t, 0, deletes)
+
+	require.NoError(t, d.Set("force_destroy", true))
+	diags = resourceStreamDelete(context.Background(), d, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, 1, deletes)
+}
+
+func TestStreamValidateTimeRange(t *testing.T) {
+	timeseriesStatus := http.StatusOK
+	var timeseriesQueries []string
+	updates := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public/v0.2/blars/projects/tacoman/streams/s1/timeseries":
+			timeseriesQueries = append(timeseriesQueries, r.URL.RawQuery)
+			w.WriteHeader(timeseriesStatus)
+			if timeseriesStatus != http.StatusOK {
+				_, err := w.Write([]byte(`{"errors": ["invalid query"]}`))
+				assert.NoError(t, err)
+				return
+			}
+			_, err := w.Write([]byte(`{"data": {"attributes": {"ops-counts": [3]}}}`))
+			assert.NoError(t, err)
+		case "/public/v0.2/blars/projects/tacoman/streams", "/public/v0.2/blars/projects/tacoman/streams/s1":
+			if r.Method == http.MethodPatch {
+				updates++
+			}
+			_, err := w.Write([]byte(`{"data": {"id": "s1", "attributes": {"name": "Checkout", "query": "service IN (\"checkout\")"}}}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	create := func() diag.Diagnostics {
+		r := resourceStream()
+		diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_name": "tacoman",
+			"stream_name":  "Checkout",
+			"query":        `service IN ("checkout")`,
+			"validate_time_range": []interface{}{map[string]interface{}{
+				"start": "2023-06-01T00:00:00Z",
+				"end":   "2023-06-02T00:00:00Z",
+			}},
+		}), meta)
+		require.NoError(t, err)
+		d, err := schema.InternalMap(r.Schema).Data(nil, diff)
+		require.NoError(t, err)
+		return resourceStreamCreate(context.Background(), d, meta)
+	}
+
+	require.False(t, create().HasError())
+	require.Len(t, timeseriesQueries, 1)
+	assert.Contains(t, timeseriesQueries[0], "oldest-time=2023-06-01T00%3A00%3A00Z")
+	assert.Contains(t, timeseriesQueries[0], "youngest-time=2023-06-02T00%3A00%3A00Z")
+
+	timeseriesStatus = http.StatusBadRequest
+	diags := create()
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Stream query failed to validate over validate_time_range", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "invalid query")
+	assert.Equal(t, cty.GetAttrPath("validate_time_range").IndexInt(0), diags[0].AttributePath)
+
+	// a failing update is rejected before the stream is changed
+	r := resourceStream()
+	state := &terraform.InstanceState{ID: "s1", Attributes: map[string]string{
+		"id":                    "s1",
+		"project_name":          "tacoman",
+		"stream_name":           "Checkout",
+		"query":                 `service IN ("checkout")`,
+		"validate_time_range.#": "0",
+	}}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_name": "tacoman",
+		"stream_name":  "Checkout Service",
+		"query":        `service IN ("checkout")`,
+		"validate_time_range": []interface{}{map[string]interface{}{
+			"start": "2023-06-01T00:00:00Z",
+			"end":   "2023-06-02T00:00:00Z",
+		}},
+	}), meta)
+	require.NoError(t, err)
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	require.NoError(t, err)
+	require.True(t, resourceStreamUpdate(context.Background(), d, meta).HasError())
+	assert.Zero(t, updates)
+}
+
+func TestValidateStreamTimeRange(t *testing.T) {
+	diff := func(start, end string) error {
+		_, err := resourceStream().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_name":        "p",
+			"stream_name":         "s",
+			"query":               `service IN ("api")`,
+			"validate_time_range": []interface{}{map[string]interface{}{"start": start, "end": end}},
+		}), nil)
+		return err
+	}
+
+	require.NoError(t, diff("2023-06-01T00:00:00Z", "2023-06-02T00:00:00Z"))
+	assert.ErrorContains(t, diff("2023-06-02T00:00:00Z", "2023-06-01T00:00:00Z"), "validate_time_range: end 2023-06-01T00:00:00Z must be after start")
+}
+
+func TestAccStreamValidateTimeRange(t *testing.T) {
+	var stream client.Stream
+
+	config := func(end string) string {
+		return `
+resource "lightstep_stream" "validated" {
+  project_name = "` + testProject + `"
+  stream_name  = "Validated Stream"
+  query        = "service IN (\"api\")"
+
+  validate_time_range {
+    start = "` + time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339) + `"
+    end   = "` + end + `"
+  }
+}
+`
+	}
+
+	resourceName := "lightstep_stream.validated"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+					resource.TestCheckResourceAttr(resourceName, "validate_time_range.#", "1"),
+				),
+			},
+			{
+				Config:      config(time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)),
+				ExpectError: regexp.MustCompile("must be after start"),
+			},
+		},
+	})
+}
+
+func TestStreamUpdateRetention(t *testing.T) {
+	var patches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/v0.2/blars/projects/tacoman/streams/s1", r.URL.Path)
+		if r.Method == http.MethodPatch {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			patches = append(patches, string(body))
+		}
+		_, err := w.Write([]byte(`{"data": {"id": "s1", "attributes": {"name": "Checkout", "query": "service IN (\"checkout\")", "retention": "30d"}}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Setenv("LIGHTSTEP_API_BASE_URL", server.URL)
+	t.Setenv("LS_DISABLE_RATE_LIMIT", "true")
+	meta := &providerMeta{client: client.NewClient("api", "blars", "staging")}
+
+	update := func(config map[string]interface{}) {
+		r := resourceStream()
+		state := &terraform.InstanceState{
+			ID: "s1",
+			Attributes: map[string]string{
+				"project_name": "tacoman",
+				"stream_name":  "Checkout",
+				"query":        `service IN ("checkout")`,
+				"retention":    "30d",
+			},
+		}
+		config["project_name"] = "tacoman"
+		config["query"] = `service IN ("checkout")`
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		require.NoError(t, err)
+		require.False(t, resourceStreamUpdate(context.Background(), d, meta).HasError())
+	}
+
+	update(map[string]interface{}{"stream_name": "Checkout v2"})
+	update(map[string]interface{}{"stream_name": "Checkout", "retention": "never"})
+
+	require.Len(t, patches, 2)
+	assert.NotContains(t, patches[0], "retention", "an unchanged retention isn't sent")
+	assert.Contains(t, patches[1], `"retention":"never"`)
+}
+
+func TestAccStreamColor(t *testing.T) {
+	var stream client.Stream
+
+	config := func(color string) string {
+		return `
+resource "lightstep_stream" "colored" {
+  project_name = "` + testProject + `"
+  stream_name  = "Colored Stream"
+  query        = "service IN (\"api\")"
+  ` + color + `
+}
+`
+	}
+
+	resourceName := "lightstep_stream.colored"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				// the color picked by Lightstep doesn't cause a diff
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+				),
+			},
+			{
+				Config: config(`color = "#3c6fd8"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+					resource.TestCheckResourceAttr(resourceName, "color", "#3c6fd8"),
+				),
+			},
+			{
+				// the same color in another case isn't a change
+				Config:   config(`color = "#3C6FD8"`),
+				PlanOnly: true,
+			},
+			{
+				Config: config(`color = "green"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists(resourceName, &stream),
+					resource.TestCheckResourceAttr(resourceName, "color", "green"),
+				),
+			},
+			{
+				// removing the color keeps the current one
+				Config:   config(""),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStreamQueryInterpolation(t *testing.T) {
+	var stream client.Stream
+
+	// the query of the second stream is built from an attribute of the first, so
+	// it is unknown at plan time and only validated once interpolated at apply
+	interpolatedQuery := `
+resource "lightstep_stream" "base" {
+  project_name = "` + testProject + `"
+  stream_name = "frontend"
+  query = "service IN (\"frontend\")"
+}
+
+resource "lightstep_stream" "interpolated" {
+  project_name = "` + testProject + `"
+  stream_name = "Errors (${lightstep_stream.base.stream_name})"
+  query = "service IN (\"${lightstep_stream.base.stream_name}\") AND \"error\" IN (\"true\")"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: interpolatedQuery,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStreamExists("lightstep_stream.interpolated", &stream),
+					resource.TestCheckResourceAttr("lightstep_stream.interpolated", "stream_name", "Errors (frontend)"),
+					resource.TestCheckResourceAttr("lightstep_stream.interpolated", "query", "service IN (\"frontend\") AND \"error\" IN (\"true\")"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateStreamQuery(t *testing.T) {
+	cases := []struct {
+		query     string
+		expectErr bool
+	}{
+		{query: `service IN ("api")`, expectErr: false},
+		{query: `service IN ("api") AND "error" IN ("true")`, expectErr: false},
+		// parentheses inside quoted values are ignored
+		{query: `operation IN ("GET (v1")`, expectErr: false},
+		// escaped quotes don't terminate a quoted value
+		{query: `operation IN ("say \"hi\"")`, expectErr: false},
+		{query: ``, expectErr: true},
+		{query: `   `, expectErr: true},
+		{query: `service IN ("api"`, expectErr: true},
+		{query: `service IN "api")`, expectErr: true},
+		{query: `service IN ("api)`, expectErr: true},
+		{query: `"customer_id" NOT IN ("test0")`, expectErr: false},
+		{query: `service in ("api")`, expectErr: false},
+		// the form of the query is only checked for lightstep_stream
+		{query: `error = true`, expectErr: false},
+		{query: `service`, expectErr: false},
+	}
+
+	for _, c := range cases {
+		err := validateStreamQuery(c.query)
+		if c.expectErr {
+			require.Error(t, err, c.query)
+		} else {
+			require.NoError(t, err, c.query)
+		}
+	}
+}
+
+func TestValidateStreamResourceQuery(t *testing.T) {
+	cases := []struct {
+		query     string
+		expectErr bool
+	}{
+		{query: `service IN ("api")`, expectErr: false},
+		{query: `"customer_id" NOT IN ("test0")`, expectErr: false},
+		// operators inside quoted values are ignored
+		{query: `"http.url" IN ("/search?q=1")`, expectErr: false},
+		// comparisons are left to the server
+		{query: `service IN ("api") AND latency >= 100`, expectErr: false},
+		{query: `service IN ("api") AND latency < 100`, expectErr: false},
+		{query: `service IN ("api"`, expectErr: true},
+		{query: `error = true`, expectErr: true},
+		{query: `error == true`, expectErr: true},
+		{query: `service IN ("api") AND error != "true"`, expectErr: true},
+		{query: `service IN "api"`, expectErr: true},
+	}
+
+	for _, c := range cases {
+		err := validateStreamResourceQuery(c.query)
+		if c.expectErr {
+			require.Error(t, err, c.query)
+		} else {
+			require.NoError(t, err, c.query)
+		}
+	}
+}
+
+func TestValidateStreamQueryField(t *testing.T) {
+	_, errs := validateStreamQueryField(`service IN ("api")`, "query")
+	assert.Empty(t, errs)
+
+	_, errs = validateStreamQueryField("error = true", "query")
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `unexpected "=" in query "error = true"`)
+}
+
+func TestSuppressEquivalentStreamQuery(t *testing.T) {
+	query := `service IN ("api", "web") AND "error" IN ("true")`
+	for _, equivalent := range []string{
+		query,
+		"  " + query + "\n",
+		`service  IN ("api", "web")  AND "error" IN ("true")`,
+		`service IN ( "api" , "web" ) AND "error" IN ("true")`,
+		`service IN ("api","web") AND "error" IN ("true")`,
+		"service\tIN (\"api\", \"web\")\nAND \"error\" IN (\"true\")",
+	} {
+		require.True(t, suppressEquivalentStreamQuery("query", query, equivalent, nil), equivalent)
+	}
+
+	for _, different := range []string{
+		// whitespace inside quoted values is significant
+		`service IN (" api", "web") AND "error" IN ("true")`,
+		`service IN ("api", "web ") AND "error" IN ("true")`,
+		`service IN ("api", "web") AND "error" IN ("true ")`,
+		// so are the tokens it separates
+		`service IN ("api", "web") AND"error" IN ("true")`,
+		`service IN ("api", "web") AND "error" IN ("false")`,
+	} {
+		require.False(t, suppressEquivalentStreamQuery("query", query, different, nil), different)
+	}
+
+	// escaped quotes don't end a quoted value
+	require.False(t, suppressEquivalentStreamQuery("query", `operation IN ("say \" hi")`, `operation IN ("say \"hi")`, nil))
+}
+
+func TestValidateStreamRetention(t *testing.T) {
+	for _, valid := range []string{"never", "30d", "1d", "720h", "90m"} {
+		_, errs := validateStreamRetention(valid, "retention")
+		require.Empty(t, errs, valid)
+	}
+	for _, invalid := range []string{"", "forever", "0d", "-1d", "1.5d", "0s", "-1h", "30"} {
+		_, errs := validateStreamRetention(invalid, "retention")
+		require.Len(t, errs, 1, invalid)
+	}
+
+	require.True(t, suppressEquivalentRetention("retention", "30d", "720h", nil))
+	require.True(t, suppressEquivalentRetention("retention", "", "never", nil))
+	require.False(t, suppressEquivalentRetention("retention", "30d", "never", nil))
+	require.False(t, suppressEquivalentRetention("retention", "30d", "31d", nil))
+}
+
+func TestValidateStreamColor(t *testing.T) {
+	for _, valid := range []string{"#3c6fd8", "#3C6FD8", "#fff", "blue", "Gray"} {
+		_, errs := validateStreamColor(valid, "color")
+		require.Empty(t, errs, valid)
+	}
+	for _, invalid := range []string{"", "3c6fd8", "#3c6fd", "#3c6fdz", "#3c6fd8ff", "magenta"} {
+		_, errs := validateStreamColor(invalid, "color")
+		require.Len(t, errs, 1, invalid)
+	}
+
+	require.True(t, suppressEquivalentStreamColor("color", "#3c6fd8", "#3C6FD8", nil))
+	require.True(t, suppressEquivalentStreamColor("color", "blue", "Blue", nil))
+	require.False(t, suppressEquivalentStreamColor("color", "blue", "#3c6fd8", nil))
+}
+
+func TestValidateCustomDataURL(t *testing.T) {
+	cases := []struct {
+		customData map[string]interface{}
+		expectErr  bool
+	}{
+		{customData: map[string]interface{}{"name": "runbook", "url": "https://lightstep.atlassian.net/l/c/M7b0rBsj"}},
+		{customData: map[string]interface{}{"name": "runbook", "url": "http://localhost:8080/path?q=1"}},
+		// entries without a url aren't validated
+		{customData: map[string]interface{}{"name": "owner", "team": "not a url"}},
+		{customData: map[string]interface{}{"name": "runbook", "url": "www.lightstep.com"}, expectErr: true},
+		{customData: map[string]interface{}{"name": "runbook", "url": "/relative/path"}, expectErr: true},
+		{customData: map[string]interface{}{"name": "runbook", "url": "ftp://lightstep.com/file"}, expectErr: true},
+		{customData: map[string]interface{}{"name": "runbook", "url": "https://"}, expectErr: true},
+		{customData: map[string]interface{}{"name": "runbook", "url": "https://bad host.com"}, expectErr: true},
+		{customData: map[string]interface{}{"name": "runbook", "url": ""}, expectErr: true},
+	}
+
+	for _, c := range cases {
+		_, errs := validateCustomDataURL(c.customData, "custom_data.0")
+		if c.expectErr {
+			require.Len(t, errs, 1, c.customData)
+		} else {
+			require.Empty(t, errs, c.customData)
+		}
+	}
+}
+
+func TestFlattenStreamCustomDataOrder(t *testing.T) {
+	var s client.Stream
+	s.Attributes.CustomDataGet = map[string]map[string]string{
+		"runbook":   {"url": "https://www.lightstep.com/runbook"},
+		"dashboard": {"url": "https://www.lightstep.com/dashboard"},
+		"alerts":    {"key": "value"},
+	}
+
+	customData := flattenStreamCustomData(s)
+	require.Len(t, customData, 3)
+	assert.Equal(t, "alerts", customData[0]["name"])
+	assert.Equal(t, "dashboard", customData[1]["name"])
+	assert.Equal(t, "runbook", customData[2]["name"])
+
+	ordered := []interface{}{
+		map[string]interface{}{"name": "dashboard", "url": "https://www.lightstep.com/dashboard"},
+		map[string]interface{}{"name": "runbook", "url": "https://www.lightstep.com/runbook"},
+	}
+	reordered := []interface{}{ordered[1], ordered[0]}
+	assert.Equal(t, sortedCustomData(ordered), sortedCustomData(reordered))
+}
+
+func TestValidateStreamQueryDiff(t *testing.T) {
+	diff := func(query string) error {
+		_, err := resourceStream().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_name": "p",
+			"stream_name":  "s",
+			"query":        query,
+		}), nil)
+		return err
+	}
+
+	require.NoError(t, diff(`service IN ("api")`))
+	assert.ErrorContains(t, diff(`service IN ("api"`), "invalid stream query: unbalanced parentheses")
+	assert.ErrorContains(t, diff(`error == true`), `invalid stream query: unexpected "==" in query`)
+}
+
+func TestStreamCustomDataURLPlanDiagnostic(t *testing.T) {
+	diags := resourceStream().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_name": "p",
+		"stream_name":  "s",
+		"query":        `service IN ("api")`,
+		"custom_data": []interface{}{
+			map[string]interface{}{"name": "owner", "team": "api"},
+			map[string]interface{}{"name": "runbook", "url": "not-a-url"},
+		},
+	}))
+
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, `custom_data.1.url to be an absolute http or https URL, got "not-a-url"`)
+}
+
 func testAccCheckStreamExists(resourceName string, stream *client.Stream) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		// get stream from TF state
@@ -186,7 +984,7 @@ func testAccCheckStreamExists(resourceName string, stream *client.Stream) resour
 		}
 
 		// get stream from LS
-		client := testAccProvider.Meta().(*client.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		str, err := client.GetStream(context.Background(), testProject, tfStream.Primary.ID)
 		if err != nil {
 			return err
@@ -200,20 +998,20 @@ func testAccCheckStreamExists(resourceName string, stream *client.Stream) resour
 
 // confirms that streams created during test run have been destroyed
 func testAccStreamDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 
 	for _, resource := range s.RootModule().Resources {
-		if resource.Type != "stream" {
+		if resource.Type != "lightstep_stream" {
 			continue
 		}
 
-		s, err := conn.GetStream(context.Background(), testProject, resource.Primary.ID)
-		if err == nil {
-			if s.ID == resource.Primary.ID {
-				return fmt.Errorf("stream with ID (%v) still exists.", resource.Primary.ID)
-			}
+		exists, err := conn.StreamExists(context.Background(), testProject, resource.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("could not check whether stream with ID (%v) was destroyed: %v", resource.Primary.ID, err)
+		}
+		if exists {
+			return fmt.Errorf("stream with ID (%v) still exists.", resource.Primary.ID)
 		}
-
 	}
 
 	return nil
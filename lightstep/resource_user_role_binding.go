@@ -123,7 +123,7 @@ func setUserRoleBindingFromResource(d *schema.ResourceData, userRoleBinding clie
 }
 
 func resourceUserRoleBindingCreateOrUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	// Read the proposed plan
 	userRoleBinding := getUserRoleBindingFromResource(ctx, d)
@@ -148,7 +148,7 @@ func resourceUserRoleBindingCreateOrUpdate(ctx context.Context, d *schema.Resour
 // When called by a Create or Update context, it will read data from the terraform plan.
 func resourceUserRoleBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	// Read the resource from data.
 	userRoleBinding := getUserRoleBindingFromResource(ctx, d)
@@ -169,14 +169,14 @@ func resourceUserRoleBindingRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceUserRoleBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	// Read from the terraform state
 	userRoleBinding := getUserRoleBindingFromResource(ctx, d)
 
 	// Update role binding with no users, this will remove this role from all users of this org for the given project.
 	_, err := c.UpdateRoleBinding(ctx, userRoleBinding.ProjectName, userRoleBinding.RoleName)
-	if err != nil {
+	if err != nil && !errorIsNotFound(err) {
 		return handleAPIError(err, d, "delete user role binding")
 	}
 
@@ -187,7 +187,7 @@ func resourceUserRoleBindingDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceUserRoleBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), "/")
 	if len(ids) < 2 {
@@ -56,7 +56,7 @@ func resourceWebhookDestination() *schema.Resource {
 }
 
 func resourceWebhookDestinationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	dest := client.Destination{
 		Type: "destination",
@@ -88,7 +88,7 @@ func resourceWebhookDestinationCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceWebhookDestinationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(*client.Client)
+	c := m.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ".")
 	if len(ids) != 2 {
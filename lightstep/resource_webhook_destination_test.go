@@ -98,7 +98,7 @@ func testAccCheckWebhookDestinationExists(resourceName string, destination *clie
 		}
 
 		// get destination from LS
-		client := testAccProvider.Meta().(*client.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		d, err := client.GetDestination(context.Background(), testProject, tfDestination.Primary.ID)
 		if err != nil {
 			return err
@@ -110,7 +110,7 @@ func testAccCheckWebhookDestinationExists(resourceName string, destination *clie
 }
 
 func testAccWebhookDestinationDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*client.Client)
+	conn := testAccProvider.Meta().(*providerMeta).client
 	for _, resource := range s.RootModule().Resources {
 		if resource.Type != "lightstep_webhook_destination" {
 			continue